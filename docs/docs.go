@@ -15,9 +15,95 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Public keys other services verify access tokens with; pick the key by the token's kid header. Empty while tokens are signed with a shared secret.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Token verification keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_user_auth.JWKS"
+                        }
+                    }
+                }
+            }
+        },
+        "/analytics/volume": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sums completed sets, reps and tonnage (kg) per week or month, split by muscle group or workout type",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analytics"
+                ],
+                "summary": "Training volume per period",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date YYYY-MM-DD, inclusive (defaults to 12 weeks ago)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date YYYY-MM-DD, inclusive (defaults to today)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Period: week (default) or month",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Grouping: muscle_group (default) or workout_type",
+                        "name": "by",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_analytics.volumeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
         "/auth/login": {
             "post": {
-                "description": "Authenticates user credentials and returns JWT pair",
+                "description": "Authenticates user credentials and returns JWT pair. Users with two-factor authentication get an mfaChallengeResponse instead,\nto be completed at /auth/mfa/verify. Answers 403 for unverified e-mail addresses when verification is required,\nand 429 with a Retry-After header while the account or client address is locked out after repeated failures.",
                 "consumes": [
                     "application/json"
                 ],
@@ -57,6 +143,18 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/gin.H"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
                     }
                 }
             }
@@ -68,49 +166,29 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "tags": [
-                    "auth"
-                ],
-                "summary": "Logout (client-side token discard)",
-                "responses": {
-                    "204": {
-                        "description": "No Content",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/auth/refresh": {
-            "post": {
-                "description": "Exchanges a refresh token for a new JWT pair",
+                "description": "Revokes the refresh tokens of the given login, or of all logins when no refresh token is sent",
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
                     "auth"
                 ],
-                "summary": "Refresh JWT tokens",
+                "summary": "Logout",
                 "parameters": [
                     {
                         "description": "Refresh token",
                         "name": "payload",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.refreshRequest"
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.logoutRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content",
                         "schema": {
-                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.tokenResponse"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -124,11 +202,17 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/gin.H"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
                     }
                 }
             }
         },
-        "/muscle-groups": {
+        "/auth/mfa": {
             "get": {
                 "security": [
                     {
@@ -139,22 +223,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "muscle-groups"
+                    "auth"
                 ],
-                "summary": "List muscle groups",
+                "summary": "Two-factor authentication status",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.MuscleGroup"
-                            }
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.mfaStatusResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
                         }
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -163,29 +256,26 @@ const docTemplate = `{
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "muscle-groups"
+                    "auth"
                 ],
-                "summary": "Create muscle group",
+                "summary": "Disable two-factor authentication",
                 "parameters": [
                     {
-                        "description": "Muscle group",
+                        "description": "Current password",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.muscleGroupRequest"
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.mfaDisableRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "204": {
+                        "description": "No Content",
                         "schema": {
-                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.MuscleGroup"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -194,6 +284,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/gin.H"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -203,34 +305,37 @@ const docTemplate = `{
                 }
             }
         },
-        "/muscle-groups/{id}": {
-            "get": {
+        "/auth/mfa/confirm": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "produces": [
+                "description": "Enables two-factor authentication with a first code from the authenticator app",
+                "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "muscle-groups"
+                    "auth"
                 ],
-                "summary": "Get muscle group by ID",
+                "summary": "Confirm TOTP enrollment",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "MuscleGroup ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "TOTP code",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.mfaConfirmRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content",
                         "schema": {
-                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.MuscleGroup"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -239,20 +344,35 @@ const docTemplate = `{
                             "$ref": "#/definitions/gin.H"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/gin.H"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/auth/mfa/enroll": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Checks the current password and returns a new TOTP secret, its otpauth URI and single-use recovery codes. Two-factor authentication is enabled by /auth/mfa/confirm.",
                 "consumes": [
                     "application/json"
                 ],
@@ -260,24 +380,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "muscle-groups"
+                    "auth"
                 ],
-                "summary": "Update muscle group",
+                "summary": "Start TOTP enrollment",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "MuscleGroup ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Update",
+                        "description": "Current password",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.muscleGroupRequest"
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.mfaEnrollRequest"
                         }
                     }
                 ],
@@ -285,7 +398,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.MuscleGroup"
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.mfaEnrollResponse"
                         }
                     },
                     "400": {
@@ -294,42 +407,26 @@ const docTemplate = `{
                             "$ref": "#/definitions/gin.H"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/gin.H"
                         }
-                    }
-                }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "tags": [
-                    "muscle-groups"
-                ],
-                "summary": "Delete muscle group",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "MuscleGroup ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content",
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/gin.H"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/gin.H"
                         }
@@ -337,114 +434,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/suggest-workout": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+        "/auth/mfa/verify": {
+            "post": {
+                "description": "Exchanges the mfa_token returned by /auth/login and a TOTP or recovery code for a JWT pair",
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "workout-suggestions"
-                ],
-                "summary": "Suggest next workout",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.suggestionResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.errorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/users": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "users"
+                    "auth"
                 ],
-                "summary": "List users",
+                "summary": "Complete a two-factor login",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Offset",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Challenge and code",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.mfaVerifyRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_user_models.User"
-                            }
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.tokenResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/gin.H"
                         }
-                    }
-                }
-            },
-            "post": {
-                "description": "Creates a user and returns the stored record",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "users"
-                ],
-                "summary": "Register new user",
-                "parameters": [
-                    {
-                        "description": "User info",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.createUserRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_user_models.User"
+                            "$ref": "#/definitions/gin.H"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "$ref": "#/definitions/gin.H"
                         }
@@ -458,62 +492,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/me": {
+        "/auth/oidc/providers": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Returns the authenticated user's information",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "auth"
                 ],
-                "summary": "Get current user",
+                "summary": "List identity providers",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_user_models.User"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/gin.H"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/gin.H"
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.providersResponse"
                         }
                     }
                 }
             }
         },
-        "/users/{id}": {
+        "/auth/oidc/{provider}/authorize": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
+                "description": "Returns the provider's authorization URL for the authorization code flow with PKCE",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "auth"
                 ],
-                "summary": "Get user by ID",
+                "summary": "Start sign-in with an identity provider",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Provider name, e.g. google or apple",
+                        "name": "provider",
                         "in": "path",
                         "required": true
                     }
@@ -522,54 +534,53 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_user_models.User"
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.authorizeResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/gin.H"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "502": {
+                        "description": "Bad Gateway",
                         "schema": {
                             "$ref": "#/definitions/gin.H"
                         }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
+            }
+        },
+        "/auth/oidc/{provider}/callback": {
+            "post": {
+                "description": "Exchanges the code and state from the provider's redirect for a JWT pair, or an mfaChallengeResponse for users\nwith two-factor authentication. The first sign-in links the provider account to the user with the same e-mail\naddress, when both the provider and the account have verified it, or creates a new user without a password.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/x-www-form-urlencoded"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "auth"
                 ],
-                "summary": "Update user",
+                "summary": "Finish sign-in with an identity provider",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Provider name",
+                        "name": "provider",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Update information",
+                        "description": "Code and state",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.updateUserRequest"
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.socialCallbackRequest"
                         }
                     }
                 ],
@@ -577,7 +588,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_user_models.User"
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.tokenResponse"
                         }
                     },
                     "400": {
@@ -586,12 +597,30 @@ const docTemplate = `{
                             "$ref": "#/definitions/gin.H"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/gin.H"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -599,29 +628,32 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+            }
+        },
+        "/auth/password/forgot": {
+            "post": {
+                "description": "Mails a single-use reset link valid for one hour. The response is the same whether or not the address is registered.\nAn account is mailed at most one link a minute; further requests are ignored.",
+                "consumes": [
+                    "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "auth"
                 ],
-                "summary": "Delete user",
+                "summary": "Request a password reset",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Account e-mail",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.forgotPasswordRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
                             "type": "string"
                         }
@@ -631,88 +663,70 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/gin.H"
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/gin.H"
-                        }
                     }
                 }
             }
         },
-        "/workout-sessions": {
-            "get": {
+        "/auth/password/link": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Mails the signed-in user a single-use link to set a password. Accounts created through social login have none\nand need one before changing the e-mail address or password, managing two-factor authentication or deleting the account.",
                 "tags": [
-                    "workout-sessions"
-                ],
-                "summary": "List workout sessions for user",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Offset",
-                        "name": "offset",
-                        "in": "query"
-                    }
+                    "auth"
                 ],
+                "summary": "Mail a password link to the current user",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutSession"
-                            }
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/password/reset": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
+                "description": "Sets a new password using the token from the reset e-mail and signs out every session",
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "workout-sessions"
+                    "auth"
                 ],
-                "summary": "Create workout session",
+                "summary": "Reset password",
                 "parameters": [
                     {
-                        "description": "Session",
+                        "description": "Reset token and new password",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.workoutSessionRequest"
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.resetPasswordRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "204": {
+                        "description": "No Content",
                         "schema": {
-                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutSession"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -730,34 +744,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/workout-sessions/{id}": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+        "/auth/refresh": {
+            "post": {
+                "description": "Exchanges a refresh token for a new JWT pair. The presented token is rotated and must not be used again:\nreusing it revokes every token issued from the same login.",
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "workout-sessions"
+                    "auth"
                 ],
-                "summary": "Get workout session by ID",
+                "summary": "Refresh JWT tokens",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "WorkoutSession ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Refresh token",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.refreshRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutSession"
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.tokenResponse"
                         }
                     },
                     "400": {
@@ -766,28 +781,71 @@ const docTemplate = `{
                             "$ref": "#/definitions/gin.H"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/gin.H"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the devices the user is signed in on",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "List login sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/fitness-tracker-backend_user_handler.sessionResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions/{id}": {
             "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Revokes a login session; its refresh and access tokens stop working immediately",
                 "tags": [
-                    "workout-sessions"
+                    "auth"
                 ],
-                "summary": "Delete workout session",
+                "summary": "Sign out a device",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "WorkoutSession ID",
+                        "description": "Session ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -805,50 +863,52 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/gin.H"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
                     }
                 }
             }
         },
-        "/workout-sessions/{id}/details": {
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
+        "/auth/verify": {
+            "get": {
+                "description": "Confirms the address the token was mailed to. Access tokens issued before carry the old state; refresh them to unlock verified-only features.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "workout-sessions"
+                    "auth"
                 ],
-                "summary": "Add detail to workout session",
+                "summary": "Verify e-mail address",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "WorkoutSession ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Token from the verification e-mail",
+                        "name": "token",
+                        "in": "query",
                         "required": true
-                    },
-                    {
-                        "description": "Detail",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.workoutDetailRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutDetail"
+                            "$ref": "#/definitions/gin.H"
                         }
                     },
                     "400": {
@@ -857,14 +917,45 @@ const docTemplate = `{
                             "$ref": "#/definitions/gin.H"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/gin.H"
                         }
+                    }
+                }
+            }
+        },
+        "/auth/verify/resend": {
+            "post": {
+                "description": "Mails a new verification link and invalidates earlier ones. The response is the same for unknown or already verified addresses.\nAn account is mailed at most one link a minute; further requests are ignored.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Resend verification e-mail",
+                "parameters": [
+                    {
+                        "description": "Account e-mail",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.resendVerificationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "string"
+                        }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/gin.H"
                         }
@@ -872,28 +963,49 @@ const docTemplate = `{
                 }
             }
         },
-        "/workout-types": {
+        "/coach/threads": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
                     }
                 ],
+                "description": "Most recently active first, without messages",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "workout-types"
+                    "coach"
+                ],
+                "summary": "List coach conversations of the user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
                 ],
-                "summary": "List workout types",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutType"
-                            }
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_shared_pagination.Page-github_com_VibeTeam_fitness-tracker-backend_workout_models_CoachThread"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
                         }
                     }
                 }
@@ -902,6 +1014,9 @@ const docTemplate = `{
                 "security": [
                     {
                         "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
                     }
                 ],
                 "consumes": [
@@ -911,17 +1026,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "workout-types"
+                    "coach"
                 ],
-                "summary": "Create workout type",
+                "summary": "Start a conversation with the AI coach",
                 "parameters": [
                     {
-                        "description": "Workout type",
+                        "description": "Title",
                         "name": "payload",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.workoutTypeRequest"
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.coachThreadRequest"
                         }
                     }
                 ],
@@ -929,7 +1043,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutType"
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.CoachThread"
                         }
                     },
                     "400": {
@@ -947,24 +1061,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/workout-types/{id}": {
+        "/coach/threads/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
                     }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "workout-types"
+                    "coach"
                 ],
-                "summary": "Get workout type by ID",
+                "summary": "Get coach conversation with its messages",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "WorkoutType ID",
+                        "description": "CoachThread ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -974,7 +1091,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutType"
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.CoachThread"
                         }
                     },
                     "400": {
@@ -991,45 +1108,33 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "workout-types"
+                    "coach"
                 ],
-                "summary": "Update workout type",
+                "summary": "Delete coach conversation",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "WorkoutType ID",
+                        "description": "CoachThread ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Update",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.workoutTypeRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content",
                         "schema": {
-                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutType"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -1045,31 +1150,53 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/coach/threads/{id}/messages": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
                     }
                 ],
+                "description": "Answers with the coach's reply, generated from the conversation and the user's recent sessions. The oldest\nmessages are left out once the conversation outgrows the model's context window. The message and the reply\nare stored together once the reply is complete; nothing is stored when it fails or the client disconnects.\nWith Accept: text/event-stream the reply is streamed as \"chunk\" events with {\"text\"}, followed by a \"done\"\nevent with the stored reply or an \"error\" event with {\"error\"}.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/event-stream"
+                ],
                 "tags": [
-                    "workout-types"
+                    "coach"
                 ],
-                "summary": "Delete workout type",
+                "summary": "Ask the AI coach",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "WorkoutType ID",
+                        "description": "CoachThread ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Message",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.coachMessageRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.CoachMessage"
                         }
                     },
                     "400": {
@@ -1077,221 +1204,5110 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/gin.H"
                         }
-                    }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "fitness-tracker-backend_user_handler.createUserRequest": {
-            "type": "object",
-            "required": [
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/muscle-groups": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "muscle-groups"
+                ],
+                "summary": "List muscle groups",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_shared_pagination.Page-github_com_VibeTeam_fitness-tracker-backend_workout_models_MuscleGroup"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "muscle-groups"
+                ],
+                "summary": "Create muscle group",
+                "parameters": [
+                    {
+                        "description": "Muscle group",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.muscleGroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.MuscleGroup"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/muscle-groups/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "muscle-groups"
+                ],
+                "summary": "Get muscle group by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "MuscleGroup ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.MuscleGroup"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "muscle-groups"
+                ],
+                "summary": "Update muscle group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "MuscleGroup ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.muscleGroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.MuscleGroup"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "muscle-groups"
+                ],
+                "summary": "Delete muscle group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "MuscleGroup ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/programs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "programs"
+                ],
+                "summary": "List training programs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_shared_pagination.Page-github_com_VibeTeam_fitness-tracker-backend_workout_models_Program"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "programs"
+                ],
+                "summary": "Create training program",
+                "parameters": [
+                    {
+                        "description": "Program with weeks, days and prescriptions",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.programRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.Program"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/programs/enrollments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "programs"
+                ],
+                "summary": "List program enrollments of the user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_shared_pagination.Page-github_com_VibeTeam_fitness-tracker-backend_workout_models_ProgramEnrollment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/programs/enrollments/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "programs"
+                ],
+                "summary": "Leave a training program",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ProgramEnrollment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/programs/enrollments/{id}/adherence": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Counts scheduled days up to the given date and how many have a linked session",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "programs"
+                ],
+                "summary": "Program adherence of an enrollment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ProgramEnrollment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Calendar date YYYY-MM-DD (defaults to today)",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.adherenceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/programs/enrollments/{id}/complete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "programs"
+                ],
+                "summary": "Link a logged session to a scheduled program day",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ProgramEnrollment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Session to link",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.completeDayRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutSession"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/programs/enrollments/{id}/today": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "programs"
+                ],
+                "summary": "Get today's scheduled workout of an enrollment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ProgramEnrollment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Calendar date YYYY-MM-DD (defaults to today)",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.todayResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/programs/enrollments/{id}/today/start": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a session pre-filled from today's prescriptions and links it to the scheduled day.\nPercentage prescriptions are turned into weights from the user's estimated one-rep max.\nAPI keys need the sessions:write scope besides programs:write.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "programs"
+                ],
+                "summary": "Start today's scheduled workout",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ProgramEnrollment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Calendar date YYYY-MM-DD (defaults to today)",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutSession"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/programs/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "programs"
+                ],
+                "summary": "Get training program by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Program ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.Program"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the week/day/prescription tree; only the author may update. Weeks and days are matched by\nnumber and keep their IDs, so sessions linked to a day stay linked; days left out are removed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "programs"
+                ],
+                "summary": "Update training program",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Program ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Program",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.programRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.Program"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Only the author can delete a program. Every enrollment in it ends, including other users'; sessions logged\nfor it are kept.",
+                "tags": [
+                    "programs"
+                ],
+                "summary": "Delete training program",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Program ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/programs/{id}/enroll": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "programs"
+                ],
+                "summary": "Enroll in a training program",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Program ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Start date",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.enrollRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.ProgramEnrollment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/suggest-workout": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Answers with JSON once the whole suggestion is generated. Clients sending Accept: text/event-stream get the\nstream of /suggest-workout/stream instead. When the language model fails or times out, the rule engine\nsuggests training the muscle group that has rested longest, and engine is \"rules\".",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workout-suggestions"
+                ],
+                "summary": "Suggest next workout",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.suggestionResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/suggest-workout/plan": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Asks the model for exercises from the workout type catalog with sets, reps, target weights and a rationale.\nThe answer is validated and repaired server-side and every exercise carries its workout_type_id. When the\nlanguage model fails, times out or produces no valid plan, the rule engine plans instead.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workout-suggestions"
+                ],
+                "summary": "Suggest next workout as a structured plan",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.workoutPlanResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "No workout types exist yet",
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/suggest-workout/plan/start": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a draft session with the plan's exercises and planned sets, like starting a template. Needs the\nsessions:write scope in addition to suggestions:read when called with an API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workout-suggestions"
+                ],
+                "summary": "Start a workout session from a suggested plan",
+                "parameters": [
+                    {
+                        "description": "Plan from /suggest-workout/plan; datetime defaults to now",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.startPlanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutSession"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/suggest-workout/stream": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Server-sent events: a \"chunk\" event with {\"text\"} for every piece of the suggestion as it is generated, then\na \"done\" event with the summary below, or an \"error\" event with {\"error\"} if generation fails midway. The rule\nengine takes over when the language model fails before sending any text.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "workout-suggestions"
+                ],
+                "summary": "Stream next workout suggestion",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.suggestionSummary"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List users (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_shared_pagination.Page-github_com_VibeTeam_fitness-tracker-backend_user_models_User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a user and returns the stored record. When e-mail verification is enabled a confirmation link is mailed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Register new user",
+                "parameters": [
+                    {
+                        "description": "User info",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.createUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_user_models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the authenticated user's information",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get current user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_user_models.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes the authenticated user's account together with all workout history after confirming the password.\nPrograms the user authored are deleted too, which ends other users' enrollments in them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete current user",
+                "parameters": [
+                    {
+                        "description": "Password confirmation",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.deleteMeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the authenticated user's own profile. Changing the e-mail address or the password requires\ncurrent_password; a new e-mail address has to be verified again and a new password signs out other devices.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update current user",
+                "parameters": [
+                    {
+                        "description": "Fields to change",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.updateMeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_user_models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "List API keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/fitness-tracker-backend_user_handler.apiKeyResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a long-lived key for scripts and devices. Scopes: sessions:read, sessions:write, templates:read,\ntemplates:write, programs:read, programs:write, catalog:read, catalog:write, suggestions:read, coach:read\nand coach:write.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Create API key",
+                "parameters": [
+                    {
+                        "description": "Name, scopes and optional expiry",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.createAPIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.createdAPIKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/api-keys/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Get API key",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.apiKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes the key; requests made with it fail immediately",
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Delete API key",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Renames a key or replaces its scopes; omitted fields are kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Update API key",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.updateAPIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.apiKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/records": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "One record per workout type and kind (heaviest_weight, most_reps per weight, estimated_1rm, volume)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "records"
+                ],
+                "summary": "List current personal records of the user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_shared_pagination.Page-github_com_VibeTeam_fitness-tracker-backend_workout_models_PersonalRecord"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user by ID (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_user_models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates any account, including its role (user, coach or admin). A new e-mail address has to be verified\nagain and a new password signs the user out everywhere.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update user (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update information",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_user_handler.updateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_user_models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes the account together with all of its workout history. Programs the user authored are deleted\ntoo, which ends other users' enrollments in them.",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete user (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/workout-sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workout-sessions"
+                ],
+                "summary": "List workout sessions for user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sessions on or after this date (YYYY-MM-DD or RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sessions before the end of this date (YYYY-MM-DD) or before this instant (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sessions containing this workout type",
+                        "name": "workout_type_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sessions training this muscle group",
+                        "name": "muscle_group_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only sessions with (true) or without (false) details",
+                        "name": "has_details",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "datetime order: desc (default) or asc",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.sessionPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Accepts either a single workout_type_id or an ordered list of exercises with their sets.\nnew_records lists the personal records set by the session.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workout-sessions"
+                ],
+                "summary": "Create workout session",
+                "parameters": [
+                    {
+                        "description": "Session",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.workoutSessionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.sessionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/workout-sessions/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workout-sessions"
+                ],
+                "summary": "Get workout session by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "WorkoutSession ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutSession"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workout-sessions"
+                ],
+                "summary": "Update workout session start/end time",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "WorkoutSession ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Times",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.workoutSessionUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutSession"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "workout-sessions"
+                ],
+                "summary": "Delete workout session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "WorkoutSession ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/workout-sessions/{id}/details": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workout-sessions"
+                ],
+                "summary": "Add detail to workout session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "WorkoutSession ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Detail",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.workoutDetailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.detailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/workout-sessions/{id}/exercises": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workout-sessions"
+                ],
+                "summary": "Append an exercise to a workout session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "WorkoutSession ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Exercise",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.workoutExerciseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutExercise"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/workout-sessions/{id}/exercises/order": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workout-sessions"
+                ],
+                "summary": "Reorder the exercises of a workout session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "WorkoutSession ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Exercise IDs in the new order",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.exerciseOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutSession"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/workout-sessions/{id}/exercises/{exercise_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "workout-sessions"
+                ],
+                "summary": "Remove an exercise and its sets from a workout session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "WorkoutSession ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "WorkoutExercise ID",
+                        "name": "exercise_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/workout-sessions/{id}/sets": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workout-sets"
+                ],
+                "summary": "List sets of a workout session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "WorkoutSession ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_shared_pagination.Page-github_com_VibeTeam_fitness-tracker-backend_workout_models_WorkoutSet"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workout-sets"
+                ],
+                "summary": "Log a set in a workout session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "WorkoutSession ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Set",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.workoutSetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.setResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/workout-sessions/{id}/sets/{set_id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workout-sets"
+                ],
+                "summary": "Update a set of a workout session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "WorkoutSession ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "WorkoutSet ID",
+                        "name": "set_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Set",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.workoutSetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.setResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "workout-sets"
+                ],
+                "summary": "Delete a set of a workout session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "WorkoutSession ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "WorkoutSet ID",
+                        "name": "set_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/workout-templates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Ordered by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workout-templates"
+                ],
+                "summary": "List workout templates of the user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_shared_pagination.Page-github_com_VibeTeam_fitness-tracker-backend_workout_models_WorkoutTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workout-templates"
+                ],
+                "summary": "Create workout template",
+                "parameters": [
+                    {
+                        "description": "Template",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.workoutTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/workout-templates/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workout-templates"
+                ],
+                "summary": "Get workout template by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "WorkoutTemplate ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces name, notes and the full ordered exercise list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workout-templates"
+                ],
+                "summary": "Update workout template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "WorkoutTemplate ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Template",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.workoutTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "workout-templates"
+                ],
+                "summary": "Delete workout template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "WorkoutTemplate ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/workout-templates/{id}/start": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a new session for the authenticated user pre-filled with the template's exercises and planned sets.\nAPI keys need the sessions:write scope besides templates:write.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workout-templates"
+                ],
+                "summary": "Start a workout session from a template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "WorkoutTemplate ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Start time (defaults to now)",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.startTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutSession"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/workout-types": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workout-types"
+                ],
+                "summary": "List workout types",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_shared_pagination.Page-github_com_VibeTeam_fitness-tracker-backend_workout_models_WorkoutType"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workout-types"
+                ],
+                "summary": "Create workout type",
+                "parameters": [
+                    {
+                        "description": "Workout type",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.workoutTypeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutType"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/workout-types/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workout-types"
+                ],
+                "summary": "Get workout type by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "WorkoutType ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutType"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workout-types"
+                ],
+                "summary": "Update workout type",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "WorkoutType ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.workoutTypeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutType"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "workout-types"
+                ],
+                "summary": "Delete workout type",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "WorkoutType ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/workout-types/{id}/e1rm": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "One point per session: the best estimate among its completed sets of the workout type, in kg",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "records"
+                ],
+                "summary": "Estimated one-rep max over time",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "WorkoutType ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "epley, brzycki or lombardi (defaults to the server setting)",
+                        "name": "formula",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date YYYY-MM-DD (inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date YYYY-MM-DD (inclusive)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fitness-tracker-backend_workout_handler.oneRepMaxResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        },
+        "/workout-types/{id}/records": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "records"
+                ],
+                "summary": "Personal record history of the user for a workout type",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "WorkoutType ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_shared_pagination.Page-github_com_VibeTeam_fitness-tracker-backend_workout_models_PersonalRecord"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "fitness-tracker-backend_user_handler.apiKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "Prefix is the public start of the key, to recognise it in lists.",
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.authorizeResponse": {
+            "type": "object",
+            "properties": {
+                "authorization_url": {
+                    "description": "AuthorizationURL is opened in a browser; the provider redirects back with code and state.",
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.createAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_at": {
+                    "description": "ExpiresAt is optional; keys without it stay valid until deleted.",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.createUserRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.createdAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "description": "Key is shown only once. Send it in the X-API-Key header or as a Bearer token.",
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "Prefix is the public start of the key, to recognise it in lists.",
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.deleteMeRequest": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.forgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.loginRequest": {
+            "type": "object",
+            "required": [
                 "email",
+                "password"
+            ],
+            "properties": {
+                "device_name": {
+                    "description": "DeviceName labels the new session in the session list, e.g. \"Pixel 8\".",
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.logoutRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "description": "RefreshToken selects the login to end; when omitted, every login of the user is ended.",
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.mfaConfirmRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.mfaDisableRequest": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.mfaEnrollRequest": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.mfaEnrollResponse": {
+            "type": "object",
+            "properties": {
+                "otpauth_uri": {
+                    "description": "OTPAuthURI is imported by authenticator apps, usually rendered as a QR code.",
+                    "type": "string"
+                },
+                "recovery_codes": {
+                    "description": "RecoveryCodes are shown only once; each replaces a TOTP code a single time.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.mfaStatusResponse": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "recovery_codes_left": {
+                    "type": "integer"
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.mfaVerifyRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "mfa_token"
+            ],
+            "properties": {
+                "code": {
+                    "description": "Code is a TOTP code or one of the recovery codes.",
+                    "type": "string"
+                },
+                "mfa_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.providersResponse": {
+            "type": "object",
+            "properties": {
+                "providers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.refreshRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.resendVerificationRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.resetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.sessionResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "description": "Current marks the session of the access token used for the request.",
+                    "type": "boolean"
+                },
+                "device_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.socialCallbackRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "state"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "device_name": {
+                    "description": "DeviceName labels the new session in the session list, e.g. \"Pixel 8\".",
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.tokenResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.updateAPIKeyRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.updateMeRequest": {
+            "type": "object",
+            "properties": {
+                "current_password": {
+                    "description": "CurrentPassword is required to change the e-mail address or the password.",
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "description": "Password is the new password.",
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_user_handler.updateUserRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_analytics.volumeResponse": {
+            "type": "object",
+            "properties": {
+                "by": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "group_by": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_repository.VolumeRow"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.adherenceResponse": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "integer"
+                },
+                "enrollment_id": {
+                    "type": "integer"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "scheduled": {
+                    "type": "integer"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.coachMessageRequest": {
+            "type": "object",
+            "required": [
+                "content"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "maxLength": 4000
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.coachThreadRequest": {
+            "type": "object",
+            "properties": {
+                "title": {
+                    "description": "Title defaults to the start of the first message.",
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.completeDayRequest": {
+            "type": "object",
+            "required": [
+                "session_id"
+            ],
+            "properties": {
+                "program_day_id": {
+                    "description": "ProgramDayID defaults to the day scheduled on the session's date.",
+                    "type": "integer"
+                },
+                "session_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.detailResponse": {
+            "type": "object",
+            "properties": {
+                "detailName": {
+                    "type": "string"
+                },
+                "detailValue": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "new_records": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.PersonalRecord"
+                    }
+                },
+                "workoutSessionID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.enrollRequest": {
+            "type": "object",
+            "properties": {
+                "start_date": {
+                    "description": "StartDate is a calendar date (YYYY-MM-DD); defaults to today.",
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.errorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.exerciseOrderRequest": {
+            "type": "object",
+            "required": [
+                "exercise_ids"
+            ],
+            "properties": {
+                "exercise_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.muscleGroupRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.oneRepMaxResponse": {
+            "type": "object",
+            "properties": {
+                "formula": {
+                    "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_use_case.Formula"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_use_case.OneRepMaxPoint"
+                    }
+                },
+                "workout_type_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.prescriptionRequest": {
+            "type": "object",
+            "required": [
+                "sets",
+                "workout_type_id"
+            ],
+            "properties": {
+                "notes": {
+                    "type": "string"
+                },
+                "percent_1rm": {
+                    "type": "number",
+                    "maximum": 150
+                },
+                "reps": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "sets": {
+                    "type": "integer",
+                    "maximum": 50,
+                    "minimum": 1
+                },
+                "unit": {
+                    "type": "string",
+                    "enum": [
+                        "kg",
+                        "lb"
+                    ]
+                },
+                "weight": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "workout_type_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.programDayRequest": {
+            "type": "object",
+            "required": [
+                "day_number",
+                "prescriptions"
+            ],
+            "properties": {
+                "day_number": {
+                    "type": "integer",
+                    "maximum": 7,
+                    "minimum": 1
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prescriptions": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/fitness-tracker-backend_workout_handler.prescriptionRequest"
+                    }
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.programRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "weeks"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "weeks": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/fitness-tracker-backend_workout_handler.programWeekRequest"
+                    }
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.programWeekRequest": {
+            "type": "object",
+            "required": [
+                "week_number"
+            ],
+            "properties": {
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fitness-tracker-backend_workout_handler.programDayRequest"
+                    }
+                },
+                "week_number": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.sessionPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutSession"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.sessionResponse": {
+            "type": "object",
+            "properties": {
+                "datetime": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutDetail"
+                    }
+                },
+                "durationSeconds": {
+                    "type": "integer"
+                },
+                "endedAt": {
+                    "type": "string"
+                },
+                "exercises": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutExercise"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "new_records": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.PersonalRecord"
+                    }
+                },
+                "programDayID": {
+                    "type": "integer"
+                },
+                "programEnrollmentID": {
+                    "description": "ProgramEnrollmentID and ProgramDayID link a session to the scheduled program day it completes.",
+                    "type": "integer"
+                },
+                "sets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutSet"
+                    }
+                },
+                "userID": {
+                    "type": "integer"
+                },
+                "workoutType": {
+                    "description": "Associations",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutType"
+                        }
+                    ]
+                },
+                "workoutTypeID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.setResponse": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "new_records": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.PersonalRecord"
+                    }
+                },
+                "reps": {
+                    "type": "integer"
+                },
+                "restSeconds": {
+                    "type": "integer"
+                },
+                "rpe": {
+                    "type": "number"
+                },
+                "setIndex": {
+                    "type": "integer"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "number"
+                },
+                "workoutExerciseID": {
+                    "type": "integer"
+                },
+                "workoutSessionID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.startPlanRequest": {
+            "type": "object",
+            "required": [
+                "exercises"
+            ],
+            "properties": {
+                "datetime": {
+                    "type": "string"
+                },
+                "exercises": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/fitness-tracker-backend_workout_handler.workoutTemplateExerciseRequest"
+                    }
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.startTemplateRequest": {
+            "type": "object",
+            "properties": {
+                "datetime": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.suggestionResponse": {
+            "type": "object",
+            "properties": {
+                "engine": {
+                    "description": "Engine is llm or, when the language model was unavailable, rules.",
+                    "type": "string"
+                },
+                "suggestion": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.suggestionSummary": {
+            "type": "object",
+            "properties": {
+                "chunks": {
+                    "type": "integer"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "engine": {
+                    "type": "string"
+                },
+                "suggestion": {
+                    "description": "Suggestion is the whole text, for clients that missed or dropped chunks.",
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.todayResponse": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "boolean"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "day": {
+                    "type": "integer"
+                },
+                "enrollment_id": {
+                    "type": "integer"
+                },
+                "finished": {
+                    "type": "boolean"
+                },
+                "program_day": {
+                    "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.ProgramDay"
+                },
+                "rest_day": {
+                    "type": "boolean"
+                },
+                "week": {
+                    "type": "integer"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.workoutDetailRequest": {
+            "type": "object",
+            "required": [
                 "name",
-                "password"
+                "value"
             ],
             "properties": {
-                "email": {
+                "name": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.workoutExerciseRequest": {
+            "type": "object",
+            "required": [
+                "workout_type_id"
+            ],
+            "properties": {
+                "notes": {
+                    "type": "string"
+                },
+                "sets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fitness-tracker-backend_workout_handler.workoutSetRequest"
+                    }
+                },
+                "workout_type_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.workoutPlanExercise": {
+            "type": "object",
+            "properties": {
+                "notes": {
+                    "description": "Notes holds the model's reason for the exercise; rule engine plans leave it empty.",
+                    "type": "string"
+                },
+                "rest_seconds": {
+                    "type": "integer"
+                },
+                "target_reps": {
+                    "type": "integer"
+                },
+                "target_sets": {
+                    "type": "integer"
+                },
+                "target_weight": {
+                    "type": "number"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "workout_type": {
+                    "type": "string"
+                },
+                "workout_type_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.workoutPlanResponse": {
+            "type": "object",
+            "properties": {
+                "engine": {
+                    "description": "Engine is llm or rules; a rules plan for a rest day has no exercises.",
+                    "type": "string"
+                },
+                "exercises": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fitness-tracker-backend_workout_handler.workoutPlanExercise"
+                    }
+                },
+                "rationale": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.workoutSessionRequest": {
+            "type": "object",
+            "properties": {
+                "datetime": {
+                    "type": "string"
+                },
+                "ended_at": {
+                    "type": "string"
+                },
+                "exercises": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fitness-tracker-backend_workout_handler.workoutExerciseRequest"
+                    }
+                },
+                "workout_type_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.workoutSessionUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "datetime": {
+                    "type": "string"
+                },
+                "ended_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.workoutSetRequest": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "boolean"
+                },
+                "exercise_id": {
+                    "description": "ExerciseID attaches the set to an exercise of the session; it defaults to the\nonly exercise when the session has exactly one. Ignored for nested sets.",
+                    "type": "integer"
+                },
+                "reps": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "rest_seconds": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "rpe": {
+                    "type": "number",
+                    "maximum": 10,
+                    "minimum": 0
+                },
+                "set_index": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "unit": {
+                    "type": "string",
+                    "enum": [
+                        "kg",
+                        "lb"
+                    ]
+                },
+                "weight": {
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.workoutTemplateExerciseRequest": {
+            "type": "object",
+            "required": [
+                "target_sets",
+                "workout_type_id"
+            ],
+            "properties": {
+                "notes": {
                     "type": "string"
                 },
+                "rest_seconds": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "target_reps": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "target_sets": {
+                    "type": "integer",
+                    "maximum": 50,
+                    "minimum": 1
+                },
+                "target_weight": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "unit": {
+                    "type": "string",
+                    "enum": [
+                        "kg",
+                        "lb"
+                    ]
+                },
+                "workout_type_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "fitness-tracker-backend_workout_handler.workoutTemplateRequest": {
+            "type": "object",
+            "required": [
+                "exercises",
+                "name"
+            ],
+            "properties": {
+                "exercises": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/fitness-tracker-backend_workout_handler.workoutTemplateExerciseRequest"
+                    }
+                },
                 "name": {
                     "type": "string"
                 },
-                "password": {
+                "notes": {
                     "type": "string"
                 }
             }
         },
-        "fitness-tracker-backend_user_handler.loginRequest": {
+        "fitness-tracker-backend_workout_handler.workoutTypeRequest": {
             "type": "object",
             "required": [
-                "email",
-                "password"
+                "muscle_group_id",
+                "name"
             ],
             "properties": {
-                "email": {
+                "muscle_group_id": {
+                    "type": "integer"
+                },
+                "name": {
                     "type": "string"
+                }
+            }
+        },
+        "gin.H": {
+            "type": "object",
+            "additionalProperties": {}
+        },
+        "github_com_VibeTeam_fitness-tracker-backend_shared_pagination.Page-github_com_VibeTeam_fitness-tracker-backend_user_models_User": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_user_models.User"
+                    }
                 },
-                "password": {
+                "next_cursor": {
+                    "description": "NextCursor fetches the following page; it is empty on the last page.",
                     "type": "string"
                 }
             }
         },
-        "fitness-tracker-backend_user_handler.refreshRequest": {
+        "github_com_VibeTeam_fitness-tracker-backend_shared_pagination.Page-github_com_VibeTeam_fitness-tracker-backend_workout_models_CoachThread": {
             "type": "object",
-            "required": [
-                "refresh_token"
-            ],
             "properties": {
-                "refresh_token": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.CoachThread"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor fetches the following page; it is empty on the last page.",
                     "type": "string"
                 }
             }
         },
-        "fitness-tracker-backend_user_handler.tokenResponse": {
+        "github_com_VibeTeam_fitness-tracker-backend_shared_pagination.Page-github_com_VibeTeam_fitness-tracker-backend_workout_models_MuscleGroup": {
             "type": "object",
             "properties": {
-                "access_token": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.MuscleGroup"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor fetches the following page; it is empty on the last page.",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_VibeTeam_fitness-tracker-backend_shared_pagination.Page-github_com_VibeTeam_fitness-tracker-backend_workout_models_PersonalRecord": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.PersonalRecord"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor fetches the following page; it is empty on the last page.",
                     "type": "string"
+                }
+            }
+        },
+        "github_com_VibeTeam_fitness-tracker-backend_shared_pagination.Page-github_com_VibeTeam_fitness-tracker-backend_workout_models_Program": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.Program"
+                    }
                 },
-                "refresh_token": {
+                "next_cursor": {
+                    "description": "NextCursor fetches the following page; it is empty on the last page.",
                     "type": "string"
                 }
             }
         },
-        "fitness-tracker-backend_user_handler.updateUserRequest": {
+        "github_com_VibeTeam_fitness-tracker-backend_shared_pagination.Page-github_com_VibeTeam_fitness-tracker-backend_workout_models_ProgramEnrollment": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.ProgramEnrollment"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor fetches the following page; it is empty on the last page.",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_VibeTeam_fitness-tracker-backend_shared_pagination.Page-github_com_VibeTeam_fitness-tracker-backend_workout_models_WorkoutSet": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutSet"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor fetches the following page; it is empty on the last page.",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_VibeTeam_fitness-tracker-backend_shared_pagination.Page-github_com_VibeTeam_fitness-tracker-backend_workout_models_WorkoutTemplate": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutTemplate"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor fetches the following page; it is empty on the last page.",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_VibeTeam_fitness-tracker-backend_shared_pagination.Page-github_com_VibeTeam_fitness-tracker-backend_workout_models_WorkoutType": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutType"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor fetches the following page; it is empty on the last page.",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_VibeTeam_fitness-tracker-backend_user_auth.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "description": "Crv and X are the curve and public key of Ed25519 keys.",
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "description": "N and E are the RSA modulus and exponent.",
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_VibeTeam_fitness-tracker-backend_user_auth.JWKS": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_user_auth.JWK"
+                    }
+                }
+            }
+        },
+        "github_com_VibeTeam_fitness-tracker-backend_user_models.User": {
             "type": "object",
             "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
                 "email": {
                     "type": "string"
                 },
+                "emailVerifiedAt": {
+                    "description": "EmailVerifiedAt is nil until the current e-mail address has been confirmed.",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "mfaenabledAt": {
+                    "description": "MFAEnabledAt is nil until TOTP enrollment has been confirmed with a first code.",
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 },
-                "password": {
+                "passwordHash": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_VibeTeam_fitness-tracker-backend_workout_models.CoachMessage": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "threadID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_VibeTeam_fitness-tracker-backend_workout_models.CoachThread": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "UpdatedAt moves with every message, so threads can be listed by recent activity.",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "messages": {
+                    "description": "Associations",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.CoachMessage"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_VibeTeam_fitness-tracker-backend_workout_models.MuscleGroup": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_VibeTeam_fitness-tracker-backend_workout_models.PersonalRecord": {
+            "type": "object",
+            "properties": {
+                "achievedAt": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "reps": {
+                    "type": "integer"
+                },
+                "userID": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "number"
+                },
+                "weight": {
+                    "type": "number"
+                },
+                "workoutSessionID": {
+                    "type": "integer"
+                },
+                "workoutSetID": {
+                    "type": "integer"
+                },
+                "workoutType": {
+                    "description": "Associations",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutType"
+                        }
+                    ]
+                },
+                "workoutTypeID": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_VibeTeam_fitness-tracker-backend_workout_models.Program": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
                     "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "integer"
+                },
+                "weeks": {
+                    "description": "Associations",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.ProgramWeek"
+                    }
                 }
             }
         },
-        "fitness-tracker-backend_workout_handler.errorResponse": {
+        "github_com_VibeTeam_fitness-tracker-backend_workout_models.ProgramDay": {
             "type": "object",
             "properties": {
-                "error": {
+                "dayNumber": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
                     "type": "string"
+                },
+                "prescriptions": {
+                    "description": "Associations",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.ProgramPrescription"
+                    }
+                },
+                "programWeekID": {
+                    "type": "integer"
                 }
             }
         },
-        "fitness-tracker-backend_workout_handler.muscleGroupRequest": {
+        "github_com_VibeTeam_fitness-tracker-backend_workout_models.ProgramEnrollment": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "name": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "program": {
+                    "description": "Associations",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.Program"
+                        }
+                    ]
+                },
+                "programID": {
+                    "type": "integer"
+                },
+                "startDate": {
                     "type": "string"
+                },
+                "userID": {
+                    "type": "integer"
                 }
             }
         },
-        "fitness-tracker-backend_workout_handler.suggestionResponse": {
+        "github_com_VibeTeam_fitness-tracker-backend_workout_models.ProgramPrescription": {
             "type": "object",
             "properties": {
-                "suggestion": {
+                "id": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "percentOneRM": {
+                    "type": "number"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "programDayID": {
+                    "type": "integer"
+                },
+                "reps": {
+                    "type": "integer"
+                },
+                "sets": {
+                    "type": "integer"
+                },
+                "unit": {
                     "type": "string"
+                },
+                "weight": {
+                    "type": "number"
+                },
+                "workoutType": {
+                    "description": "Associations",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutType"
+                        }
+                    ]
+                },
+                "workoutTypeID": {
+                    "type": "integer"
                 }
             }
         },
-        "fitness-tracker-backend_workout_handler.workoutDetailRequest": {
+        "github_com_VibeTeam_fitness-tracker-backend_workout_models.ProgramWeek": {
             "type": "object",
-            "required": [
-                "name",
-                "value"
-            ],
             "properties": {
-                "name": {
-                    "type": "string"
+                "days": {
+                    "description": "Associations",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.ProgramDay"
+                    }
                 },
-                "value": {
-                    "type": "string"
+                "id": {
+                    "type": "integer"
+                },
+                "programID": {
+                    "type": "integer"
+                },
+                "weekNumber": {
+                    "type": "integer"
                 }
             }
         },
-        "fitness-tracker-backend_workout_handler.workoutSessionRequest": {
+        "github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutDetail": {
             "type": "object",
-            "required": [
-                "workout_type_id"
-            ],
             "properties": {
-                "datetime": {
+                "detailName": {
                     "type": "string"
                 },
-                "workout_type_id": {
+                "detailValue": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "workoutSessionID": {
                     "type": "integer"
                 }
             }
         },
-        "fitness-tracker-backend_workout_handler.workoutTypeRequest": {
+        "github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutExercise": {
             "type": "object",
-            "required": [
-                "muscle_group_id",
-                "name"
-            ],
             "properties": {
-                "muscle_group_id": {
+                "id": {
                     "type": "integer"
                 },
-                "name": {
+                "notes": {
                     "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "sets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutSet"
+                    }
+                },
+                "workoutSessionID": {
+                    "type": "integer"
+                },
+                "workoutType": {
+                    "description": "Associations",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutType"
+                        }
+                    ]
+                },
+                "workoutTypeID": {
+                    "type": "integer"
                 }
             }
         },
-        "gin.H": {
-            "type": "object",
-            "additionalProperties": {}
-        },
-        "github_com_VibeTeam_fitness-tracker-backend_user_models.User": {
+        "github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutSession": {
             "type": "object",
             "properties": {
-                "createdAt": {
+                "datetime": {
                     "type": "string"
                 },
-                "email": {
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutDetail"
+                    }
+                },
+                "durationSeconds": {
+                    "type": "integer"
+                },
+                "endedAt": {
                     "type": "string"
                 },
+                "exercises": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutExercise"
+                    }
+                },
                 "id": {
                     "type": "integer"
                 },
-                "name": {
-                    "type": "string"
+                "programDayID": {
+                    "type": "integer"
                 },
-                "passwordHash": {
-                    "type": "string"
+                "programEnrollmentID": {
+                    "description": "ProgramEnrollmentID and ProgramDayID link a session to the scheduled program day it completes.",
+                    "type": "integer"
+                },
+                "sets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutSet"
+                    }
+                },
+                "userID": {
+                    "type": "integer"
+                },
+                "workoutType": {
+                    "description": "Associations",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutType"
+                        }
+                    ]
+                },
+                "workoutTypeID": {
+                    "type": "integer"
                 }
             }
         },
-        "github_com_VibeTeam_fitness-tracker-backend_workout_models.MuscleGroup": {
+        "github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutSet": {
             "type": "object",
             "properties": {
+                "completed": {
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "name": {
+                "reps": {
+                    "type": "integer"
+                },
+                "restSeconds": {
+                    "type": "integer"
+                },
+                "rpe": {
+                    "type": "number"
+                },
+                "setIndex": {
+                    "type": "integer"
+                },
+                "unit": {
                     "type": "string"
+                },
+                "weight": {
+                    "type": "number"
+                },
+                "workoutExerciseID": {
+                    "type": "integer"
+                },
+                "workoutSessionID": {
+                    "type": "integer"
                 }
             }
         },
-        "github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutDetail": {
+        "github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutTemplate": {
             "type": "object",
             "properties": {
-                "detailName": {
+                "createdAt": {
                     "type": "string"
                 },
-                "detailValue": {
-                    "type": "string"
+                "exercises": {
+                    "description": "Associations",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutTemplateExercise"
+                    }
                 },
                 "id": {
                     "type": "integer"
                 },
-                "workoutSessionID": {
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "userID": {
                     "type": "integer"
                 }
             }
         },
-        "github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutSession": {
+        "github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutTemplateExercise": {
             "type": "object",
             "properties": {
-                "datetime": {
+                "id": {
+                    "type": "integer"
+                },
+                "notes": {
                     "type": "string"
                 },
-                "details": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/github_com_VibeTeam_fitness-tracker-backend_workout_models.WorkoutDetail"
-                    }
+                "position": {
+                    "type": "integer"
                 },
-                "id": {
+                "restSeconds": {
                     "type": "integer"
                 },
-                "userID": {
+                "targetReps": {
+                    "type": "integer"
+                },
+                "targetSets": {
+                    "type": "integer"
+                },
+                "targetWeight": {
+                    "type": "number"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "workoutTemplateID": {
                     "type": "integer"
                 },
                 "workoutType": {
@@ -1328,9 +6344,71 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "github_com_VibeTeam_fitness-tracker-backend_workout_repository.VolumeRow": {
+            "type": "object",
+            "properties": {
+                "group_id": {
+                    "type": "integer"
+                },
+                "group_name": {
+                    "type": "string"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "reps": {
+                    "type": "integer"
+                },
+                "sets": {
+                    "type": "integer"
+                },
+                "tonnage": {
+                    "type": "number"
+                }
+            }
+        },
+        "github_com_VibeTeam_fitness-tracker-backend_workout_use_case.Formula": {
+            "type": "string",
+            "enum": [
+                "epley",
+                "brzycki",
+                "lombardi"
+            ],
+            "x-enum-varnames": [
+                "FormulaEpley",
+                "FormulaBrzycki",
+                "FormulaLombardi"
+            ]
+        },
+        "github_com_VibeTeam_fitness-tracker-backend_workout_use_case.OneRepMaxPoint": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "estimate": {
+                    "type": "number"
+                },
+                "reps": {
+                    "type": "integer"
+                },
+                "session_id": {
+                    "type": "integer"
+                },
+                "weight": {
+                    "type": "number"
+                }
+            }
         }
     },
     "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "A personal API key from /users/me/api-keys; workout endpoints check its scopes.",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
         "BearerAuth": {
             "description": "Provide your JWT with the \"Bearer \" prefix. Example: \"Bearer {token}\".",
             "type": "apiKey",
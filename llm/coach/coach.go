@@ -0,0 +1,70 @@
+// Package coach holds conversations with the AI coach: every reply is generated from the
+// athlete's recent training and as much of the conversation as fits the model's context.
+package coach
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/VibeTeam/fitness-tracker-backend/llm/provider"
+)
+
+// DefaultContextTokens is the context window assumed when none is configured; small local
+// models commonly run with 4096 tokens.
+const DefaultContextTokens = 4096
+
+// replyTokens is the part of the context window kept free for the reply.
+const replyTokens = 1024
+
+const instructions = "You are a friendly, knowledgeable strength and conditioning coach chatting with an athlete. " +
+	"Give concrete, safe advice based on their training, keep answers short, and suggest seeing a professional " +
+	"for pain that is sharp or lasts."
+
+// Coach generates coach replies with a model provider.
+type Coach struct {
+	provider      provider.Provider
+	contextTokens int
+}
+
+// New creates a Coach for a model with a context window of contextTokens tokens, or
+// DefaultContextTokens if it is not positive.
+func New(p provider.Provider, contextTokens int) *Coach {
+	if contextTokens <= 0 {
+		contextTokens = DefaultContextTokens
+	}
+	return &Coach{provider: p, contextTokens: contextTokens}
+}
+
+// Reply continues a conversation, given oldest first and ending with the athlete's newest
+// message, calling onChunk as Provider.Chat does. training summarises the athlete's recent
+// workouts. The oldest messages are left out when the conversation outgrows the context window.
+func (c *Coach) Reply(ctx context.Context, training string, conversation []provider.Message, onChunk func(text string) error) (string, error) {
+	system := provider.Message{Role: provider.RoleSystem, Content: instructions + "\n\nTheir recent training:\n" + training}
+	return c.provider.Chat(ctx, c.trim(system, conversation), onChunk)
+}
+
+// trim returns the system message followed by the newest messages of the conversation that
+// fit the context window next to it and the reply. The newest message is always kept, and the
+// kept part starts with a user message as some chat templates require.
+func (c *Coach) trim(system provider.Message, conversation []provider.Message) []provider.Message {
+	budget := c.contextTokens - replyTokens - estimateTokens(system)
+	start := len(conversation)
+	for start > 0 {
+		cost := estimateTokens(conversation[start-1])
+		if start < len(conversation) && cost > budget {
+			break
+		}
+		budget -= cost
+		start--
+	}
+	for start < len(conversation)-1 && conversation[start].Role != provider.RoleUser {
+		start++
+	}
+	return append([]provider.Message{system}, conversation[start:]...)
+}
+
+// estimateTokens approximates the tokens a message takes: about four characters per token
+// for English text plus a few for the role markup.
+func estimateTokens(m provider.Message) int {
+	return utf8.RuneCountInString(m.Content)/4 + 4
+}
@@ -0,0 +1,58 @@
+package coach_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/VibeTeam/fitness-tracker-backend/llm/coach"
+	"github.com/VibeTeam/fitness-tracker-backend/llm/provider"
+)
+
+func TestReplyTrimsConversation(t *testing.T) {
+	// 400 characters make about 100 tokens per message
+	long := strings.Repeat("my shoulder hurts when benching. ", 12)
+	var conversation []provider.Message
+	for i := 0; i < 20; i++ {
+		conversation = append(conversation,
+			provider.Message{Role: provider.RoleUser, Content: long},
+			provider.Message{Role: provider.RoleAssistant, Content: long},
+		)
+	}
+	conversation = append(conversation, provider.Message{Role: provider.RoleUser, Content: "swap the bench?"})
+
+	llm := provider.NewFake("Try floor presses.")
+	reply, err := coach.New(llm, 2048).Reply(context.Background(), "Session 1: Bench Press", conversation, nil)
+	if err != nil || reply != "Try floor presses." {
+		t.Fatalf("got %q, %v", reply, err)
+	}
+
+	sent := llm.Chats()[0]
+	if sent[0].Role != provider.RoleSystem || !strings.Contains(sent[0].Content, "Session 1: Bench Press") {
+		t.Fatalf("system message missing training: %+v", sent[0])
+	}
+	if last := sent[len(sent)-1]; last.Content != "swap the bench?" {
+		t.Fatalf("newest message dropped, last is %q", last.Content)
+	}
+	if sent[1].Role != provider.RoleUser {
+		t.Fatalf("kept conversation starts with %s", sent[1].Role)
+	}
+	tokens := 0
+	for _, m := range sent {
+		tokens += len(m.Content)/4 + 4
+	}
+	if len(sent) >= len(conversation) || tokens > 2048-1024 {
+		t.Fatalf("sent %d of %d messages, about %d tokens", len(sent)-1, len(conversation), tokens)
+	}
+}
+
+func TestReplyKeepsOversizedNewestMessage(t *testing.T) {
+	llm := provider.NewFake("ok")
+	huge := provider.Message{Role: provider.RoleUser, Content: strings.Repeat("x", 20000)}
+	if _, err := coach.New(llm, 0).Reply(context.Background(), "", []provider.Message{{Role: provider.RoleUser, Content: "hi"}, {Role: provider.RoleAssistant, Content: "hello"}, huge}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if sent := llm.Chats()[0]; len(sent) != 2 || sent[1] != huge {
+		t.Fatalf("expected system message and newest message only, got %d messages", len(sent))
+	}
+}
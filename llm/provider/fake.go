@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Fake is a deterministic Provider for tests and for running the service without a model
+// server. It answers every chat with the same reply, streamed word by word, and remembers
+// the chats it was sent.
+type Fake struct {
+	reply string
+
+	mu    sync.Mutex
+	chats [][]Message
+}
+
+// NewFake creates a provider that always replies with reply.
+func NewFake(reply string) *Fake {
+	return &Fake{reply: reply}
+}
+
+// Chat implements Provider.
+func (f *Fake) Chat(ctx context.Context, messages []Message, onChunk func(text string) error) (string, error) {
+	f.mu.Lock()
+	f.chats = append(f.chats, append([]Message(nil), messages...))
+	f.mu.Unlock()
+
+	var reply string
+	for _, chunk := range strings.SplitAfter(f.reply, " ") {
+		if err := ctx.Err(); err != nil {
+			return reply, err
+		}
+		reply += chunk
+		if onChunk != nil && chunk != "" {
+			if err := onChunk(chunk); err != nil {
+				return reply, err
+			}
+		}
+	}
+	return reply, nil
+}
+
+// Chats returns the messages of every chat so far, oldest first.
+func (f *Fake) Chats() [][]Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]Message(nil), f.chats...)
+}
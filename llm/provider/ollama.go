@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Ollama is a Provider backed by the /api/chat endpoint of an Ollama server.
+type Ollama struct {
+	baseURL string
+	model   string
+	httpCli *http.Client
+}
+
+// NewOllama creates a provider for the model on the Ollama server at baseURL,
+// e.g. http://localhost:11434.
+func NewOllama(baseURL, model string) *Ollama {
+	return &Ollama{baseURL: baseURL, model: model, httpCli: &http.Client{}}
+}
+
+// ollamaChatRequest is the payload sent to /api/chat.
+type ollamaChatRequest struct {
+	Model    string    `json:"model"`
+	Messages []Message `json:"messages"`
+}
+
+// ollamaChatResponse is one line of the NDJSON stream /api/chat answers with.
+type ollamaChatResponse struct {
+	Message Message `json:"message"`
+	Done    bool    `json:"done"`
+	// Error is set instead of a message when generation fails mid-stream.
+	Error string `json:"error"`
+}
+
+// EnsureModel pulls the model from the Ollama server if it is not already present.
+// It blocks until the pull completes (or returns an error).
+func (o *Ollama) EnsureModel(ctx context.Context) error {
+	b, _ := json.Marshal(map[string]string{"name": o.model})
+	req, err := http.NewRequestWithContext(ctx, "POST", o.baseURL+"/api/pull", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.httpCli.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var m map[string]any
+		if err := dec.Decode(&m); err != nil {
+			break // stream finished
+		}
+		// when status == "success" or "exists" or "complete" we can finish
+		if status, ok := m["status"].(string); ok {
+			switch status {
+			case "success", "exists", "complete", "already exists":
+				return nil
+			}
+		}
+	}
+	return nil // default: assume success
+}
+
+// Chat implements Provider.
+func (o *Ollama) Chat(ctx context.Context, messages []Message, onChunk func(text string) error) (string, error) {
+	b, _ := json.Marshal(ollamaChatRequest{Model: o.model, Messages: messages})
+	req, err := http.NewRequestWithContext(ctx, "POST", o.baseURL+"/api/chat", bytes.NewReader(b))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.httpCli.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("ollama: unexpected status %s", resp.Status)
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	var reply string
+	for {
+		var chunk ollamaChatResponse
+		if err := dec.Decode(&chunk); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if ctx.Err() != nil {
+				return reply, ctx.Err()
+			}
+			return reply, fmt.Errorf("ollama: reading stream: %w", err)
+		}
+		if chunk.Error != "" {
+			return reply, fmt.Errorf("ollama: %s", chunk.Error)
+		}
+		reply += chunk.Message.Content
+		if onChunk != nil && chunk.Message.Content != "" {
+			if err := onChunk(chunk.Message.Content); err != nil {
+				return reply, err
+			}
+		}
+		if chunk.Done {
+			break
+		}
+	}
+	return reply, nil
+}
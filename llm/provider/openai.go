@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// OpenAI is a Provider backed by an OpenAI-compatible /chat/completions endpoint, as served by
+// llama.cpp server, vLLM, LM Studio and OpenAI itself.
+type OpenAI struct {
+	baseURL string
+	apiKey  string
+	model   string
+	httpCli *http.Client
+}
+
+// NewOpenAI creates a provider for the model behind baseURL, the API root that
+// /chat/completions is appended to, e.g. http://localhost:8080/v1. apiKey is sent as a bearer
+// token unless empty; local servers usually need none.
+func NewOpenAI(baseURL, apiKey, model string) *OpenAI {
+	return &OpenAI{baseURL: strings.TrimSuffix(baseURL, "/"), apiKey: apiKey, model: model, httpCli: &http.Client{}}
+}
+
+// openAIChatRequest is the payload sent to /chat/completions.
+type openAIChatRequest struct {
+	Model    string    `json:"model"`
+	Messages []Message `json:"messages"`
+	Stream   bool      `json:"stream"`
+}
+
+// openAIChatChunk is the data of one server-sent event of a streamed completion.
+type openAIChatChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	// Error is set by some servers instead of choices when generation fails mid-stream.
+	Error *openAIError `json:"error"`
+}
+
+type openAIError struct {
+	Message string `json:"message"`
+}
+
+// Chat implements Provider.
+func (o *OpenAI) Chat(ctx context.Context, messages []Message, onChunk func(text string) error) (string, error) {
+	b, _ := json.Marshal(openAIChatRequest{Model: o.model, Messages: messages, Stream: true})
+	req, err := http.NewRequestWithContext(ctx, "POST", o.baseURL+"/chat/completions", bytes.NewReader(b))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	if o.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+
+	resp, err := o.httpCli.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			Error openAIError `json:"error"`
+		}
+		if data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096)); json.Unmarshal(data, &body) == nil && body.Error.Message != "" {
+			return "", fmt.Errorf("openai: %s: %s", resp.Status, body.Error.Message)
+		}
+		return "", fmt.Errorf("openai: unexpected status %s", resp.Status)
+	}
+
+	var reply string
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue // blank separator lines, comments and other event fields
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			return reply, nil
+		}
+		var chunk openAIChatChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return reply, fmt.Errorf("openai: reading stream: %w", err)
+		}
+		if chunk.Error != nil {
+			return reply, fmt.Errorf("openai: %s", chunk.Error.Message)
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content == "" {
+				continue
+			}
+			reply += choice.Delta.Content
+			if onChunk != nil {
+				if err := onChunk(choice.Delta.Content); err != nil {
+					return reply, err
+				}
+			}
+		}
+	}
+	if ctx.Err() != nil {
+		return reply, ctx.Err()
+	}
+	if err := scanner.Err(); err != nil {
+		return reply, fmt.Errorf("openai: reading stream: %w", err)
+	}
+	return reply, nil
+}
@@ -0,0 +1,28 @@
+// Package provider talks to the language models behind workout suggestions. Provider hides
+// the wire protocol, so the model server can be swapped through configuration: Ollama,
+// any server with an OpenAI-compatible chat completions API (llama.cpp server, vLLM,
+// LM Studio, OpenAI itself), or a deterministic fake for tests and local development.
+package provider
+
+import "context"
+
+// Roles of chat messages.
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Message is one message of a chat.
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Provider generates the model's reply to a chat.
+type Provider interface {
+	// Chat returns the reply to messages, calling onChunk, when not nil, with every piece of
+	// text as it is generated. Cancelling ctx aborts the generation; an error from onChunk
+	// aborts it too and is returned.
+	Chat(ctx context.Context, messages []Message, onChunk func(text string) error) (string, error)
+}
@@ -0,0 +1,113 @@
+package provider_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/VibeTeam/fitness-tracker-backend/llm/provider"
+)
+
+var chat = []provider.Message{{Role: provider.RoleUser, Content: "next workout?"}}
+
+// collect runs a chat and returns the reply and the chunks it was streamed in.
+func collect(t *testing.T, p provider.Provider) (string, []string, error) {
+	t.Helper()
+	var chunks []string
+	reply, err := p.Chat(context.Background(), chat, func(text string) error {
+		chunks = append(chunks, text)
+		return nil
+	})
+	return reply, chunks, err
+}
+
+func TestOllamaChat(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model    string
+			Messages []provider.Message
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || r.URL.Path != "/api/chat" || req.Model != "gemma" || len(req.Messages) != 1 {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		for _, chunk := range []string{"Leg ", "day"} {
+			fmt.Fprintf(w, `{"message":{"role":"assistant","content":%q},"done":false}`+"\n", chunk)
+		}
+		fmt.Fprintln(w, `{"message":{"role":"assistant","content":""},"done":true}`)
+	}))
+	defer srv.Close()
+
+	reply, chunks, err := collect(t, provider.NewOllama(srv.URL, "gemma"))
+	if err != nil || reply != "Leg day" || len(chunks) != 2 {
+		t.Fatalf("got %q in %q, %v", reply, chunks, err)
+	}
+
+	_, _, err = collect(t, provider.NewOllama(srv.URL, "missing"))
+	if err == nil {
+		t.Fatal("expected an error for a failed request")
+	}
+}
+
+func TestOpenAIChat(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":{"message":"invalid api key"}}`)
+			return
+		}
+		var req struct {
+			Model  string
+			Stream bool
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Model != "qwen" || !req.Stream {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, ": keep-alive\n\n")
+		fmt.Fprint(w, `data: {"choices":[{"delta":{"role":"assistant"}}]}`+"\n\n")
+		for _, chunk := range []string{"Push ", "day"} {
+			fmt.Fprintf(w, `data: {"choices":[{"delta":{"content":%q}}]}`+"\n\n", chunk)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	reply, chunks, err := collect(t, provider.NewOpenAI(srv.URL+"/v1/", "secret", "qwen"))
+	if err != nil || reply != "Push day" || len(chunks) != 2 {
+		t.Fatalf("got %q in %q, %v", reply, chunks, err)
+	}
+
+	_, _, err = collect(t, provider.NewOpenAI(srv.URL+"/v1", "wrong", "qwen"))
+	if err == nil || !strings.Contains(err.Error(), "invalid api key") {
+		t.Fatalf("expected the server's error message, got %v", err)
+	}
+}
+
+func TestFakeChat(t *testing.T) {
+	fake := provider.NewFake("Rest day today")
+	reply, chunks, err := collect(t, fake)
+	if err != nil || reply != "Rest day today" || strings.Join(chunks, "|") != "Rest |day |today" {
+		t.Fatalf("got %q in %q, %v", reply, chunks, err)
+	}
+	if chats := fake.Chats(); len(chats) != 1 || chats[0][0].Content != "next workout?" {
+		t.Fatalf("chats not recorded: %v", chats)
+	}
+
+	// an error from the callback stops the stream
+	stop := errors.New("client gone")
+	reply, err = fake.Chat(context.Background(), chat, func(string) error { return stop })
+	if !errors.Is(err, stop) || reply != "Rest " {
+		t.Fatalf("got %q, %v", reply, err)
+	}
+}
@@ -0,0 +1,227 @@
+package suggester
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/VibeTeam/fitness-tracker-backend/llm/provider"
+)
+
+// ErrInvalidPlan is returned when the model does not produce a usable plan, even after being
+// shown what was wrong with its first answer.
+var ErrInvalidPlan = errors.New("the model did not produce a valid workout plan")
+
+// Limits a plan is clamped to; models occasionally suggest absurd volumes.
+const (
+	maxPlanExercises = 12
+	maxPlanSets      = 10
+	maxPlanReps      = 100
+	maxRestSeconds   = 600
+	// defaultRestSeconds is used when the model leaves out the rest between sets.
+	defaultRestSeconds = 90
+	// maxCatalogNames caps the exercise names listed in the prompt.
+	maxCatalogNames = 200
+)
+
+// Plan is a structured workout suggestion.
+type Plan struct {
+	Title     string         `json:"title"`
+	Rationale string         `json:"rationale"`
+	Exercises []PlanExercise `json:"exercises"`
+}
+
+// PlanExercise is one exercise of a Plan. WorkoutType is always spelled exactly as in the
+// catalog the plan was made from.
+type PlanExercise struct {
+	WorkoutType  string  `json:"workout_type"`
+	Sets         int     `json:"sets"`
+	Reps         int     `json:"reps"`
+	TargetWeight float64 `json:"target_weight"`
+	Unit         string  `json:"unit"`
+	RestSeconds  int     `json:"rest_seconds"`
+	Rationale    string  `json:"rationale"`
+}
+
+// planSchema is the JSON schema the model is asked to answer with.
+const planSchema = `{
+  "type": "object",
+  "required": ["title", "rationale", "exercises"],
+  "properties": {
+    "title": {"type": "string"},
+    "rationale": {"type": "string", "description": "why this workout, in 1-2 sentences"},
+    "exercises": {
+      "type": "array", "minItems": 1, "maxItems": 12,
+      "items": {
+        "type": "object",
+        "required": ["workout_type", "sets", "reps", "target_weight", "unit"],
+        "properties": {
+          "workout_type": {"type": "string", "description": "one of the allowed exercise names, spelled exactly"},
+          "sets": {"type": "integer", "minimum": 1, "maximum": 10},
+          "reps": {"type": "integer", "minimum": 1, "maximum": 100},
+          "target_weight": {"type": "number", "minimum": 0, "description": "0 for bodyweight"},
+          "unit": {"enum": ["kg", "lb"]},
+          "rest_seconds": {"type": "integer", "minimum": 0, "maximum": 600},
+          "rationale": {"type": "string"}
+        }
+      }
+    }
+  }
+}`
+
+// SuggestPlan asks the model for the next workout as a Plan built from the exercises in
+// catalog. The answer is repaired where possible: code fences and surrounding prose are
+// stripped, numbers given as strings such as "8-10" or "60kg" are read, values are clamped
+// and exercise names are matched to the catalog ignoring case and punctuation. Exercises
+// that match nothing are dropped. If no usable plan remains, the model is shown the problem
+// and asked once more before ErrInvalidPlan is returned.
+func (s *Suggester) SuggestPlan(ctx context.Context, history string, catalog []string) (*Plan, error) {
+	if len(catalog) == 0 {
+		return nil, fmt.Errorf("%w: no exercises to choose from", ErrInvalidPlan)
+	}
+	if len(catalog) > maxCatalogNames {
+		catalog = catalog[:maxCatalogNames]
+	}
+	messages := []provider.Message{
+		{Role: provider.RoleSystem, Content: "You are a strength coach. Answer with a single JSON object that matches this JSON schema " +
+			"and nothing else, no prose and no code fences:\n" + planSchema},
+		{Role: provider.RoleUser, Content: fmt.Sprintf("Based on this workout history, plan the next workout: %s.\n"+
+			"Allowed exercise names: %s.", history, strings.Join(catalog, "; "))},
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < 2; attempt++ {
+		reply, err := s.provider.Chat(ctx, messages, nil)
+		if err != nil {
+			return nil, err
+		}
+		plan, err := parsePlan(reply, catalog)
+		if err == nil {
+			return plan, nil
+		}
+		lastErr = err
+		messages = append(messages,
+			provider.Message{Role: provider.RoleAssistant, Content: reply},
+			provider.Message{Role: provider.RoleUser, Content: "That answer was not usable: " + err.Error() +
+				". Answer again with only the JSON object, using only the allowed exercise names."},
+		)
+	}
+	return nil, fmt.Errorf("%w: %v", ErrInvalidPlan, lastErr)
+}
+
+// planReply is a plan as the model writes it, before repair.
+type planReply struct {
+	Title     string `json:"title"`
+	Rationale string `json:"rationale"`
+	Exercises []struct {
+		WorkoutType  string       `json:"workout_type"`
+		Sets         looseNumber  `json:"sets"`
+		Reps         looseNumber  `json:"reps"`
+		TargetWeight looseNumber  `json:"target_weight"`
+		Unit         string       `json:"unit"`
+		RestSeconds  *looseNumber `json:"rest_seconds"`
+		Rationale    string       `json:"rationale"`
+	} `json:"exercises"`
+}
+
+// parsePlan repairs and validates a model's answer against the catalog.
+func parsePlan(reply string, catalog []string) (*Plan, error) {
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return nil, errors.New("no JSON object found")
+	}
+	var raw planReply
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+
+	names := make(map[string]string, len(catalog))
+	for _, name := range catalog {
+		if key := normalizeName(name); names[key] == "" {
+			names[key] = name
+		}
+	}
+	plan := &Plan{Title: strings.TrimSpace(raw.Title), Rationale: strings.TrimSpace(raw.Rationale)}
+	var unknown []string
+	for _, e := range raw.Exercises {
+		name, ok := names[normalizeName(e.WorkoutType)]
+		if !ok {
+			unknown = append(unknown, strconv.Quote(e.WorkoutType))
+			continue
+		}
+		unit := strings.ToLower(strings.TrimSpace(e.Unit))
+		if strings.HasPrefix(unit, "lb") || strings.HasPrefix(unit, "pound") {
+			unit = "lb"
+		} else {
+			unit = "kg"
+		}
+		rest := defaultRestSeconds
+		if e.RestSeconds != nil {
+			rest = clamp(int(*e.RestSeconds), 0, maxRestSeconds)
+		}
+		plan.Exercises = append(plan.Exercises, PlanExercise{
+			WorkoutType:  name,
+			Sets:         clamp(int(e.Sets), 1, maxPlanSets),
+			Reps:         clamp(int(e.Reps), 1, maxPlanReps),
+			TargetWeight: max(float64(e.TargetWeight), 0),
+			Unit:         unit,
+			RestSeconds:  rest,
+			Rationale:    strings.TrimSpace(e.Rationale),
+		})
+		if len(plan.Exercises) == maxPlanExercises {
+			break
+		}
+	}
+	if len(plan.Exercises) == 0 {
+		if len(unknown) > 0 {
+			return nil, fmt.Errorf("no allowed exercise names, got %s", strings.Join(unknown, ", "))
+		}
+		return nil, errors.New("no exercises")
+	}
+	if plan.Title == "" {
+		plan.Title = "Suggested workout"
+	}
+	return plan, nil
+}
+
+// looseNumber reads JSON numbers as well as strings starting with one, such as "8-10" or
+// "60kg"; null and other strings read as zero.
+type looseNumber float64
+
+func (n *looseNumber) UnmarshalJSON(b []byte) error {
+	var f float64
+	if err := json.Unmarshal(b, &f); err == nil {
+		*n = looseNumber(f)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil && string(b) != "null" {
+		return err
+	}
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", ".")
+	end := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) && r != '.' })
+	if end >= 0 {
+		s = s[:end]
+	}
+	f, _ = strconv.ParseFloat(s, 64)
+	*n = looseNumber(f)
+	return nil
+}
+
+// normalizeName reduces an exercise name to its lowercase letters and digits.
+func normalizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+func clamp(v, lo, hi int) int {
+	return min(max(v, lo), hi)
+}
@@ -0,0 +1,78 @@
+package suggester_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/VibeTeam/fitness-tracker-backend/llm/provider"
+	"github.com/VibeTeam/fitness-tracker-backend/llm/suggester"
+)
+
+// script is a provider answering with the given replies in turn.
+type script struct {
+	replies []string
+	chats   [][]provider.Message
+}
+
+func (s *script) Chat(_ context.Context, messages []provider.Message, _ func(string) error) (string, error) {
+	s.chats = append(s.chats, messages)
+	reply := s.replies[0]
+	s.replies = s.replies[1:]
+	return reply, nil
+}
+
+var catalog = []string{"Back Squat", "Bench Press", "Pull-Up"}
+
+func TestSuggestPlanRepairsReply(t *testing.T) {
+	llm := &script{replies: []string{"Here is your plan:\n```json\n" + `{
+		"title": "Lower body",
+		"rationale": "Legs are rested.",
+		"exercises": [
+			{"workout_type": "back squat", "sets": 25, "reps": "8-10", "target_weight": "100kg", "unit": "KG"},
+			{"workout_type": "Leg Press", "sets": 3, "reps": 12, "target_weight": 150, "unit": "kg"},
+			{"workout_type": "pullup", "sets": 3, "reps": 8, "target_weight": 0, "unit": "lbs", "rest_seconds": 120}
+		]
+	}` + "\n```"}}
+
+	plan, err := suggester.New(llm).SuggestPlan(context.Background(), "Session 1: Bench Press", catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []suggester.PlanExercise{
+		{WorkoutType: "Back Squat", Sets: 10, Reps: 8, TargetWeight: 100, Unit: "kg", RestSeconds: 90},
+		{WorkoutType: "Pull-Up", Sets: 3, Reps: 8, Unit: "lb", RestSeconds: 120},
+	}
+	if plan.Title != "Lower body" || len(plan.Exercises) != len(want) {
+		t.Fatalf("unexpected plan %+v", plan)
+	}
+	for i := range want {
+		if plan.Exercises[i] != want[i] {
+			t.Errorf("exercise %d: got %+v, want %+v", i, plan.Exercises[i], want[i])
+		}
+	}
+	if prompt := llm.chats[0][1].Content; !strings.Contains(prompt, "Back Squat; Bench Press; Pull-Up") {
+		t.Errorf("catalog missing from prompt: %s", prompt)
+	}
+}
+
+func TestSuggestPlanRetriesOnce(t *testing.T) {
+	valid := `{"title": "Push", "exercises": [{"workout_type": "Bench Press", "sets": 5, "reps": 5, "target_weight": 80, "unit": "kg"}]}`
+	llm := &script{replies: []string{`{"exercises": [{"workout_type": "Dips"}]}`, valid}}
+	plan, err := suggester.New(llm).SuggestPlan(context.Background(), "", catalog)
+	if err != nil || plan.Exercises[0].WorkoutType != "Bench Press" {
+		t.Fatalf("got %+v, %v", plan, err)
+	}
+	// the second request shows the model its answer and what was wrong with it
+	retry := llm.chats[1]
+	if len(retry) != 4 || retry[2].Role != provider.RoleAssistant || !strings.Contains(retry[3].Content, `"Dips"`) {
+		t.Fatalf("unexpected retry chat %+v", retry)
+	}
+
+	llm = &script{replies: []string{"I cannot help with that.", "Still no."}}
+	_, err = suggester.New(llm).SuggestPlan(context.Background(), "", catalog)
+	if !errors.Is(err, suggester.ErrInvalidPlan) {
+		t.Fatalf("expected ErrInvalidPlan, got %v", err)
+	}
+}
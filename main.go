@@ -8,11 +8,21 @@
 // @in header
 // @name Authorization
 // @description  Provide your JWT with the "Bearer " prefix. Example: "Bearer {token}".
+//
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description  A personal API key from /users/me/api-keys; workout endpoints check its scopes.
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -21,10 +31,16 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/VibeTeam/fitness-tracker-backend/shared/middleware"
+	"github.com/VibeTeam/fitness-tracker-backend/user/audit"
 	"github.com/VibeTeam/fitness-tracker-backend/user/auth"
 	userhandler "github.com/VibeTeam/fitness-tracker-backend/user/handler"
+	"github.com/VibeTeam/fitness-tracker-backend/user/mailer"
+	usermigration "github.com/VibeTeam/fitness-tracker-backend/user/migration"
 	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/oidc"
+	"github.com/VibeTeam/fitness-tracker-backend/user/repository"
 	"github.com/VibeTeam/fitness-tracker-backend/user/repository/gormrepository"
+	"github.com/VibeTeam/fitness-tracker-backend/user/repository/memrepository"
 	"github.com/VibeTeam/fitness-tracker-backend/user/use_case"
 
 	swaggerFiles "github.com/swaggo/files"
@@ -32,10 +48,15 @@ import (
 
 	_ "fitness-tracker-backend/docs"
 
+	"github.com/VibeTeam/fitness-tracker-backend/llm/coach"
+	"github.com/VibeTeam/fitness-tracker-backend/llm/provider"
 	"github.com/VibeTeam/fitness-tracker-backend/llm/suggester"
+	workoutanalytics "github.com/VibeTeam/fitness-tracker-backend/workout/analytics"
 	workouthandler "github.com/VibeTeam/fitness-tracker-backend/workout/handler"
+	workoutmigration "github.com/VibeTeam/fitness-tracker-backend/workout/migration"
 	workoutmodels "github.com/VibeTeam/fitness-tracker-backend/workout/models"
 	workoutrepo "github.com/VibeTeam/fitness-tracker-backend/workout/repository/gormrepository"
+	workoutusecase "github.com/VibeTeam/fitness-tracker-backend/workout/use_case"
 )
 
 func main() {
@@ -50,74 +71,261 @@ func main() {
 		log.Fatalf("failed to connect database: %v", err)
 	}
 
+	// Accounts from before e-mail verification count as verified; runs once, before AutoMigrate adds the column
+	if n, err := usermigration.EmailVerifiedAt(context.Background(), database); err != nil {
+		log.Fatalf("email verified at migration failed: %v", err)
+	} else if n > 0 {
+		log.Printf("marked %d existing accounts as e-mail verified", n)
+	}
+
 	// Auto migrate user and workout models
-	if err := database.AutoMigrate(&models.User{}, &workoutmodels.MuscleGroup{}, &workoutmodels.WorkoutType{}, &workoutmodels.WorkoutSession{}, &workoutmodels.WorkoutDetail{}); err != nil {
+	if err := database.AutoMigrate(&models.User{}, &models.RefreshToken{}, &models.LoginSession{}, &models.PasswordResetToken{}, &models.EmailVerificationToken{}, &models.RecoveryCode{}, &models.UserIdentity{}, &models.OIDCLoginState{}, &models.APIKey{}, &models.LoginThrottle{}, &workoutmodels.MuscleGroup{}, &workoutmodels.WorkoutType{}, &workoutmodels.WorkoutSession{}, &workoutmodels.WorkoutExercise{}, &workoutmodels.WorkoutDetail{}, &workoutmodels.WorkoutSet{},
+		&workoutmodels.WorkoutTemplate{}, &workoutmodels.WorkoutTemplateExercise{},
+		&workoutmodels.Program{}, &workoutmodels.ProgramWeek{}, &workoutmodels.ProgramDay{}, &workoutmodels.ProgramPrescription{}, &workoutmodels.ProgramEnrollment{},
+		&workoutmodels.PersonalRecord{}, &workoutmodels.CoachThread{}, &workoutmodels.CoachMessage{}); err != nil {
 		log.Fatalf("migration failed: %v", err)
 	}
 
+	// Convert legacy numeric details (reps, weight, ...) into typed sets, once per database
+	if n, err := workoutmigration.DetailsToSets(context.Background(), database); err != nil {
+		log.Fatalf("details to sets migration failed: %v", err)
+	} else if n > 0 {
+		log.Printf("migrated %d workout details into typed sets", n)
+	}
+	// Give single-type sessions an exercise entry so every session is multi-exercise shaped, once per database
+	if n, err := workoutmigration.SessionsToExercises(context.Background(), database); err != nil {
+		log.Fatalf("sessions to exercises migration failed: %v", err)
+	} else if n > 0 {
+		log.Printf("migrated %d workout sessions to exercise lists", n)
+	}
+
 	// build dependencies
 	userRepository := gormrepository.NewUserRepository(database)
 
-	// JWT setup
-	accessSecret := os.Getenv("ACCESS_SECRET")
-	if accessSecret == "" {
-		accessSecret = "access-secret"
-	}
-	refreshSecret := os.Getenv("REFRESH_SECRET")
-	if refreshSecret == "" {
-		refreshSecret = "refresh-secret"
+	// ADMIN_EMAIL grants the admin role to an existing account, bootstrapping user management
+	if email := os.Getenv("ADMIN_EMAIL"); email != "" {
+		if admin, err := userRepository.GetByEmail(context.Background(), email); err != nil {
+			log.Printf("warning: ADMIN_EMAIL %s: %v", email, err)
+		} else if admin.Role != models.RoleAdmin {
+			admin.Role = models.RoleAdmin
+			if err := userRepository.Update(context.Background(), admin); err != nil {
+				log.Fatalf("failed to promote %s to admin: %v", email, err)
+			}
+			log.Printf("granted admin role to %s", email)
+		}
 	}
 
+	// JWT setup: refresh and MFA challenge tokens are always signed with the secrets
+	accessSecret := requireSecret("ACCESS_SECRET")
+	refreshSecret := requireSecret("REFRESH_SECRET")
+
 	tokenManager := auth.NewManager(accessSecret, refreshSecret, 15*time.Minute, 7*24*time.Hour)
-	authService := use_case.NewAuthService(userRepository, tokenManager)
+	// JWT_SIGNING_KEY switches access tokens to RS256/EdDSA other services can verify through the
+	// JWKS endpoint; JWT_VERIFY_KEYS lists further comma-separated key files accepted during rotation
+	if signingKey := os.Getenv("JWT_SIGNING_KEY"); signingKey != "" {
+		var verifyKeys []string
+		for _, file := range strings.Split(os.Getenv("JWT_VERIFY_KEYS"), ",") {
+			if file = strings.TrimSpace(file); file != "" {
+				verifyKeys = append(verifyKeys, file)
+			}
+		}
+		keyRing, err := auth.LoadKeyRing(signingKey, verifyKeys...)
+		if err != nil {
+			log.Fatalf("JWT keys: %v", err)
+		}
+		tokenManager = auth.NewKeyRingManager(keyRing, accessSecret, refreshSecret, 15*time.Minute, 7*24*time.Hour)
+		log.Printf("signing tokens with %s key %s", keyRing.SigningKey().Algorithm, keyRing.SigningKey().ID)
+	}
+	refreshTokenRepository := gormrepository.NewRefreshTokenRepository(database)
+	loginSessionRepository := gormrepository.NewLoginSessionRepository(database)
+	authService := use_case.NewAuthService(userRepository, refreshTokenRepository, loginSessionRepository, tokenManager)
+
+	// brute-force protection: failed logins lock accounts and client addresses out for a while
+	auditLog, err := newAuditLogger()
+	if err != nil {
+		log.Fatalf("audit log setup: %v", err)
+	}
+	// LOGIN_THROTTLE_STORE keeps failure counters in the database (default), shared by all
+	// servers, or in memory
+	var loginThrottleRepository repository.LoginThrottleRepository
+	switch store := os.Getenv("LOGIN_THROTTLE_STORE"); store {
+	case "", "database":
+		loginThrottleRepository = gormrepository.NewLoginThrottleRepository(database)
+	case "memory":
+		loginThrottleRepository = memrepository.NewLoginThrottleRepository()
+	default:
+		log.Fatalf("LOGIN_THROTTLE_STORE: unknown store %q, use database or memory", store)
+	}
+	authService.SetLoginThrottle(use_case.NewLoginThrottle(loginThrottleRepository, use_case.DefaultAccountThrottle, use_case.DefaultIPThrottle, auditLog))
+
+	// mail setup
+	mailSender, err := newMailer()
+	if err != nil {
+		log.Fatalf("mail setup: %v", err)
+	}
+	passwordResetURL := os.Getenv("PASSWORD_RESET_URL")
+	if passwordResetURL == "" {
+		passwordResetURL = "http://localhost:3000/reset-password"
+	}
+	passwordResetRepository := gormrepository.NewPasswordResetRepository(database)
+	passwordResetService := use_case.NewPasswordResetService(userRepository, passwordResetRepository, authService, mailSender, passwordResetURL)
+
+	// EMAIL_VERIFICATION decides what unverified users may do: off (default), restrict or required
+	verificationPolicy := use_case.VerificationOff
+	if v := os.Getenv("EMAIL_VERIFICATION"); v != "" {
+		if verificationPolicy, err = use_case.ParseVerificationPolicy(v); err != nil {
+			log.Fatalf("EMAIL_VERIFICATION: %v", err)
+		}
+	}
+	emailVerifyURL := os.Getenv("EMAIL_VERIFY_URL")
+	if emailVerifyURL == "" {
+		emailVerifyURL = "http://localhost:8080/auth/verify"
+	}
+	emailVerificationRepository := gormrepository.NewEmailVerificationRepository(database)
+	verificationService := use_case.NewEmailVerificationService(userRepository, emailVerificationRepository, mailSender, emailVerifyURL)
+	authService.SetEmailVerification(verificationService, verificationPolicy)
+
+	// MFA_ISSUER names the service in authenticator apps
+	mfaIssuer := os.Getenv("MFA_ISSUER")
+	if mfaIssuer == "" {
+		mfaIssuer = "Fitness Tracker"
+	}
+	recoveryCodeRepository := gormrepository.NewRecoveryCodeRepository(database)
+	mfaService := use_case.NewMFAService(userRepository, recoveryCodeRepository, authService, mfaIssuer)
+
+	// social login
+	identityProviders, err := newIdentityProviders()
+	if err != nil {
+		log.Fatalf("identity provider setup: %v", err)
+	}
+	userIdentityRepository := gormrepository.NewUserIdentityRepository(database)
+	socialLoginService := use_case.NewSocialLoginService(userRepository, userIdentityRepository, gormrepository.NewOIDCLoginStateRepository(database), authService, identityProviders...)
+
+	// API keys for integrations
+	apiKeyRepository := gormrepository.NewAPIKeyRepository(database)
+	apiKeyService := use_case.NewAPIKeyService(apiKeyRepository, userRepository)
 
 	// repositories for workout domain
 	muscleGroupRepo := workoutrepo.NewMuscleGroupRepository(database)
 	workoutTypeRepo := workoutrepo.NewWorkoutTypeRepository(database)
 	workoutSessionRepo := workoutrepo.NewWorkoutSessionRepository(database)
 	workoutDetailRepo := workoutrepo.NewWorkoutDetailRepository(database)
+	workoutSetRepo := workoutrepo.NewWorkoutSetRepository(database)
+	workoutTemplateRepo := workoutrepo.NewWorkoutTemplateRepository(database)
+	programRepo := workoutrepo.NewProgramRepository(database)
+	enrollmentRepo := workoutrepo.NewProgramEnrollmentRepository(database)
+	recordRepo := workoutrepo.NewPersonalRecordRepository(database)
+	coachRepo := workoutrepo.NewCoachRepository(database)
+	recordService := workoutusecase.NewRecordService(recordRepo)
+	// ONE_REP_MAX_FORMULA picks the default e1RM formula: epley (default), brzycki or lombardi
+	oneRMFormula := workoutusecase.FormulaEpley
+	if v := os.Getenv("ONE_REP_MAX_FORMULA"); v != "" {
+		if oneRMFormula, err = workoutusecase.ParseFormula(v); err != nil {
+			log.Fatalf("ONE_REP_MAX_FORMULA: %v", err)
+		}
+	}
+	oneRMService := workoutusecase.NewOneRepMaxService(workoutSessionRepo, oneRMFormula)
+	analyticsRepo := workoutrepo.NewAnalyticsRepository(database)
 
 	// handlers
-	authMiddleware := middleware.Auth(tokenManager)
+	authMiddleware := middleware.Auth(tokenManager, middleware.WithSessionCheck(authService.SessionActive))
+	// apiAuth also accepts API keys; it guards the workout routes, which enforce key scopes
+	apiAuth := middleware.Auth(tokenManager, middleware.WithSessionCheck(authService.SessionActive), middleware.WithAPIKeys(apiKeyService.Authenticate))
+	// verifiedAuth guards features that unverified users must not use under the restrict policy
+	verifiedAuth := apiAuth
+	if verificationPolicy != use_case.VerificationOff {
+		verifiedAuth = middleware.Auth(tokenManager, middleware.WithSessionCheck(authService.SessionActive), middleware.WithAPIKeys(apiKeyService.Authenticate), middleware.WithVerifiedEmail())
+	}
 
-	userHandler := userhandler.New(userRepository)
+	// deleting an account also deletes the programs it authored, ending other users' enrollments in them
+	accountService := use_case.NewAccountService(userRepository, authService, verificationService,
+		refreshTokenRepository, loginSessionRepository, passwordResetRepository, emailVerificationRepository,
+		recoveryCodeRepository, userIdentityRepository, apiKeyRepository,
+		workoutSessionRepo, recordRepo, workoutTemplateRepo, enrollmentRepo, programRepo, coachRepo)
+	userHandler := userhandler.New(userRepository, accountService)
 	authHandler := userhandler.NewAuthHandler(authService)
+	passwordHandler := userhandler.NewPasswordHandler(passwordResetService)
+	verificationHandler := userhandler.NewVerificationHandler(verificationService)
+	mfaHandler := userhandler.NewMFAHandler(mfaService)
+	socialLoginHandler := userhandler.NewSocialLoginHandler(socialLoginService)
+	jwksHandler := userhandler.NewJWKSHandler(tokenManager)
+	apiKeyHandler := userhandler.NewAPIKeyHandler(apiKeyService)
 
 	mgHandler := workouthandler.NewMuscleGroupHandler(muscleGroupRepo)
 	wtHandler := workouthandler.NewWorkoutTypeHandler(workoutTypeRepo)
-	wsHandler := workouthandler.NewWorkoutSessionHandler(workoutSessionRepo, workoutDetailRepo)
-	ollamaURL := os.Getenv("OLLAMA_BASE_URL")
-	if ollamaURL == "" {
-		ollamaURL = "http://localhost:11434"
-	}
-	modelName := "gemma3:1b-it-qat"
-	// pull model on startup (best-effort)
-	// NOTE: that line can take significant amount of time to complete on first start or on change of modelName variable
-	// because Ollama need to download model (default model weight 1 Gb)
-	if err := suggester.EnsureModel(ollamaURL, modelName); err != nil {
-		log.Printf("warning: failed to pull model %s: %v", modelName, err)
+	wsHandler := workouthandler.NewWorkoutSessionHandler(workoutSessionRepo, workoutDetailRepo, recordService)
+	setHandler := workouthandler.NewWorkoutSetHandler(workoutSessionRepo, workoutSetRepo, recordService)
+	templateHandler := workouthandler.NewWorkoutTemplateHandler(workoutTemplateRepo, workoutSessionRepo)
+	programHandler := workouthandler.NewProgramHandler(programRepo, enrollmentRepo, workoutSessionRepo, oneRMService)
+	recordHandler := workouthandler.NewRecordHandler(recordService)
+	oneRMHandler := workouthandler.NewOneRepMaxHandler(oneRMService)
+	analyticsHandler := workoutanalytics.NewHandler(analyticsRepo)
+	llmProvider, err := newLLMProvider()
+	if err != nil {
+		log.Fatalf("LLM provider: %v", err)
 	}
 
-	suggestHandler := workouthandler.NewSuggestHandler(workoutSessionRepo, suggester.New(ollamaURL, modelName))
+	suggestHandler := workouthandler.NewSuggestHandler(workoutSessionRepo, workoutTypeRepo, suggester.New(llmProvider), oneRMService)
+	// LLM_TIMEOUT bounds model calls for suggestions before the rule engine answers instead, e.g. 30s
+	if v := os.Getenv("LLM_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Fatalf("LLM_TIMEOUT: %q is not a positive duration", v)
+		}
+		suggestHandler.SetLLMTimeout(d)
+	}
+	// LLM_CONTEXT_TOKENS is the model's context window, which long coach conversations are trimmed to
+	contextTokens := coach.DefaultContextTokens
+	if v := os.Getenv("LLM_CONTEXT_TOKENS"); v != "" {
+		if contextTokens, err = strconv.Atoi(v); err != nil || contextTokens <= 0 {
+			log.Fatalf("LLM_CONTEXT_TOKENS: %q is not a positive number", v)
+		}
+	}
+	coachHandler := workouthandler.NewCoachHandler(coachRepo, workoutSessionRepo, coach.New(llmProvider, contextTokens), oneRMService)
 
 	router := gin.Default()
+	// TRUSTED_PROXIES lists comma-separated addresses or CIDRs of reverse proxies whose
+	// X-Forwarded-For header names the client; without it the peer address is the client, so
+	// clients cannot pick the address the login throttle and session list see
+	var trustedProxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatalf("TRUSTED_PROXIES: %v", err)
+	}
 
 	// Configure CORS middleware
 	corsConfig := cors.DefaultConfig()
 	corsConfig.AllowAllOrigins = true
-	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
+	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-API-Key"}
 	corsConfig.AllowCredentials = true
 	router.Use(cors.New(corsConfig))
 
 	// register routes
 	userHandler.RegisterRoutes(router, authMiddleware)
 	authHandler.RegisterRoutes(router, authMiddleware)
+	passwordHandler.RegisterRoutes(router, authMiddleware)
+	verificationHandler.RegisterRoutes(router)
+	mfaHandler.RegisterRoutes(router, authMiddleware)
+	socialLoginHandler.RegisterRoutes(router)
+	jwksHandler.RegisterRoutes(router)
+	apiKeyHandler.RegisterRoutes(router, authMiddleware)
 
-	mgHandler.RegisterRoutes(router, authMiddleware)
-	wtHandler.RegisterRoutes(router, authMiddleware)
-	wsHandler.RegisterRoutes(router, authMiddleware)
-	suggestHandler.RegisterRoutes(router, authMiddleware)
+	mgHandler.RegisterRoutes(router, apiAuth)
+	wtHandler.RegisterRoutes(router, apiAuth)
+	wsHandler.RegisterRoutes(router, apiAuth)
+	setHandler.RegisterRoutes(router, apiAuth)
+	templateHandler.RegisterRoutes(router, apiAuth)
+	programHandler.RegisterRoutes(router, apiAuth)
+	recordHandler.RegisterRoutes(router, apiAuth)
+	oneRMHandler.RegisterRoutes(router, apiAuth)
+	analyticsHandler.RegisterRoutes(router, apiAuth)
+	suggestHandler.RegisterRoutes(router, verifiedAuth)
+	coachHandler.RegisterRoutes(router, verifiedAuth)
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler)) // Autogenerated swagger docs
 
 	listenAddr := ":8080"
@@ -132,3 +340,127 @@ func main() {
 		log.Fatalf("server error: %v", err)
 	}
 }
+
+// minSecretLength is the shortest HS256 secret accepted, 256 bits as hex or base64 characters.
+const minSecretLength = 32
+
+// requireSecret reads a token signing secret from the environment and stops the server when it
+// is missing or too short; there is no built-in default anyone could forge tokens with.
+func requireSecret(name string) string {
+	secret := os.Getenv(name)
+	if len(secret) < minSecretLength {
+		log.Fatalf("%s must be set to at least %d characters, e.g. the output of: openssl rand -hex 32", name, minSecretLength)
+	}
+	return secret
+}
+
+// newMailer builds the mail sender selected by MAIL_DRIVER: "smtp" relays through SMTP_HOST,
+// anything else ("log", the default) writes messages to MAIL_LOG_FILE or stdout.
+func newMailer() (mailer.Mailer, error) {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "Fitness Tracker <noreply@localhost>"
+	}
+	if os.Getenv("MAIL_DRIVER") == "smtp" {
+		port := 587
+		if v := os.Getenv("SMTP_PORT"); v != "" {
+			p, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("SMTP_PORT: %w", err)
+			}
+			port = p
+		}
+		return mailer.NewSMTP(os.Getenv("SMTP_HOST"), port, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), from), nil
+	}
+	if path := os.Getenv("MAIL_LOG_FILE"); path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		return mailer.NewLog(f, from), nil
+	}
+	return mailer.NewLog(os.Stdout, from), nil
+}
+
+// newAuditLogger writes audit events as JSON lines to AUDIT_LOG_FILE, or to stdout.
+func newAuditLogger() (audit.Logger, error) {
+	if path := os.Getenv("AUDIT_LOG_FILE"); path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		return audit.NewJSON(f), nil
+	}
+	return audit.NewJSON(os.Stdout), nil
+}
+
+// newIdentityProviders configures the OpenID Connect providers listed in OIDC_PROVIDERS, e.g.
+// "google,apple,corp". Each provider NAME reads OIDC_NAME_CLIENT_ID, OIDC_NAME_CLIENT_SECRET
+// and OIDC_NAME_REDIRECT_URL; providers other than google and apple also need OIDC_NAME_ISSUER.
+func newIdentityProviders() ([]oidc.Provider, error) {
+	var providers []oidc.Provider
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		env := func(key string) string { return os.Getenv("OIDC_" + strings.ToUpper(name) + "_" + key) }
+		clientID, secret, redirect := env("CLIENT_ID"), env("CLIENT_SECRET"), env("REDIRECT_URL")
+		if clientID == "" || redirect == "" {
+			return nil, fmt.Errorf("%s: client ID and redirect URL are required", name)
+		}
+
+		var cfg oidc.Config
+		switch name {
+		case "google":
+			cfg = oidc.Google(clientID, secret, redirect)
+		case "apple":
+			cfg = oidc.Apple(clientID, secret, redirect)
+		default:
+			cfg = oidc.Config{Name: name, ClientID: clientID, ClientSecret: secret, RedirectURL: redirect}
+		}
+		if issuer := env("ISSUER"); issuer != "" {
+			cfg.Issuer = issuer
+		}
+		if cfg.Issuer == "" {
+			return nil, fmt.Errorf("%s: issuer is required", name)
+		}
+		providers = append(providers, oidc.New(cfg))
+	}
+	return providers, nil
+}
+
+// newLLMProvider returns the model provider for workout suggestions selected by LLM_PROVIDER:
+// ollama (default), openai for OpenAI-compatible servers, or fake for a canned reply.
+func newLLMProvider() (provider.Provider, error) {
+	model := os.Getenv("LLM_MODEL")
+	switch name := os.Getenv("LLM_PROVIDER"); name {
+	case "", "ollama":
+		ollamaURL := os.Getenv("OLLAMA_BASE_URL")
+		if ollamaURL == "" {
+			ollamaURL = "http://localhost:11434"
+		}
+		if model == "" {
+			model = "gemma3:1b-it-qat"
+		}
+		ollama := provider.NewOllama(ollamaURL, model)
+		// pull model on startup (best-effort)
+		// NOTE: that line can take significant amount of time to complete on first start or on change of LLM_MODEL
+		// because Ollama need to download model (default model weight 1 Gb)
+		if err := ollama.EnsureModel(context.Background()); err != nil {
+			log.Printf("warning: failed to pull model %s: %v", model, err)
+		}
+		return ollama, nil
+	case "openai":
+		baseURL := os.Getenv("OPENAI_BASE_URL")
+		if baseURL == "" || model == "" {
+			return nil, errors.New("the openai provider needs OPENAI_BASE_URL and LLM_MODEL")
+		}
+		return provider.NewOpenAI(baseURL, os.Getenv("OPENAI_API_KEY"), model), nil
+	case "fake":
+		return provider.NewFake("Fake suggestion: warm up for 10 minutes, then do 3 sets of 10 squats, push-ups and rows. " +
+			"Finish with 5 minutes of stretching."), nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q, use ollama, openai or fake", name)
+	}
+}
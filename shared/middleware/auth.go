@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
@@ -9,29 +10,107 @@ import (
 	"github.com/VibeTeam/fitness-tracker-backend/user/auth"
 )
 
+// SessionCheck reports whether the login session of an access token is still active.
+type SessionCheck func(ctx context.Context, userID, sessionID uint) bool
+
+// Option customises the Auth middleware.
+type Option func(*authOptions)
+
+type authOptions struct {
+	sessionActive SessionCheck
+	verifiedOnly  bool
+	apiKey        APIKeyCheck
+}
+
+// WithSessionCheck makes Auth reject access tokens whose login session has been revoked,
+// instead of honouring them until they expire. Tokens without a session are not checked.
+func WithSessionCheck(check SessionCheck) Option {
+	return func(o *authOptions) { o.sessionActive = check }
+}
+
+// WithVerifiedEmail makes Auth reject, with 403 Forbidden, access tokens of users whose e-mail
+// address was not verified when the token was minted.
+func WithVerifiedEmail() Option {
+	return func(o *authOptions) { o.verifiedOnly = true }
+}
+
+// WithAPIKeys makes Auth accept API keys, sent in the X-API-Key header or as a Bearer token
+// starting with APIKeyPrefix, next to JWT access tokens. Routes restrict what keys may do
+// with RequireScope or RequireAccess; only use it for routes that do.
+func WithAPIKeys(check APIKeyCheck) Option {
+	return func(o *authOptions) { o.apiKey = check }
+}
+
 // Auth returns a Gin middleware that validates Bearer JWT access tokens using the provided token manager.
-// On success, the middleware stores the authenticated user ID under the key "user_id" in the Gin context.
-func Auth(tokenMgr *auth.Manager) gin.HandlerFunc {
+// On success, the middleware stores the authenticated user ID under the key "user_id" in the Gin context,
+// the user's role under "role", and the login session ID, if the token has one, under "session_id".
+// Requests authenticated with an API key have its scopes stored under "api_key_scopes" instead.
+func Auth(tokenMgr *auth.Manager, opts ...Option) gin.HandlerFunc {
+	var o authOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
 	return func(c *gin.Context) {
 		header := c.GetHeader("Authorization")
+		if o.apiKey != nil {
+			key := c.GetHeader("X-API-Key")
+			if key == "" && strings.HasPrefix(header, "Bearer "+APIKeyPrefix) {
+				key = strings.TrimPrefix(header, "Bearer ")
+			}
+			if key != "" {
+				authenticateAPIKey(c, o, key)
+				return
+			}
+		}
 		if header == "" || !strings.HasPrefix(header, "Bearer ") {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing or invalid authorization header"})
 			return
 		}
 
 		tokenStr := strings.TrimPrefix(header, "Bearer ")
-		userID, err := tokenMgr.ValidateAccessToken(tokenStr)
+		claims, err := tokenMgr.ParseAccessToken(tokenStr)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
 			return
 		}
+		userID := uint(claims.UserID)
+		if claims.SessionID != 0 && o.sessionActive != nil && !o.sessionActive(c.Request.Context(), userID, claims.SessionID) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "session has been revoked"})
+			return
+		}
+		if o.verifiedOnly && !claims.EmailVerified {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "verify your e-mail address to use this feature"})
+			return
+		}
 
 		// Store the authenticated user ID for downstream handlers
-		c.Set("user_id", uint(userID))
+		c.Set("user_id", userID)
+		c.Set("role", claims.Role)
+		if claims.SessionID != 0 {
+			c.Set("session_id", claims.SessionID)
+		}
 		c.Next()
 	}
 }
 
+// authenticateAPIKey finishes Auth for a request carrying an API key.
+func authenticateAPIKey(c *gin.Context, o authOptions, key string) {
+	principal, ok := o.apiKey(c.Request.Context(), key)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired API key"})
+		return
+	}
+	if o.verifiedOnly && !principal.EmailVerified {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "verify your e-mail address to use this feature"})
+		return
+	}
+
+	c.Set("user_id", principal.UserID)
+	c.Set("role", principal.Role)
+	c.Set("api_key_scopes", principal.Scopes)
+	c.Next()
+}
+
 // UserID retrieves the authenticated user ID from Gin context if present.
 func UserID(c *gin.Context) (uint, bool) {
 	v, ok := c.Get("user_id")
@@ -41,3 +120,56 @@ func UserID(c *gin.Context) (uint, bool) {
 	id, ok := v.(uint)
 	return id, ok
 }
+
+// RequireRole returns a Gin middleware that only lets through users holding one of the given roles.
+// It must run after Auth; other callers are rejected with 403 Forbidden.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := Role(c)
+		for _, r := range roles {
+			if role == r {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient role"})
+	}
+}
+
+// RoleLookup returns the role currently stored for a user.
+type RoleLookup func(ctx context.Context, userID uint) (string, error)
+
+// RequireCurrentRole is RequireRole checked against the role stored for the user instead of
+// the one minted into the access token, so demoting a user takes effect immediately rather
+// than when their token expires. Users whose role cannot be looked up are rejected.
+func RequireCurrentRole(lookup RoleLookup, roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := UserID(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient role"})
+			return
+		}
+		role, err := lookup(c.Request.Context(), userID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient role"})
+			return
+		}
+		c.Set("role", role)
+		RequireRole(roles...)(c)
+	}
+}
+
+// Role retrieves the authenticated user's role from Gin context; it is empty if unknown.
+func Role(c *gin.Context) string {
+	return c.GetString("role")
+}
+
+// SessionID retrieves the login session ID of the access token from Gin context if present.
+func SessionID(c *gin.Context) (uint, bool) {
+	v, ok := c.Get("session_id")
+	if !ok {
+		return 0, false
+	}
+	id, ok := v.(uint)
+	return id, ok
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -68,3 +70,153 @@ func TestAuthMiddleware_InvalidToken(t *testing.T) {
 	r.ServeHTTP(w, req)
 	require.Equal(t, http.StatusUnauthorized, w.Code)
 }
+
+func TestAuthMiddleware_RevokedSession(t *testing.T) {
+	mgr := auth.NewManager("a", "b", time.Minute, time.Hour)
+	revoked := map[uint]bool{7: true}
+
+	r := gin.New()
+	r.Use(Auth(mgr, WithSessionCheck(func(_ context.Context, _, sessionID uint) bool {
+		return !revoked[sessionID]
+	})))
+	r.GET("/ping", func(c *gin.Context) {
+		sid, _ := SessionID(c)
+		c.String(http.StatusOK, "sid=%d", sid)
+	})
+
+	for _, tc := range []struct {
+		sessionID uint
+		code      int
+	}{{5, http.StatusOK}, {7, http.StatusUnauthorized}, {0, http.StatusOK}} {
+		access, _, err := mgr.NewTokensFor(auth.Subject{UserID: 42, SessionID: tc.sessionID})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set("Authorization", "Bearer "+access)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+		require.Equal(t, tc.code, w.Code, "session %d", tc.sessionID)
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	mgr := auth.NewManager("a", "b", time.Minute, time.Hour)
+
+	r := gin.New()
+	r.GET("/admin", Auth(mgr), RequireRole("admin"), func(c *gin.Context) {
+		c.String(http.StatusOK, "role=%s", Role(c))
+	})
+
+	for role, code := range map[string]int{"admin": http.StatusOK, "user": http.StatusForbidden, "": http.StatusForbidden} {
+		access, _, err := mgr.NewTokensFor(auth.Subject{UserID: 42, Role: role})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		req.Header.Set("Authorization", "Bearer "+access)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+		require.Equal(t, code, w.Code, "role %q", role)
+	}
+}
+
+func TestRequireCurrentRole(t *testing.T) {
+	mgr := auth.NewManager("a", "b", time.Minute, time.Hour)
+	stored := map[uint]string{1: "admin", 2: "user"}
+	lookup := func(_ context.Context, userID uint) (string, error) {
+		role, ok := stored[userID]
+		if !ok {
+			return "", errors.New("not found")
+		}
+		return role, nil
+	}
+
+	r := gin.New()
+	r.GET("/admin", Auth(mgr), RequireCurrentRole(lookup, "admin"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	// user 2 was demoted after the token was minted; user 3 no longer exists
+	for userID, code := range map[int32]int{1: http.StatusOK, 2: http.StatusForbidden, 3: http.StatusForbidden} {
+		access, _, err := mgr.NewTokensFor(auth.Subject{UserID: userID, Role: "admin"})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		req.Header.Set("Authorization", "Bearer "+access)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+		require.Equal(t, code, w.Code, "user %d", userID)
+	}
+}
+
+func TestAuthMiddleware_VerifiedEmail(t *testing.T) {
+	mgr := auth.NewManager("a", "b", time.Minute, time.Hour)
+
+	r := gin.New()
+	r.GET("/ping", Auth(mgr, WithVerifiedEmail()), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for verified, code := range map[bool]int{true: http.StatusOK, false: http.StatusForbidden} {
+		access, _, err := mgr.NewTokensFor(auth.Subject{UserID: 42, EmailVerified: verified})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set("Authorization", "Bearer "+access)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+		require.Equal(t, code, w.Code, "verified %v", verified)
+	}
+}
+
+func TestAuthMiddleware_APIKeys(t *testing.T) {
+	mgr := auth.NewManager("a", "b", time.Minute, time.Hour)
+	keys := map[string]APIKeyPrincipal{
+		APIKeyPrefix + "reader": {UserID: 7, Scopes: []string{ScopeSessionsRead}},
+		APIKeyPrefix + "writer": {UserID: 8, Scopes: []string{ScopeSessionsRead, ScopeSessionsWrite}},
+	}
+
+	r := gin.New()
+	g := r.Group("/sessions", Auth(mgr, WithAPIKeys(func(_ context.Context, key string) (APIKeyPrincipal, bool) {
+		p, ok := keys[key]
+		return p, ok
+	})), RequireAccess(ScopeSessionsRead, ScopeSessionsWrite))
+	echo := func(c *gin.Context) {
+		uid, _ := UserID(c)
+		c.String(http.StatusOK, "uid=%d", uid)
+	}
+	g.GET("", echo)
+	g.POST("", echo)
+
+	access, _, err := mgr.NewTokens(42)
+	require.NoError(t, err)
+	for _, tc := range []struct {
+		method, header, value string
+		code                  int
+	}{
+		{http.MethodGet, "X-API-Key", APIKeyPrefix + "reader", http.StatusOK},
+		{http.MethodGet, "Authorization", "Bearer " + APIKeyPrefix + "reader", http.StatusOK},
+		{http.MethodPost, "X-API-Key", APIKeyPrefix + "reader", http.StatusForbidden},
+		{http.MethodPost, "Authorization", "Bearer " + APIKeyPrefix + "writer", http.StatusOK},
+		{http.MethodGet, "X-API-Key", APIKeyPrefix + "revoked", http.StatusUnauthorized},
+		// Access tokens are not limited by scopes.
+		{http.MethodPost, "Authorization", "Bearer " + access, http.StatusOK},
+	} {
+		req := httptest.NewRequest(tc.method, "/sessions", nil)
+		req.Header.Set(tc.header, tc.value)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+		require.Equal(t, tc.code, w.Code, "%s with %s", tc.method, tc.value)
+	}
+
+	// Without WithAPIKeys an API key is just an invalid token.
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set("Authorization", "Bearer "+APIKeyPrefix+"reader")
+	w := httptest.NewRecorder()
+	newRouter(mgr).ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyPrefix starts every API key, which tells them apart from JWTs in the Authorization header.
+const APIKeyPrefix = "ftk_"
+
+// API key scopes. Read scopes allow GET requests on their resources, write scopes all other
+// methods; a key that writes usually needs the read scope as well.
+const (
+	// ScopeSessionsRead covers workout sessions and sets, personal records, e1RM series and analytics.
+	ScopeSessionsRead  = "sessions:read"
+	ScopeSessionsWrite = "sessions:write"
+	// ScopeTemplatesRead covers workout templates; starting one creates a workout session.
+	ScopeTemplatesRead  = "templates:read"
+	ScopeTemplatesWrite = "templates:write"
+	// ScopeProgramsRead covers training programs and enrollments.
+	ScopeProgramsRead  = "programs:read"
+	ScopeProgramsWrite = "programs:write"
+	// ScopeCatalogRead covers muscle groups and workout types.
+	ScopeCatalogRead  = "catalog:read"
+	ScopeCatalogWrite = "catalog:write"
+	// ScopeSuggestionsRead covers AI workout suggestions.
+	ScopeSuggestionsRead = "suggestions:read"
+	// ScopeCoachRead covers AI coach threads; writing includes chatting with the coach.
+	ScopeCoachRead  = "coach:read"
+	ScopeCoachWrite = "coach:write"
+)
+
+// Scopes lists every scope an API key can be granted.
+var Scopes = []string{
+	ScopeSessionsRead, ScopeSessionsWrite,
+	ScopeTemplatesRead, ScopeTemplatesWrite,
+	ScopeProgramsRead, ScopeProgramsWrite,
+	ScopeCatalogRead, ScopeCatalogWrite,
+	ScopeSuggestionsRead,
+	ScopeCoachRead, ScopeCoachWrite,
+}
+
+// KnownScope reports whether scope is one of Scopes.
+func KnownScope(scope string) bool {
+	return slices.Contains(Scopes, scope)
+}
+
+// APIKeyPrincipal is the user behind a valid API key and what the key may do.
+type APIKeyPrincipal struct {
+	UserID        uint
+	Role          string
+	EmailVerified bool
+	Scopes        []string
+}
+
+// APIKeyCheck resolves an API key; it reports false for unknown, malformed and expired keys.
+type APIKeyCheck func(ctx context.Context, key string) (APIKeyPrincipal, bool)
+
+// RequireScope returns a Gin middleware that rejects, with 403 Forbidden, requests made with an
+// API key that lacks scope. It must run after Auth; requests with access tokens pass.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if scopes, ok := APIKeyScopes(c); ok && !slices.Contains(scopes, scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "API key lacks scope " + scope})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireAccess is RequireScope with the read scope for GET and HEAD requests and the write
+// scope for all others.
+func RequireAccess(read, write string) gin.HandlerFunc {
+	readOnly, readWrite := RequireScope(read), RequireScope(write)
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			readOnly(c)
+			return
+		}
+		readWrite(c)
+	}
+}
+
+// APIKeyScopes retrieves the scopes of the API key the request was authenticated with; ok is
+// false for requests with an access token.
+func APIKeyScopes(c *gin.Context) (scopes []string, ok bool) {
+	v, ok := c.Get("api_key_scopes")
+	if !ok {
+		return nil, false
+	}
+	scopes, ok = v.([]string)
+	return scopes, ok
+}
@@ -0,0 +1,129 @@
+// Package pagination implements keyset (cursor) pagination shared by all list endpoints.
+//
+// A cursor marks the last item of a page by its sort key: a timestamp plus the row ID for
+// time-ordered lists, a text key plus the row ID for lists ordered by another column such as
+// a name, or the ID alone for ID-ordered ones. Unlike offsets, cursors keep
+// pages stable while rows are inserted or deleted between requests.
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultLimit is the page size used when the client does not ask for one.
+	DefaultLimit = 100
+	// MaxLimit caps the page size a client may request.
+	MaxLimit = 500
+)
+
+// ErrInvalidCursor is returned when a cursor cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is the sort key of the last item of a page. Time is set for time-ordered lists and
+// Key for lists ordered by another column; both are empty for ID-ordered lists.
+type Cursor struct {
+	Time time.Time
+	Key  string
+	ID   uint
+}
+
+// Encode returns the opaque string form of the cursor.
+func (c Cursor) Encode() string {
+	raw := strconv.FormatUint(uint64(c.ID), 10)
+	switch {
+	case c.Key != "":
+		raw = keyPrefix + base64.RawURLEncoding.EncodeToString([]byte(c.Key)) + "." + raw
+	case !c.Time.IsZero():
+		raw = strconv.FormatInt(c.Time.UnixNano(), 10) + "." + raw
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// keyPrefix marks the text key of a cursor in its encoded form.
+const keyPrefix = "~"
+
+// Decode parses a cursor produced by Encode.
+func Decode(s string) (Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	var c Cursor
+	idPart := string(b)
+	if sortKey, id, ok := strings.Cut(idPart, "."); ok {
+		if key, isKey := strings.CutPrefix(sortKey, keyPrefix); isKey {
+			k, err := base64.RawURLEncoding.DecodeString(key)
+			if err != nil || len(k) == 0 {
+				return Cursor{}, ErrInvalidCursor
+			}
+			c.Key = string(k)
+		} else {
+			nanos, err := strconv.ParseInt(sortKey, 10, 64)
+			if err != nil {
+				return Cursor{}, ErrInvalidCursor
+			}
+			c.Time = time.Unix(0, nanos).UTC()
+		}
+		idPart = id
+	}
+	id, err := strconv.ParseUint(idPart, 10, 64)
+	if err != nil || id == 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+	c.ID = uint(id)
+	return c, nil
+}
+
+// Params are the paging parameters of a list request.
+type Params struct {
+	Limit int
+	// After is the cursor of the last item already seen; nil requests the first page.
+	After *Cursor
+}
+
+// Parse reads the raw limit and cursor query values of a request. An empty limit means
+// DefaultLimit; larger values are capped at MaxLimit.
+func Parse(limit, cursor string) (Params, error) {
+	p := Params{Limit: DefaultLimit}
+	if limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			return p, errors.New("limit must be a positive integer")
+		}
+		p.Limit = min(n, MaxLimit)
+	}
+	if cursor != "" {
+		c, err := Decode(cursor)
+		if err != nil {
+			return p, err
+		}
+		p.After = &c
+	}
+	return p, nil
+}
+
+// Page is the common list response envelope.
+type Page[T any] struct {
+	Items []T `json:"items"`
+	// NextCursor fetches the following page; it is empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewPage builds a page from items fetched with a limit of p.Limit+1: the extra item only
+// signals that another page exists and is dropped. key returns the cursor of an item.
+func NewPage[T any](items []T, p Params, key func(T) Cursor) Page[T] {
+	page := Page[T]{Items: items}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	if len(items) > p.Limit {
+		page.Items = items[:p.Limit]
+		page.NextCursor = key(page.Items[p.Limit-1]).Encode()
+	}
+	return page
+}
@@ -0,0 +1,61 @@
+package pagination
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	for _, c := range []Cursor{
+		{ID: 7},
+		{Time: time.Date(2025, 3, 4, 5, 6, 7, 8, time.UTC), ID: 42},
+		{Key: "Push day. Heavy", ID: 9},
+	} {
+		got, err := Decode(c.Encode())
+		require.NoError(t, err)
+		require.Equal(t, c, got)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	for _, s := range []string{"!!", "YWJj", "MC4w", "", "fiEuMw"} {
+		_, err := Decode(s)
+		require.ErrorIs(t, err, ErrInvalidCursor, s)
+	}
+}
+
+func TestParse(t *testing.T) {
+	p, err := Parse("", "")
+	require.NoError(t, err)
+	require.Equal(t, DefaultLimit, p.Limit)
+	require.Nil(t, p.After)
+
+	p, err = Parse("10000", Cursor{ID: 3}.Encode())
+	require.NoError(t, err)
+	require.Equal(t, MaxLimit, p.Limit)
+	require.Equal(t, uint(3), p.After.ID)
+
+	_, err = Parse("0", "")
+	require.Error(t, err)
+	_, err = Parse("5", "nope")
+	require.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestNewPage(t *testing.T) {
+	key := func(n int) Cursor { return Cursor{ID: uint(n)} }
+	p := Params{Limit: 2}
+
+	page := NewPage([]int{1, 2, 3}, p, key)
+	require.Equal(t, []int{1, 2}, page.Items)
+	require.Equal(t, Cursor{ID: 2}.Encode(), page.NextCursor)
+
+	page = NewPage([]int{1, 2}, p, key)
+	require.Equal(t, []int{1, 2}, page.Items)
+	require.Empty(t, page.NextCursor)
+
+	page = NewPage[int](nil, p, key)
+	require.NotNil(t, page.Items)
+	require.Empty(t, page.NextCursor)
+}
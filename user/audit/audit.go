@@ -0,0 +1,60 @@
+// Package audit records security-relevant events such as failed logins and account lockouts.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Event types.
+const (
+	// LoginFailed is a login with a wrong e-mail address or password.
+	LoginFailed = "login_failed"
+	// MFAFailed is a wrong second factor for an otherwise valid login.
+	MFAFailed = "mfa_failed"
+	// LoginLocked is a lockout imposed after repeated failures.
+	LoginLocked = "login_locked"
+	// LoginRejected is an attempt refused because of an active lockout.
+	LoginRejected = "login_rejected"
+)
+
+// Event is one audit record.
+type Event struct {
+	Type   string    `json:"type"`
+	Time   time.Time `json:"time"`
+	UserID uint      `json:"user_id,omitempty"`
+	Email  string    `json:"email,omitempty"`
+	IP     string    `json:"ip,omitempty"`
+	// Detail adds context, e.g. which lockout applies and for how long.
+	Detail string `json:"detail,omitempty"`
+}
+
+// Logger records audit events. Recording must not fail the operation being audited, so
+// implementations handle their own errors.
+type Logger interface {
+	Record(ctx context.Context, e Event)
+}
+
+// JSONLogger writes events to a writer as JSON lines, ready for a log shipper.
+type JSONLogger struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSON returns a Logger that writes one JSON object per event to w.
+func NewJSON(w io.Writer) *JSONLogger {
+	return &JSONLogger{w: w}
+}
+
+func (l *JSONLogger) Record(_ context.Context, e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	line, _ := json.Marshal(e)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = l.w.Write(append(line, '\n'))
+}
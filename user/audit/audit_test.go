@@ -0,0 +1,29 @@
+package audit_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/audit"
+)
+
+func TestJSONLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := audit.NewJSON(&buf)
+	l.Record(context.Background(), audit.Event{Type: audit.LoginFailed, Email: "a@b.c", IP: "10.0.0.1"})
+	l.Record(context.Background(), audit.Event{Type: audit.LoginLocked, Email: "a@b.c", Detail: "account locked for 30s"})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	var e audit.Event
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &e))
+	require.Equal(t, audit.LoginFailed, e.Type)
+	require.Equal(t, "10.0.0.1", e.IP)
+	require.False(t, e.Time.IsZero(), "events are timestamped")
+	require.NotContains(t, lines[0], "user_id", "empty fields are omitted")
+}
@@ -0,0 +1,200 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSABits is the smallest RSA modulus accepted for signing or verification keys.
+const minRSABits = 2048
+
+// Key is a public key tokens can be verified with, and for the signing key also its private half.
+type Key struct {
+	// ID is the key's RFC 7638 thumbprint; tokens name it in their kid header.
+	ID string
+	// Algorithm is the JWS algorithm: RS256 for RSA keys, EdDSA for Ed25519 keys.
+	Algorithm string
+	public    crypto.PublicKey
+	private   crypto.PrivateKey
+}
+
+// KeyRing holds the key new tokens are signed with and every key tokens are still accepted
+// from. Rotating keys takes three deployments: publish the new key as a verification key,
+// make it the signing key while the old one stays for verification, and drop the old key
+// once the last refresh token signed with it has expired.
+type KeyRing struct {
+	signing *Key
+	keys    []*Key
+}
+
+// NewKeyRing builds a key ring from an RSA or Ed25519 signing key and any number of public
+// keys that are only used for verification.
+func NewKeyRing(signing crypto.Signer, verifyOnly ...crypto.PublicKey) (*KeyRing, error) {
+	k, err := newKey(signing.Public())
+	if err != nil {
+		return nil, err
+	}
+	k.private = signing
+	r := &KeyRing{signing: k, keys: []*Key{k}}
+	for _, pub := range verifyOnly {
+		k, err := newKey(pub)
+		if err != nil {
+			return nil, err
+		}
+		if r.Key(k.ID) == nil {
+			r.keys = append(r.keys, k)
+		}
+	}
+	return r, nil
+}
+
+// LoadKeyRing reads a key ring from PEM files: a private signing key (PKCS #8 or PKCS #1) and
+// verification keys as public keys (PKIX) or private keys, of which only the public half is kept.
+func LoadKeyRing(signingKeyFile string, verifyKeyFiles ...string) (*KeyRing, error) {
+	key, err := readPEMKey(signingKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%s: signing key must be a private key", signingKeyFile)
+	}
+	var verifyOnly []crypto.PublicKey
+	for _, file := range verifyKeyFiles {
+		key, err := readPEMKey(file)
+		if err != nil {
+			return nil, err
+		}
+		if s, ok := key.(crypto.Signer); ok {
+			key = s.Public()
+		}
+		verifyOnly = append(verifyOnly, key)
+	}
+	return NewKeyRing(signer, verifyOnly...)
+}
+
+// SigningKey returns the key new tokens are signed with.
+func (r *KeyRing) SigningKey() *Key {
+	return r.signing
+}
+
+// Key returns the key with the given ID, or nil.
+func (r *KeyRing) Key(id string) *Key {
+	for _, k := range r.keys {
+		if k.ID == id {
+			return k
+		}
+	}
+	return nil
+}
+
+// JWK is a public key in JSON Web Key format (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// N and E are the RSA modulus and exponent.
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Crv and X are the curve and public key of Ed25519 keys.
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public halves of all keys in the ring, signing key first.
+func (r *KeyRing) JWKS() JWKS {
+	set := JWKS{Keys: make([]JWK, 0, len(r.keys))}
+	for _, k := range r.keys {
+		set.Keys = append(set.Keys, k.jwk())
+	}
+	return set
+}
+
+func (k *Key) jwk() JWK {
+	j := JWK{Kid: k.ID, Use: "sig", Alg: k.Algorithm}
+	switch pub := k.public.(type) {
+	case *rsa.PublicKey:
+		j.Kty, j.N, j.E = "RSA", b64(pub.N.Bytes()), b64(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		j.Kty, j.Crv, j.X = "OKP", "Ed25519", b64(pub)
+	}
+	return j
+}
+
+// method returns the jwt signing method of the key.
+func (k *Key) method() jwt.SigningMethod {
+	return jwt.GetSigningMethod(k.Algorithm)
+}
+
+// newKey derives algorithm and key ID of a supported public key.
+func newKey(pub crypto.PublicKey) (*Key, error) {
+	k := &Key{public: pub}
+	var thumbprint string
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("RSA key of %d bits is too short, use at least %d", pub.N.BitLen(), minRSABits)
+		}
+		k.Algorithm = jwt.SigningMethodRS256.Alg()
+		thumbprint = fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, b64(big.NewInt(int64(pub.E)).Bytes()), b64(pub.N.Bytes()))
+	case ed25519.PublicKey:
+		k.Algorithm = jwt.SigningMethodEdDSA.Alg()
+		thumbprint = fmt.Sprintf(`{"crv":"Ed25519","kty":"OKP","x":"%s"}`, b64(pub))
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", pub)
+	}
+	sum := sha256.Sum256([]byte(thumbprint))
+	k.ID = b64(sum[:])
+	return k, nil
+}
+
+// readPEMKey reads the first key from a PEM file.
+func readPEMKey(file string) (any, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM data", file)
+	}
+	var key any
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		err = errors.New("unsupported PEM block " + strings.ToLower(block.Type))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return key, nil
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,148 @@
+package auth_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/auth"
+)
+
+// writeKey stores key as a PKCS #8 private or PKIX public PEM file and returns its path.
+func writeKey(t *testing.T, name string, key any) string {
+	var block *pem.Block
+	if pub, ok := key.(ed25519.PublicKey); ok {
+		der, err := x509.MarshalPKIXPublicKey(pub)
+		require.NoError(t, err)
+		block = &pem.Block{Type: "PUBLIC KEY", Bytes: der}
+	} else {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		require.NoError(t, err)
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	}
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(block), 0o600))
+	return path
+}
+
+func TestKeyRingRotation(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	rsaFile, edFile := writeKey(t, "old.pem", rsaKey), writeKey(t, "new.pem", edKey)
+
+	// Before the rotation the RSA key signs and the Ed25519 key is published in advance.
+	before, err := auth.LoadKeyRing(rsaFile, writeKey(t, "new.pub", edPub))
+	require.NoError(t, err)
+	oldMgr := auth.NewKeyRingManager(before, "access-secret", "refresh-secret", time.Minute, time.Hour)
+	jwks := oldMgr.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	require.Equal(t, "RS256", jwks.Keys[0].Alg)
+	require.Equal(t, "EdDSA", jwks.Keys[1].Alg)
+	require.Equal(t, "Ed25519", jwks.Keys[1].Crv)
+
+	oldAccess, oldRefresh, err := oldMgr.NewTokens(1)
+	require.NoError(t, err)
+	tok, _, err := jwt.NewParser().ParseUnverified(oldAccess, &auth.Claims{})
+	require.NoError(t, err)
+	require.Equal(t, jwks.Keys[0].Kid, tok.Header["kid"])
+
+	// After the rotation the Ed25519 key signs; tokens from the RSA key stay valid.
+	after, err := auth.LoadKeyRing(edFile, rsaFile)
+	require.NoError(t, err)
+	newMgr := auth.NewKeyRingManager(after, "access-secret", "refresh-secret", time.Minute, time.Hour)
+	require.Equal(t, jwks.Keys[1].Kid, newMgr.JWKS().Keys[0].Kid, "key IDs do not depend on the file")
+
+	uid, err := newMgr.ValidateAccessToken(oldAccess)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), uid)
+	claims, err := newMgr.ParseRefreshToken(oldRefresh)
+	require.NoError(t, err)
+	newAccess, _, err := newMgr.NewTokensFor(claims.Subject())
+	require.NoError(t, err)
+	_, err = newMgr.ValidateAccessToken(newAccess)
+	require.NoError(t, err)
+
+	// Once the RSA key is dropped its tokens are refused.
+	dropped, err := auth.NewKeyRing(edKey)
+	require.NoError(t, err)
+	_, err = auth.NewKeyRingManager(dropped, "access-secret", "refresh-secret", time.Minute, time.Hour).ValidateAccessToken(oldAccess)
+	require.Error(t, err)
+}
+
+func TestKeyRingRejectsForgedTokens(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ring, err := auth.NewKeyRing(rsaKey)
+	require.NoError(t, err)
+	mgr := auth.NewKeyRingManager(ring, "access-secret", "refresh-secret", time.Minute, time.Hour)
+	kid := ring.SigningKey().ID
+	claims := &auth.Claims{UserID: 1, Type: auth.AccessToken, RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))}}
+
+	// HS256 keyed with the published public key must not pass as RS256.
+	pubDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	hs := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	hs.Header["kid"] = kid
+	forged, err := hs.SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}))
+	require.NoError(t, err)
+	_, err = mgr.ValidateAccessToken(forged)
+	require.Error(t, err)
+
+	// Neither may a token signed by another key that claims our key ID.
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rs := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	rs.Header["kid"] = kid
+	forged, err = rs.SignedString(otherKey)
+	require.NoError(t, err)
+	_, err = mgr.ValidateAccessToken(forged)
+	require.Error(t, err)
+
+	// Short RSA keys are refused outright.
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	_, err = auth.NewKeyRing(weak)
+	require.Error(t, err)
+}
+
+func TestKeyRingSignsOnlyAccessTokens(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	ring, err := auth.NewKeyRing(edKey)
+	require.NoError(t, err)
+	mgr := auth.NewKeyRingManager(ring, "access-secret", "refresh-secret", time.Minute, time.Hour)
+
+	access, refresh, err := mgr.NewTokens(1)
+	require.NoError(t, err)
+	challenge, err := mgr.NewMFAToken(1, "")
+	require.NoError(t, err)
+
+	tok, _, err := jwt.NewParser().ParseUnverified(access, &auth.Claims{})
+	require.NoError(t, err)
+	require.Equal(t, "EdDSA", tok.Method.Alg())
+
+	// Refresh and challenge tokens must not verify with the published keys.
+	for _, s := range []string{refresh, challenge} {
+		tok, _, err := jwt.NewParser().ParseUnverified(s, &auth.Claims{})
+		require.NoError(t, err)
+		require.Equal(t, "HS256", tok.Method.Alg())
+		require.NotContains(t, tok.Header, "kid")
+	}
+	_, err = mgr.ParseRefreshToken(refresh)
+	require.NoError(t, err)
+	_, err = mgr.ParseMFAToken(challenge)
+	require.NoError(t, err)
+	_, err = mgr.ValidateAccessToken(refresh)
+	require.Error(t, err)
+}
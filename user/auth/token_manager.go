@@ -1,31 +1,64 @@
 package auth
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// TokenType distinguishes between access and refresh tokens.
+// TokenType distinguishes between access, refresh and MFA challenge tokens.
 type TokenType string
 
 const (
 	AccessToken  TokenType = "access"
 	RefreshToken TokenType = "refresh"
+	// MFAToken proves a correct password and is exchanged for a token pair with a second factor.
+	MFAToken TokenType = "mfa"
 )
 
+// MFATokenTTL is how long a user has to enter the second factor after the password.
+const MFATokenTTL = 5 * time.Minute
+
 // Claims represents the JWT payload used across the system.
 type Claims struct {
 	UserID int32     `json:"user_id"`
 	Type   TokenType `json:"type"`
+	// SessionID is the login session the token belongs to; zero for tokens minted without one.
+	SessionID uint `json:"sid,omitempty"`
+	// Role is the user's role when the token was minted; role changes apply on the next refresh.
+	Role string `json:"role,omitempty"`
+	// EmailVerified tells whether the user's e-mail address was verified when the token was minted.
+	EmailVerified bool `json:"ev,omitempty"`
+	// DeviceName carries the device of an MFA challenge over to the session it completes.
+	DeviceName string `json:"dev,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// Subject is what a token pair states about its bearer.
+type Subject struct {
+	UserID        int32
+	SessionID     uint
+	Role          string
+	EmailVerified bool
+}
+
+// Subject returns the bearer described by the claims.
+func (c *Claims) Subject() Subject {
+	return Subject{UserID: c.UserID, SessionID: c.SessionID, Role: c.Role, EmailVerified: c.EmailVerified}
+}
+
 // Manager handles creation and verification of JWT access/refresh tokens.
 type Manager struct {
-	accessSecret    []byte
-	refreshSecret   []byte
+	accessSecret  []byte
+	refreshSecret []byte
+	// keys replaces the access secret for access tokens when set: they are signed with its
+	// signing key and verified against its public keys, so other services can check them
+	// without a secret. Refresh and MFA challenge tokens stay on the secrets, so a service
+	// trusting the published keys never accepts them.
+	keys            *KeyRing
 	accessTokenTTL  time.Duration
 	refreshTokenTTL time.Duration
 }
@@ -41,13 +74,37 @@ func NewManager(accessSecret, refreshSecret string, accessTTL, refreshTTL time.D
 	}
 }
 
+// NewKeyRingManager builds a Manager that signs access tokens with the key ring's signing key
+// and names it in the kid header. Refresh tokens and MFA challenge tokens are only ever read
+// by this service and keep using the secrets.
+func NewKeyRingManager(keys *KeyRing, accessSecret, refreshSecret string, accessTTL, refreshTTL time.Duration) *Manager {
+	m := NewManager(accessSecret, refreshSecret, accessTTL, refreshTTL)
+	m.keys = keys
+	return m
+}
+
+// JWKS returns the public keys access tokens can be verified with; it is empty for a Manager
+// using shared secrets.
+func (m *Manager) JWKS() JWKS {
+	if m.keys == nil {
+		return JWKS{Keys: []JWK{}}
+	}
+	return m.keys.JWKS()
+}
+
 // NewTokens returns freshly minted access and refresh tokens for the given user.
 func (m *Manager) NewTokens(userID int32) (string, string, error) {
-	access, err := m.newToken(userID, AccessToken, m.accessTokenTTL, m.accessSecret)
+	return m.NewTokensFor(Subject{UserID: userID})
+}
+
+// NewTokensFor is NewTokens for tokens carrying the full subject: login session, role and
+// e-mail verification state.
+func (m *Manager) NewTokensFor(sub Subject) (string, string, error) {
+	access, err := m.newToken(sub, AccessToken, m.accessTokenTTL, m.accessSecret)
 	if err != nil {
 		return "", "", err
 	}
-	refresh, err := m.newToken(userID, RefreshToken, m.refreshTokenTTL, m.refreshSecret)
+	refresh, err := m.newToken(sub, RefreshToken, m.refreshTokenTTL, m.refreshSecret)
 	if err != nil {
 		return "", "", err
 	}
@@ -56,46 +113,94 @@ func (m *Manager) NewTokens(userID int32) (string, string, error) {
 
 // ValidateAccessToken parses and validates access token, returning embedded user ID.
 func (m *Manager) ValidateAccessToken(tokenStr string) (int32, error) {
-	claims, err := m.parseToken(tokenStr, m.accessSecret, AccessToken)
+	claims, err := m.ParseAccessToken(tokenStr)
 	if err != nil {
 		return 0, err
 	}
 	return claims.UserID, nil
 }
 
-// RefreshTokens validates provided refresh token and returns a new token pair.
-func (m *Manager) RefreshTokens(refreshToken string) (string, string, error) {
-	claims, err := m.parseToken(refreshToken, m.refreshSecret, RefreshToken)
-	if err != nil {
-		return "", "", err
-	}
-	return m.NewTokens(claims.UserID)
+// ParseAccessToken parses and validates access token, returning all of its claims.
+func (m *Manager) ParseAccessToken(tokenStr string) (*Claims, error) {
+	return m.parseToken(tokenStr, m.accessSecret, AccessToken)
+}
+
+// RefreshTTL reports how long freshly minted refresh tokens stay valid.
+func (m *Manager) RefreshTTL() time.Duration {
+	return m.refreshTokenTTL
+}
+
+// ParseRefreshToken verifies the signature, expiry and kind of a refresh token and returns its claims.
+// It does not know about revocation; callers that persist refresh tokens must check that themselves.
+func (m *Manager) ParseRefreshToken(tokenStr string) (*Claims, error) {
+	return m.parseToken(tokenStr, m.refreshSecret, RefreshToken)
+}
+
+// NewMFAToken returns an MFA challenge token for a user whose password has been checked.
+// It is signed with the access secret but is not accepted as an access token.
+func (m *Manager) NewMFAToken(userID int32, deviceName string) (string, error) {
+	return m.sign(&Claims{UserID: userID, Type: MFAToken, DeviceName: deviceName}, MFATokenTTL, m.accessSecret)
+}
+
+// ParseMFAToken verifies the signature, expiry and kind of an MFA challenge token.
+func (m *Manager) ParseMFAToken(tokenStr string) (*Claims, error) {
+	return m.parseToken(tokenStr, m.accessSecret, MFAToken)
 }
 
 // --- internals ---
 
-func (m *Manager) newToken(userID int32, ttype TokenType, ttl time.Duration, secret []byte) (string, error) {
+func (m *Manager) newToken(sub Subject, ttype TokenType, ttl time.Duration, secret []byte) (string, error) {
+	return m.sign(&Claims{
+		UserID:        sub.UserID,
+		Type:          ttype,
+		SessionID:     sub.SessionID,
+		Role:          sub.Role,
+		EmailVerified: sub.EmailVerified,
+	}, ttl, secret)
+}
+
+// sign stamps the registered claims and signs access tokens with the key ring's signing key
+// and everything else, or everything without a key ring, with secret.
+func (m *Manager) sign(claims *Claims, ttl time.Duration, secret []byte) (string, error) {
 	now := time.Now()
-	claims := &Claims{
-		UserID: userID,
-		Type:   ttype,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
-			IssuedAt:  jwt.NewNumericDate(now),
-		},
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		// A random ID keeps tokens minted within the same second distinct.
+		ID:        newTokenID(),
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		IssuedAt:  jwt.NewNumericDate(now),
 	}
 
+	if m.signsWithKeys(claims.Type) {
+		key := m.keys.SigningKey()
+		token := jwt.NewWithClaims(key.method(), claims)
+		token.Header["kid"] = key.ID
+		return token.SignedString(key.private)
+	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(secret)
 }
 
+func newTokenID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
 // ErrInvalidToken is returned when token verification fails or token kind mismatches.
 var ErrInvalidToken = errors.New("invalid token")
 
 func (m *Manager) parseToken(tokenStr string, secret []byte, expectedType TokenType) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenStr, &Claims{}, func(t *jwt.Token) (interface{}, error) {
-		return secret, nil
-	})
+		if !m.signsWithKeys(expectedType) {
+			return secret, nil
+		}
+		kid, _ := t.Header["kid"].(string)
+		key := m.keys.Key(kid)
+		if key == nil || t.Method.Alg() != key.Algorithm {
+			return nil, ErrInvalidToken
+		}
+		return key.public, nil
+	}, jwt.WithValidMethods(m.validMethods(expectedType)))
 	if err != nil {
 		return nil, err
 	}
@@ -105,3 +210,17 @@ func (m *Manager) parseToken(tokenStr string, secret []byte, expectedType TokenT
 	}
 	return cl, nil
 }
+
+// signsWithKeys reports whether tokens of the given type are signed with the key ring.
+func (m *Manager) signsWithKeys(ttype TokenType) bool {
+	return m.keys != nil && ttype == AccessToken
+}
+
+// validMethods lists the algorithms tokens of the given type may be signed with, so a token
+// cannot pick a weaker one, e.g. HS256 keyed with a published public key.
+func (m *Manager) validMethods(ttype TokenType) []string {
+	if !m.signsWithKeys(ttype) {
+		return []string{jwt.SigningMethodHS256.Alg()}
+	}
+	return []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}
+}
@@ -19,6 +19,29 @@ func TestTokenLifecycle(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, int32(42), uid)
 
-	_, _, err = mgr.RefreshTokens(refresh)
+	claims, err := mgr.ParseRefreshToken(refresh)
 	require.NoError(t, err)
+	require.Equal(t, int32(42), claims.UserID)
+	_, err = mgr.ParseRefreshToken(access)
+	require.Error(t, err, "an access token is no refresh token")
+}
+
+func TestMFAToken(t *testing.T) {
+	mgr := auth.NewManager("access‑key", "refresh‑key", time.Minute, 24*time.Hour)
+
+	challenge, err := mgr.NewMFAToken(7, "Pixel 8")
+	require.NoError(t, err)
+
+	claims, err := mgr.ParseMFAToken(challenge)
+	require.NoError(t, err)
+	require.Equal(t, int32(7), claims.UserID)
+	require.Equal(t, "Pixel 8", claims.DeviceName)
+
+	// A challenge must never pass for an access token, nor the other way round.
+	_, err = mgr.ValidateAccessToken(challenge)
+	require.ErrorIs(t, err, auth.ErrInvalidToken)
+	access, _, err := mgr.NewTokens(7)
+	require.NoError(t, err)
+	_, err = mgr.ParseMFAToken(access)
+	require.ErrorIs(t, err, auth.ErrInvalidToken)
 }
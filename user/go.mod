@@ -2,11 +2,15 @@ module github.com/VibeTeam/fitness-tracker-backend/user
 
 go 1.24
 
+replace github.com/VibeTeam/fitness-tracker-backend/shared => ../shared
+
 require (
+	github.com/VibeTeam/fitness-tracker-backend/shared v0.0.0-00010101000000-000000000000
 	github.com/gin-gonic/gin v1.10.1
 	github.com/golang-jwt/jwt/v5 v5.2.2
 	github.com/stretchr/testify v1.9.0
 	golang.org/x/crypto v0.40.0
+	gorm.io/driver/sqlite v1.6.0
 	gorm.io/gorm v1.30.0
 )
 
@@ -28,6 +32,7 @@ require (
 	github.com/klauspost/cpuid/v2 v2.2.7 // indirect
 	github.com/leodido/go-urn v1.4.0 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/mattn/go-sqlite3 v1.14.22 // indirect
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
 	github.com/modern-go/reflect2 v1.0.2 // indirect
 	github.com/pelletier/go-toml/v2 v2.2.2 // indirect
@@ -0,0 +1,261 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/middleware"
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/use_case"
+)
+
+// APIKeyHandler exposes management of the authenticated user's API keys.
+type APIKeyHandler struct {
+	svc *use_case.APIKeyService
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler.
+func NewAPIKeyHandler(svc *use_case.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{svc: svc}
+}
+
+// RegisterRoutes wires the API key endpoints. authMiddleware should not accept API keys
+// themselves, so a leaked key cannot mint more keys.
+func (h *APIKeyHandler) RegisterRoutes(r *gin.Engine, authMiddleware gin.HandlerFunc) {
+	keys := r.Group("/users/me/api-keys")
+	keys.Use(authMiddleware)
+	keys.POST("", h.create)
+	keys.GET("", h.list)
+	keys.GET("/:id", h.get)
+	keys.PATCH("/:id", h.update)
+	keys.DELETE("/:id", h.delete)
+}
+
+type apiKeyResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	// Prefix is the public start of the key, to recognise it in lists.
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type createdAPIKeyResponse struct {
+	apiKeyResponse
+	// Key is shown only once. Send it in the X-API-Key header or as a Bearer token.
+	Key string `json:"key"`
+}
+
+type createAPIKeyRequest struct {
+	Name   string   `json:"name" binding:"required,max=100"`
+	Scopes []string `json:"scopes" binding:"required"`
+	// ExpiresAt is optional; keys without it stay valid until deleted.
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type updateAPIKeyRequest struct {
+	Name   *string  `json:"name" binding:"omitempty,max=100"`
+	Scopes []string `json:"scopes"`
+}
+
+func toAPIKeyResponse(k *models.APIKey) apiKeyResponse {
+	return apiKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.ScopeList(),
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
+
+// Create API key
+// @Summary      Create API key
+// @Description  Creates a long-lived key for scripts and devices. Scopes: sessions:read, sessions:write, templates:read,
+// @Description  templates:write, programs:read, programs:write, catalog:read, catalog:write, suggestions:read, coach:read
+// @Description  and coach:write.
+// @Tags         api-keys
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      createAPIKeyRequest  true  "Name, scopes and optional expiry"
+// @Success      201      {object}  createdAPIKeyResponse
+// @Failure      400      {object}  gin.H
+// @Failure      401      {object}  gin.H
+// @Failure      409      {object}  gin.H
+// @Failure      500      {object}  gin.H
+// @Router       /users/me/api-keys [post]
+func (h *APIKeyHandler) create(c *gin.Context) {
+	uid, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+	var req createAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	key, raw, err := h.svc.Create(c.Request.Context(), uid, req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, createdAPIKeyResponse{apiKeyResponse: toAPIKeyResponse(key), Key: raw})
+}
+
+// List API keys
+// @Summary      List API keys
+// @Tags         api-keys
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {array}   apiKeyResponse
+// @Failure      401  {object}  gin.H
+// @Failure      500  {object}  gin.H
+// @Router       /users/me/api-keys [get]
+func (h *APIKeyHandler) list(c *gin.Context) {
+	uid, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+
+	keys, err := h.svc.List(c.Request.Context(), uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	resp := make([]apiKeyResponse, len(keys))
+	for i, k := range keys {
+		resp[i] = toAPIKeyResponse(k)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Get API key
+// @Summary      Get API key
+// @Tags         api-keys
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      int  true  "API key ID"
+// @Success      200  {object}  apiKeyResponse
+// @Failure      400  {object}  gin.H
+// @Failure      401  {object}  gin.H
+// @Failure      404  {object}  gin.H
+// @Router       /users/me/api-keys/{id} [get]
+func (h *APIKeyHandler) get(c *gin.Context) {
+	uid, id, ok := h.params(c)
+	if !ok {
+		return
+	}
+
+	key, err := h.svc.Get(c.Request.Context(), uid, id)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toAPIKeyResponse(key))
+}
+
+// Update API key
+// @Summary      Update API key
+// @Description  Renames a key or replaces its scopes; omitted fields are kept
+// @Tags         api-keys
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                  true  "API key ID"
+// @Param        payload  body      updateAPIKeyRequest  true  "Fields to change"
+// @Success      200      {object}  apiKeyResponse
+// @Failure      400      {object}  gin.H
+// @Failure      401      {object}  gin.H
+// @Failure      404      {object}  gin.H
+// @Failure      500      {object}  gin.H
+// @Router       /users/me/api-keys/{id} [patch]
+func (h *APIKeyHandler) update(c *gin.Context) {
+	uid, id, ok := h.params(c)
+	if !ok {
+		return
+	}
+	var req updateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var name string
+	if req.Name != nil {
+		if name = *req.Name; name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name must not be empty"})
+			return
+		}
+	}
+
+	key, err := h.svc.Update(c.Request.Context(), uid, id, name, req.Scopes)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toAPIKeyResponse(key))
+}
+
+// Delete API key
+// @Summary      Delete API key
+// @Description  Revokes the key; requests made with it fail immediately
+// @Tags         api-keys
+// @Security     BearerAuth
+// @Param        id   path      int  true  "API key ID"
+// @Success      204  {string}  string  "No Content"
+// @Failure      400  {object}  gin.H
+// @Failure      401  {object}  gin.H
+// @Failure      404  {object}  gin.H
+// @Failure      500  {object}  gin.H
+// @Router       /users/me/api-keys/{id} [delete]
+func (h *APIKeyHandler) delete(c *gin.Context) {
+	uid, id, ok := h.params(c)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.Delete(c.Request.Context(), uid, id); err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// params reads the authenticated user and the key ID, answering the request on failure.
+func (h *APIKeyHandler) params(c *gin.Context) (userID, id uint, ok bool) {
+	userID, ok = middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return 0, 0, false
+	}
+	n, err := strconv.Atoi(c.Param("id"))
+	if err != nil || n <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return 0, 0, false
+	}
+	return userID, uint(n), true
+}
+
+// fail maps API key errors to responses.
+func (h *APIKeyHandler) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, use_case.ErrAPIKeyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, use_case.ErrInvalidScope), errors.Is(err, use_case.ErrInvalidAPIKeyExpiry):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, use_case.ErrTooManyAPIKeys):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,157 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/middleware"
+	"github.com/VibeTeam/fitness-tracker-backend/user/auth"
+	"github.com/VibeTeam/fitness-tracker-backend/user/handler"
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/use_case"
+)
+
+/* ---------- minimal in‑memory APIKeyRepository ------------------------------ */
+
+type memAPIKeyRepo struct {
+	mu   sync.Mutex
+	keys []*models.APIKey
+}
+
+func (r *memAPIKeyRepo) find(match func(*models.APIKey) bool) (*models.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, k := range r.keys {
+		if k != nil && match(k) {
+			cp := *k
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *memAPIKeyRepo) Create(_ context.Context, k *models.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	k.ID = uint(len(r.keys) + 1)
+	cp := *k
+	r.keys = append(r.keys, &cp)
+	return nil
+}
+
+func (r *memAPIKeyRepo) GetByID(_ context.Context, id uint) (*models.APIKey, error) {
+	return r.find(func(k *models.APIKey) bool { return k.ID == id })
+}
+
+func (r *memAPIKeyRepo) GetByPrefix(_ context.Context, prefix string) (*models.APIKey, error) {
+	return r.find(func(k *models.APIKey) bool { return k.Prefix == prefix })
+}
+
+func (r *memAPIKeyRepo) ListByUser(_ context.Context, userID uint) ([]*models.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*models.APIKey
+	for i := len(r.keys) - 1; i >= 0; i-- {
+		if k := r.keys[i]; k != nil && k.UserID == userID {
+			cp := *k
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
+
+func (r *memAPIKeyRepo) Update(_ context.Context, k *models.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *k
+	r.keys[k.ID-1] = &cp
+	return nil
+}
+
+func (r *memAPIKeyRepo) Delete(_ context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys[id-1] = nil
+	return nil
+}
+
+func (r *memAPIKeyRepo) Touch(_ context.Context, id uint, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys[id-1].LastUsedAt = &at
+	return nil
+}
+
+func (r *memAPIKeyRepo) DeleteByUser(_ context.Context, _ uint) error { return nil }
+
+/* --------------------------------------------------------------------------- */
+
+func TestAPIKeyEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := newMemRepo()
+	require.NoError(t, repo.Create(context.Background(), &models.User{Email: "keys@example.com", Role: models.RoleUser}))
+	svc := use_case.NewAPIKeyService(&memAPIKeyRepo{}, repo)
+
+	router := gin.New()
+	stubAuth := func(c *gin.Context) { c.Set("user_id", uint(1)) }
+	handler.NewAPIKeyHandler(svc).RegisterRoutes(router, stubAuth)
+	// A route an integration would call with its key.
+	tm := auth.NewManager("access-key", "refresh-key", time.Minute, time.Hour)
+	router.POST("/workout-sessions", middleware.Auth(tm, middleware.WithAPIKeys(svc.Authenticate)),
+		middleware.RequireAccess(middleware.ScopeSessionsRead, middleware.ScopeSessionsWrite),
+		func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	do := func(method, path, body string, out any) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rec, req)
+		if out != nil {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), out))
+		}
+		return rec.Code
+	}
+	logWorkout := func(key string) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/workout-sessions", nil)
+		req.Header.Set("X-API-Key", key)
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/users/me/api-keys", `{"name":"gym","scopes":["everything"]}`, nil))
+
+	var created struct {
+		ID     uint     `json:"id"`
+		Key    string   `json:"key"`
+		Scopes []string `json:"scopes"`
+	}
+	require.Equal(t, http.StatusCreated, do(http.MethodPost, "/users/me/api-keys", `{"name":"gym","scopes":["sessions:read"]}`, &created))
+	require.Equal(t, []string{"sessions:read"}, created.Scopes)
+	require.Equal(t, http.StatusForbidden, logWorkout(created.Key))
+
+	require.Equal(t, http.StatusOK, do(http.MethodPatch, "/users/me/api-keys/1", `{"scopes":["sessions:read","sessions:write"]}`, nil))
+	require.Equal(t, http.StatusCreated, logWorkout(created.Key))
+
+	var list []map[string]any
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/users/me/api-keys", "", &list))
+	require.Len(t, list, 1)
+	require.Equal(t, "gym", list[0]["name"])
+	require.NotContains(t, list[0], "key", "the key is only shown on creation")
+	require.NotNil(t, list[0]["last_used_at"])
+
+	require.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/users/me/api-keys/1", "", nil))
+	require.Equal(t, http.StatusNotFound, do(http.MethodGet, "/users/me/api-keys/1", "", nil))
+	require.Equal(t, http.StatusUnauthorized, logWorkout(created.Key))
+}
@@ -1,14 +1,18 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/VibeTeam/fitness-tracker-backend/shared/middleware"
 	"github.com/VibeTeam/fitness-tracker-backend/user/use_case"
 )
 
-// AuthHandler exposes authentication-related HTTP endpoints (login, refresh, logout).
+// AuthHandler exposes authentication-related HTTP endpoints (login, refresh, logout, sessions).
 type AuthHandler struct {
 	svc *use_case.AuthService
 }
@@ -31,6 +35,8 @@ func (h *AuthHandler) RegisterRoutes(r *gin.Engine, authMiddleware gin.HandlerFu
 	authGroupWithAuth := r.Group("/auth")
 	authGroupWithAuth.Use(authMiddleware)
 	authGroupWithAuth.POST("/logout", h.logout)
+	authGroupWithAuth.GET("/sessions", h.listSessions)
+	authGroupWithAuth.DELETE("/sessions/:id", h.revokeSession)
 }
 
 // --- request/response DTOs ---
@@ -38,6 +44,8 @@ func (h *AuthHandler) RegisterRoutes(r *gin.Engine, authMiddleware gin.HandlerFu
 type loginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
+	// DeviceName labels the new session in the session list, e.g. "Pixel 8".
+	DeviceName string `json:"device_name"`
 }
 
 type tokenResponse struct {
@@ -45,13 +53,38 @@ type tokenResponse struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+// mfaChallengeResponse replaces tokenResponse for users with two-factor authentication.
+type mfaChallengeResponse struct {
+	MFARequired bool `json:"mfa_required"`
+	// MFAToken is exchanged for a token pair at /auth/mfa/verify within five minutes.
+	MFAToken string `json:"mfa_token"`
+}
+
 type refreshRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
+type sessionResponse struct {
+	ID         uint      `json:"id"`
+	DeviceName string    `json:"device_name"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	// Current marks the session of the access token used for the request.
+	Current bool `json:"current"`
+}
+
+type logoutRequest struct {
+	// RefreshToken selects the login to end; when omitted, every login of the user is ended.
+	RefreshToken string `json:"refresh_token"`
+}
+
 // Login
 // @Summary      User login
-// @Description  Authenticates user credentials and returns JWT pair
+// @Description  Authenticates user credentials and returns JWT pair. Users with two-factor authentication get an mfaChallengeResponse instead,
+// @Description  to be completed at /auth/mfa/verify. Answers 403 for unverified e-mail addresses when verification is required,
+// @Description  and 429 with a Retry-After header while the account or client address is locked out after repeated failures.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -59,6 +92,8 @@ type refreshRequest struct {
 // @Success      200      {object}  tokenResponse
 // @Failure      400      {object}  gin.H
 // @Failure      401      {object}  gin.H
+// @Failure      403      {object}  gin.H
+// @Failure      429      {object}  gin.H
 // @Router       /auth/login [post]
 func (h *AuthHandler) login(c *gin.Context) {
 	var req loginRequest
@@ -67,18 +102,43 @@ func (h *AuthHandler) login(c *gin.Context) {
 		return
 	}
 
-	access, refresh, err := h.svc.Login(c.Request.Context(), req.Email, req.Password)
+	device := use_case.Device{Name: req.DeviceName, UserAgent: c.Request.UserAgent(), IP: c.ClientIP()}
+	res, err := h.svc.Login(c.Request.Context(), req.Email, req.Password, device)
 	if err != nil {
+		if lockedOut(c, err) {
+			return
+		}
+		if errors.Is(err, use_case.ErrEmailNotVerified) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
+	if res.MFARequired() {
+		c.JSON(http.StatusOK, mfaChallengeResponse{MFARequired: true, MFAToken: res.MFAToken})
+		return
+	}
 
-	c.JSON(http.StatusOK, tokenResponse{AccessToken: access, RefreshToken: refresh})
+	c.JSON(http.StatusOK, tokenResponse{AccessToken: res.AccessToken, RefreshToken: res.RefreshToken})
+}
+
+// lockedOut answers 429 Too Many Requests with a Retry-After header if err is a lockout.
+func lockedOut(c *gin.Context, err error) bool {
+	var tooMany *use_case.TooManyAttemptsError
+	if !errors.As(err, &tooMany) {
+		return false
+	}
+	seconds := int((tooMany.RetryAfter + time.Second - 1) / time.Second)
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error(), "retry_after": seconds})
+	return true
 }
 
 // Refresh tokens
 // @Summary      Refresh JWT tokens
-// @Description  Exchanges a refresh token for a new JWT pair
+// @Description  Exchanges a refresh token for a new JWT pair. The presented token is rotated and must not be used again:
+// @Description  reusing it revokes every token issued from the same login.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -104,11 +164,107 @@ func (h *AuthHandler) refresh(c *gin.Context) {
 }
 
 // Logout
-// @Summary      Logout (client-side token discard)
+// @Summary      Logout
+// @Description  Revokes the refresh tokens of the given login, or of all logins when no refresh token is sent
 // @Tags         auth
 // @Security     BearerAuth
-// @Success      204  {string}  string  "No Content"
+// @Accept       json
+// @Param        payload  body      logoutRequest  false  "Refresh token"
+// @Success      204      {string}  string  "No Content"
+// @Failure      400      {object}  gin.H
+// @Failure      401      {object}  gin.H
+// @Failure      500      {object}  gin.H
 // @Router       /auth/logout [post]
 func (h *AuthHandler) logout(c *gin.Context) {
+	uid, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+	var req logoutRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if err := h.svc.Logout(c.Request.Context(), uid, req.RefreshToken); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// List sessions
+// @Summary      List login sessions
+// @Description  Lists the devices the user is signed in on
+// @Tags         auth
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {array}   sessionResponse
+// @Failure      401  {object}  gin.H
+// @Failure      500  {object}  gin.H
+// @Router       /auth/sessions [get]
+func (h *AuthHandler) listSessions(c *gin.Context) {
+	uid, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+	current, _ := middleware.SessionID(c)
+
+	sessions, err := h.svc.Sessions(c.Request.Context(), uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	resp := make([]sessionResponse, len(sessions))
+	for i, s := range sessions {
+		resp[i] = sessionResponse{
+			ID:         s.ID,
+			DeviceName: s.DeviceName,
+			UserAgent:  s.UserAgent,
+			IP:         s.IP,
+			CreatedAt:  s.CreatedAt,
+			LastUsedAt: s.LastUsedAt,
+			Current:    s.ID == current,
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Revoke session
+// @Summary      Sign out a device
+// @Description  Revokes a login session; its refresh and access tokens stop working immediately
+// @Tags         auth
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Session ID"
+// @Success      204  {string}  string  "No Content"
+// @Failure      400  {object}  gin.H
+// @Failure      401  {object}  gin.H
+// @Failure      404  {object}  gin.H
+// @Failure      500  {object}  gin.H
+// @Router       /auth/sessions/{id} [delete]
+func (h *AuthHandler) revokeSession(c *gin.Context) {
+	uid, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.svc.RevokeSession(c.Request.Context(), uid, uint(id)); err != nil {
+		if errors.Is(err, use_case.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 	c.Status(http.StatusNoContent)
 }
@@ -14,9 +14,12 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
 
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
+	"github.com/VibeTeam/fitness-tracker-backend/user/audit"
 	"github.com/VibeTeam/fitness-tracker-backend/user/auth"
 	"github.com/VibeTeam/fitness-tracker-backend/user/handler"
 	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/repository/memrepository"
 	"github.com/VibeTeam/fitness-tracker-backend/user/use_case"
 )
 
@@ -70,14 +73,196 @@ func (r *memRepo) GetByID(_ context.Context, id uint) (*models.User, error) {
 	return &cu, nil
 }
 
-func (r *memRepo) List(_ context.Context, _, _ int) ([]*models.User, error) { return nil, nil }
-func (r *memRepo) Update(_ context.Context, _ *models.User) error           { return nil }
-func (r *memRepo) Delete(_ context.Context, _ uint) error                   { return nil }
+func (r *memRepo) List(_ context.Context, _ int, _ *pagination.Cursor) ([]*models.User, error) {
+	return nil, nil
+}
+func (r *memRepo) Update(_ context.Context, u *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cu := *u
+	r.byEmail[u.Email] = &cu
+	r.byID[u.ID] = &cu
+	return nil
+}
+func (r *memRepo) Delete(_ context.Context, _ uint) error { return nil }
 func (r *memRepo) Count(_ context.Context) (int, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	return len(r.byID), nil
 }
+func (r *memRepo) AdvanceTOTPStep(_ context.Context, id uint, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.byID[id]
+	if !ok || u.TOTPLastStep >= step {
+		return false, nil
+	}
+	u.TOTPLastStep = step
+	return true, nil
+}
+
+/* ---------- minimal in‑memory RefreshTokenRepository ------------------------ */
+
+type memRefreshRepo struct {
+	mu     sync.Mutex
+	tokens []*models.RefreshToken
+}
+
+func (r *memRefreshRepo) Create(_ context.Context, t *models.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t.ID = uint(len(r.tokens) + 1)
+	cp := *t
+	r.tokens = append(r.tokens, &cp)
+	return nil
+}
+
+func (r *memRefreshRepo) GetByHash(_ context.Context, hash string) (*models.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.TokenHash == hash {
+			cp := *t
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *memRefreshRepo) MarkUsed(_ context.Context, id uint, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.tokens[id-1].UsedAt != nil {
+		return false, nil
+	}
+	r.tokens[id-1].UsedAt = &at
+	return true, nil
+}
+
+func (r *memRefreshRepo) RevokeFamily(_ context.Context, familyID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.FamilyID == familyID && t.RevokedAt == nil {
+			t.RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+func (r *memRefreshRepo) RevokeByUser(_ context.Context, userID uint, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.RevokedAt == nil {
+			t.RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+// DeleteByUser blanks the rows of a user instead of removing them, as IDs index the slice.
+func (r *memRefreshRepo) DeleteByUser(_ context.Context, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, t := range r.tokens {
+		if t.UserID == userID {
+			r.tokens[i] = &models.RefreshToken{ID: t.ID}
+		}
+	}
+	return nil
+}
+
+/* ---------- minimal in‑memory LoginSessionRepository ------------------------ */
+
+type memSessionRepo struct {
+	mu       sync.Mutex
+	sessions []*models.LoginSession
+}
+
+func (r *memSessionRepo) Create(_ context.Context, s *models.LoginSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s.ID = uint(len(r.sessions) + 1)
+	s.CreatedAt = time.Now()
+	cp := *s
+	r.sessions = append(r.sessions, &cp)
+	return nil
+}
+
+func (r *memSessionRepo) GetByID(_ context.Context, id uint) (*models.LoginSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id == 0 || int(id) > len(r.sessions) {
+		return nil, errors.New("not found")
+	}
+	cp := *r.sessions[id-1]
+	return &cp, nil
+}
+
+func (r *memSessionRepo) GetByFamily(_ context.Context, familyID string) (*models.LoginSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.sessions {
+		if s.FamilyID == familyID {
+			cp := *s
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *memSessionRepo) ListActiveByUser(_ context.Context, userID uint, since time.Time) ([]*models.LoginSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*models.LoginSession
+	for _, s := range r.sessions {
+		if s.UserID == userID && s.RevokedAt == nil && s.LastUsedAt.After(since) {
+			cp := *s
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
+
+func (r *memSessionRepo) Touch(_ context.Context, id uint, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions[id-1].LastUsedAt = at
+	return nil
+}
+
+func (r *memSessionRepo) Revoke(_ context.Context, id uint, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.sessions[id-1].RevokedAt == nil {
+		r.sessions[id-1].RevokedAt = &at
+	}
+	return nil
+}
+
+func (r *memSessionRepo) RevokeByUser(_ context.Context, userID uint, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.sessions {
+		if s.UserID == userID && s.RevokedAt == nil {
+			s.RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+// DeleteByUser blanks the rows of a user instead of removing them, as IDs index the slice.
+func (r *memSessionRepo) DeleteByUser(_ context.Context, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, s := range r.sessions {
+		if s.UserID == userID {
+			r.sessions[i] = &models.LoginSession{ID: s.ID}
+		}
+	}
+	return nil
+}
 
 /* --------------------------------------------------------------------------- */
 
@@ -87,7 +272,7 @@ func TestLoginAndRefresh_Success(t *testing.T) {
 	// backing store + service
 	repo := newMemRepo()
 	tm := auth.NewManager("access-key", "refresh-key", time.Minute, time.Hour)
-	svc := use_case.NewAuthService(repo, tm)
+	svc := use_case.NewAuthService(repo, &memRefreshRepo{}, &memSessionRepo{}, tm)
 
 	// register a real user so /auth/login can succeed
 	_, _, err := svc.Register(context.Background(), "me@example.com", "pwd")
@@ -96,7 +281,7 @@ func TestLoginAndRefresh_Success(t *testing.T) {
 	// HTTP layer
 	h := handler.NewAuthHandler(svc)
 	router := gin.New()
-	h.RegisterRoutes(router, func(c *gin.Context) {}) // no‑op auth
+	h.RegisterRoutes(router, func(c *gin.Context) { c.Set("user_id", uint(1)) }) // stub auth
 
 	/* -------- login -------- */
 	loginBody := []byte(`{"email":"me@example.com","password":"pwd"}`)
@@ -120,4 +305,133 @@ func TestLoginAndRefresh_Success(t *testing.T) {
 	router.ServeHTTP(rec, req)
 
 	require.Equal(t, http.StatusOK, rec.Code)
+
+	var rotated map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rotated))
+	require.NotEqual(t, tokens["refresh_token"], rotated["refresh_token"])
+
+	/* -------- replaying the rotated token fails -------- */
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/auth/refresh", bytes.NewReader(refBody))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestLogout_RevokesRefreshToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := newMemRepo()
+	tm := auth.NewManager("access-key", "refresh-key", time.Minute, time.Hour)
+	svc := use_case.NewAuthService(repo, &memRefreshRepo{}, &memSessionRepo{}, tm)
+	_, refresh, err := svc.Register(context.Background(), "out@example.com", "pwd")
+	require.NoError(t, err)
+
+	h := handler.NewAuthHandler(svc)
+	router := gin.New()
+	h.RegisterRoutes(router, func(c *gin.Context) { c.Set("user_id", uint(1)) }) // stub auth
+
+	body := []byte(`{"refresh_token":"` + refresh + `"}`)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/auth/logout", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/auth/refresh", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// A body-less logout is still accepted.
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/auth/logout", nil))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestSessions_ListAndRevoke(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := newMemRepo()
+	tm := auth.NewManager("access-key", "refresh-key", time.Minute, time.Hour)
+	svc := use_case.NewAuthService(repo, &memRefreshRepo{}, &memSessionRepo{}, tm)
+	_, _, err := svc.Register(context.Background(), "dev@example.com", "pwd")
+	require.NoError(t, err)
+
+	h := handler.NewAuthHandler(svc)
+	router := gin.New()
+	caller := uint(1)
+	h.RegisterRoutes(router, func(c *gin.Context) {
+		c.Set("user_id", caller)
+		c.Set("session_id", uint(1))
+	}) // stub auth: caller on the session created by Register
+
+	body := []byte(`{"email":"dev@example.com","password":"pwd","device_name":"Pixel"}`)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "test-agent")
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/auth/sessions", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var sessions []struct {
+		ID         uint   `json:"id"`
+		DeviceName string `json:"device_name"`
+		UserAgent  string `json:"user_agent"`
+		Current    bool   `json:"current"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &sessions))
+	require.Len(t, sessions, 2)
+	require.True(t, sessions[0].Current)
+	require.Equal(t, "Pixel", sessions[1].DeviceName)
+	require.Equal(t, "test-agent", sessions[1].UserAgent)
+	require.False(t, sessions[1].Current)
+
+	// Another user cannot see or revoke the session.
+	caller = 2
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/auth/sessions/2", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	caller = 1
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/auth/sessions/2", nil))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.False(t, svc.SessionActive(context.Background(), 1, 2))
+}
+
+func TestLogin_LockedOut(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := newMemRepo()
+	tm := auth.NewManager("access-key", "refresh-key", time.Minute, time.Hour)
+	svc := use_case.NewAuthService(repo, &memRefreshRepo{}, &memSessionRepo{}, tm)
+	policy := use_case.ThrottlePolicy{FreeAttempts: 1, BaseLockout: 90 * time.Second, MaxLockout: time.Hour, Window: time.Hour}
+	var auditLog bytes.Buffer
+	svc.SetLoginThrottle(use_case.NewLoginThrottle(memrepository.NewLoginThrottleRepository(), policy, policy, audit.NewJSON(&auditLog)))
+	_, _, err := svc.Register(context.Background(), "locked@example.com", "pwd")
+	require.NoError(t, err)
+
+	router := gin.New()
+	handler.NewAuthHandler(svc).RegisterRoutes(router, func(c *gin.Context) { c.Set("user_id", uint(1)) }) // stub auth
+	login := func(password string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/auth/login", bytes.NewBufferString(`{"email":"locked@example.com","password":"`+password+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	require.Equal(t, http.StatusUnauthorized, login("wrong").Code)
+	require.Equal(t, http.StatusUnauthorized, login("wrong").Code)
+
+	rec := login("pwd")
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.Equal(t, "90", rec.Header().Get("Retry-After"))
+	require.Contains(t, auditLog.String(), `"type":"login_locked"`)
 }
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/auth"
+)
+
+// JWKSHandler publishes the public keys access tokens are signed with.
+type JWKSHandler struct {
+	tokens *auth.Manager
+}
+
+// NewJWKSHandler creates a new JWKSHandler.
+func NewJWKSHandler(tokens *auth.Manager) *JWKSHandler {
+	return &JWKSHandler{tokens: tokens}
+}
+
+// RegisterRoutes wires the public JWKS endpoint.
+func (h *JWKSHandler) RegisterRoutes(r *gin.Engine) {
+	r.GET("/.well-known/jwks.json", h.jwks)
+}
+
+// JSON Web Key Set
+// @Summary      Token verification keys
+// @Description  Public keys other services verify access tokens with; pick the key by the token's kid header. Empty while tokens are signed with a shared secret.
+// @Tags         auth
+// @Produce      json
+// @Success      200  {object}  auth.JWKS
+// @Router       /.well-known/jwks.json [get]
+func (h *JWKSHandler) jwks(c *gin.Context) {
+	// Verifiers may cache the set briefly; a key is published well before it starts signing.
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.tokens.JWKS())
+}
@@ -0,0 +1,38 @@
+package handler_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/auth"
+	"github.com/VibeTeam/fitness-tracker-backend/user/handler"
+)
+
+func TestJWKSEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	ring, err := auth.NewKeyRing(key)
+	require.NoError(t, err)
+	router := gin.New()
+	handler.NewJWKSHandler(auth.NewKeyRingManager(ring, "access-secret", "refresh-secret", time.Minute, time.Hour)).RegisterRoutes(router)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var set auth.JWKS
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &set))
+	require.Len(t, set.Keys, 1)
+	require.Equal(t, auth.JWK{Kty: "OKP", Kid: ring.SigningKey().ID, Use: "sig", Alg: "EdDSA", Crv: "Ed25519", X: set.Keys[0].X}, set.Keys[0])
+	require.NotEmpty(t, set.Keys[0].X)
+}
@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/middleware"
+	"github.com/VibeTeam/fitness-tracker-backend/user/use_case"
+)
+
+// MFAHandler exposes TOTP two-factor authentication endpoints.
+type MFAHandler struct {
+	svc *use_case.MFAService
+}
+
+// NewMFAHandler creates a new MFAHandler.
+func NewMFAHandler(svc *use_case.MFAService) *MFAHandler {
+	return &MFAHandler{svc: svc}
+}
+
+// RegisterRoutes wires the MFA endpoints. Verifying a login challenge is public, since the
+// caller has no access token yet; managing the second factor requires authentication.
+func (h *MFAHandler) RegisterRoutes(r *gin.Engine, authMiddleware gin.HandlerFunc) {
+	mfa := r.Group("/auth/mfa")
+	mfa.POST("/verify", h.verify)
+
+	mfaWithAuth := r.Group("/auth/mfa")
+	mfaWithAuth.Use(authMiddleware)
+	mfaWithAuth.GET("", h.status)
+	mfaWithAuth.POST("/enroll", h.enroll)
+	mfaWithAuth.POST("/confirm", h.confirm)
+	mfaWithAuth.DELETE("", h.disable)
+}
+
+type mfaStatusResponse struct {
+	Enabled           bool `json:"enabled"`
+	RecoveryCodesLeft int  `json:"recovery_codes_left"`
+}
+
+type mfaEnrollResponse struct {
+	Secret string `json:"secret"`
+	// OTPAuthURI is imported by authenticator apps, usually rendered as a QR code.
+	OTPAuthURI string `json:"otpauth_uri"`
+	// RecoveryCodes are shown only once; each replaces a TOTP code a single time.
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type mfaEnrollRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type mfaConfirmRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type mfaDisableRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type mfaVerifyRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	// Code is a TOTP code or one of the recovery codes.
+	Code string `json:"code" binding:"required"`
+}
+
+// MFA status
+// @Summary      Two-factor authentication status
+// @Tags         auth
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  mfaStatusResponse
+// @Failure      401  {object}  gin.H
+// @Failure      500  {object}  gin.H
+// @Router       /auth/mfa [get]
+func (h *MFAHandler) status(c *gin.Context) {
+	uid, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+
+	enabled, err := h.svc.Enabled(c.Request.Context(), uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	resp := mfaStatusResponse{Enabled: enabled}
+	if enabled {
+		if resp.RecoveryCodesLeft, err = h.svc.RecoveryCodesLeft(c.Request.Context(), uid); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Enroll MFA
+// @Summary      Start TOTP enrollment
+// @Description  Checks the current password and returns a new TOTP secret, its otpauth URI and single-use recovery codes. Two-factor authentication is enabled by /auth/mfa/confirm.
+// @Tags         auth
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      mfaEnrollRequest  true  "Current password"
+// @Success      200      {object}  mfaEnrollResponse
+// @Failure      400      {object}  gin.H
+// @Failure      401      {object}  gin.H
+// @Failure      403      {object}  gin.H
+// @Failure      409      {object}  gin.H
+// @Failure      500      {object}  gin.H
+// @Router       /auth/mfa/enroll [post]
+func (h *MFAHandler) enroll(c *gin.Context) {
+	uid, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+	var req mfaEnrollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	e, err := h.svc.Enroll(c.Request.Context(), uid, req.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, use_case.ErrWrongPassword), errors.Is(err, use_case.ErrPasswordNotSet):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, use_case.ErrMFAAlreadyEnabled):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, mfaEnrollResponse{Secret: e.Secret, OTPAuthURI: e.URI, RecoveryCodes: e.RecoveryCodes})
+}
+
+// Confirm MFA
+// @Summary      Confirm TOTP enrollment
+// @Description  Enables two-factor authentication with a first code from the authenticator app
+// @Tags         auth
+// @Security     BearerAuth
+// @Accept       json
+// @Param        payload  body      mfaConfirmRequest  true  "TOTP code"
+// @Success      204      {string}  string  "No Content"
+// @Failure      400      {object}  gin.H
+// @Failure      401      {object}  gin.H
+// @Failure      409      {object}  gin.H
+// @Failure      500      {object}  gin.H
+// @Router       /auth/mfa/confirm [post]
+func (h *MFAHandler) confirm(c *gin.Context) {
+	uid, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+	var req mfaConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.svc.Confirm(c.Request.Context(), uid, req.Code)
+	switch {
+	case err == nil:
+		c.Status(http.StatusNoContent)
+	case errors.Is(err, use_case.ErrInvalidMFACode), errors.Is(err, use_case.ErrMFANotEnrolled):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, use_case.ErrMFAAlreadyEnabled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// Disable MFA
+// @Summary      Disable two-factor authentication
+// @Tags         auth
+// @Security     BearerAuth
+// @Accept       json
+// @Param        payload  body      mfaDisableRequest  true  "Current password"
+// @Success      204      {string}  string  "No Content"
+// @Failure      400      {object}  gin.H
+// @Failure      401      {object}  gin.H
+// @Failure      403      {object}  gin.H
+// @Failure      500      {object}  gin.H
+// @Router       /auth/mfa [delete]
+func (h *MFAHandler) disable(c *gin.Context) {
+	uid, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+	var req mfaDisableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.svc.Disable(c.Request.Context(), uid, req.Password); err != nil {
+		if errors.Is(err, use_case.ErrWrongPassword) || errors.Is(err, use_case.ErrPasswordNotSet) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Verify MFA
+// @Summary      Complete a two-factor login
+// @Description  Exchanges the mfa_token returned by /auth/login and a TOTP or recovery code for a JWT pair
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      mfaVerifyRequest  true  "Challenge and code"
+// @Success      200      {object}  tokenResponse
+// @Failure      400      {object}  gin.H
+// @Failure      401      {object}  gin.H
+// @Failure      429      {object}  gin.H
+// @Failure      500      {object}  gin.H
+// @Router       /auth/mfa/verify [post]
+func (h *MFAHandler) verify(c *gin.Context) {
+	var req mfaVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	device := use_case.Device{UserAgent: c.Request.UserAgent(), IP: c.ClientIP()}
+	access, refresh, err := h.svc.Verify(c.Request.Context(), req.MFAToken, req.Code, device)
+	if err != nil {
+		if lockedOut(c, err) {
+			return
+		}
+		if errors.Is(err, use_case.ErrInvalidMFAToken) || errors.Is(err, use_case.ErrInvalidMFACode) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tokenResponse{AccessToken: access, RefreshToken: refresh})
+}
@@ -0,0 +1,131 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/auth"
+	"github.com/VibeTeam/fitness-tracker-backend/user/handler"
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/totp"
+	"github.com/VibeTeam/fitness-tracker-backend/user/use_case"
+)
+
+/* ---------- minimal in‑memory RecoveryCodeRepository ------------------------ */
+
+type memRecoveryRepo struct {
+	mu    sync.Mutex
+	codes []*models.RecoveryCode
+}
+
+func (r *memRecoveryRepo) Replace(_ context.Context, userID uint, hashes []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.codes = nil
+	for _, h := range hashes {
+		r.codes = append(r.codes, &models.RecoveryCode{UserID: userID, CodeHash: h})
+	}
+	return nil
+}
+
+func (r *memRecoveryRepo) Use(_ context.Context, userID uint, hash string, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.codes {
+		if c.UserID == userID && c.CodeHash == hash && c.UsedAt == nil {
+			c.UsedAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memRecoveryRepo) CountUnused(_ context.Context, _ uint) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, c := range r.codes {
+		if c.UsedAt == nil {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *memRecoveryRepo) DeleteByUser(ctx context.Context, userID uint) error {
+	return r.Replace(ctx, userID, nil)
+}
+
+/* --------------------------------------------------------------------------- */
+
+func TestMFAEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := newMemRepo()
+	tm := auth.NewManager("access-key", "refresh-key", time.Minute, time.Hour)
+	authSvc := use_case.NewAuthService(repo, &memRefreshRepo{}, &memSessionRepo{}, tm)
+	_, _, err := authSvc.Register(context.Background(), "2fa@example.com", "pwd")
+	require.NoError(t, err)
+
+	router := gin.New()
+	stubAuth := func(c *gin.Context) { c.Set("user_id", uint(1)) }
+	handler.NewAuthHandler(authSvc).RegisterRoutes(router, stubAuth)
+	handler.NewMFAHandler(use_case.NewMFAService(repo, &memRecoveryRepo{}, authSvc, "Fitness Tracker")).RegisterRoutes(router, stubAuth)
+
+	do := func(method, path, body string, out any) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rec, req)
+		if out != nil {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), out))
+		}
+		return rec.Code
+	}
+
+	var enrollment struct {
+		Secret        string   `json:"secret"`
+		OTPAuthURI    string   `json:"otpauth_uri"`
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/auth/mfa/enroll", "", nil))
+	require.Equal(t, http.StatusForbidden, do(http.MethodPost, "/auth/mfa/enroll", `{"password":"wrong"}`, nil))
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/auth/mfa/enroll", `{"password":"pwd"}`, &enrollment))
+	require.NotEmpty(t, enrollment.OTPAuthURI)
+	require.Len(t, enrollment.RecoveryCodes, use_case.RecoveryCodeCount)
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/auth/mfa/confirm", `{"code":"000000"}`, nil))
+	code, err := totp.Code(enrollment.Secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, do(http.MethodPost, "/auth/mfa/confirm", `{"code":"`+code+`"}`, nil))
+	require.Equal(t, http.StatusConflict, do(http.MethodPost, "/auth/mfa/enroll", `{"password":"pwd"}`, nil))
+
+	var status map[string]any
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/auth/mfa", "", &status))
+	require.Equal(t, true, status["enabled"])
+
+	// Login now stops at the challenge.
+	var challenge map[string]any
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/auth/login", `{"email":"2fa@example.com","password":"pwd"}`, &challenge))
+	require.Equal(t, true, challenge["mfa_required"])
+	require.NotContains(t, challenge, "access_token")
+	mfaToken := challenge["mfa_token"].(string)
+
+	require.Equal(t, http.StatusUnauthorized, do(http.MethodPost, "/auth/mfa/verify", `{"mfa_token":"`+mfaToken+`","code":"nope"}`, nil))
+	var tokens map[string]string
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/auth/mfa/verify", `{"mfa_token":"`+mfaToken+`","code":"`+enrollment.RecoveryCodes[0]+`"}`, &tokens))
+	require.NotEmpty(t, tokens["access_token"])
+	require.NotEmpty(t, tokens["refresh_token"])
+
+	require.Equal(t, http.StatusForbidden, do(http.MethodDelete, "/auth/mfa", `{"password":"wrong"}`, nil))
+	require.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/auth/mfa", `{"password":"pwd"}`, nil))
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/middleware"
+	"github.com/VibeTeam/fitness-tracker-backend/user/use_case"
+)
+
+// PasswordHandler exposes the password reset endpoints.
+type PasswordHandler struct {
+	svc *use_case.PasswordResetService
+}
+
+// NewPasswordHandler creates a new PasswordHandler.
+func NewPasswordHandler(svc *use_case.PasswordResetService) *PasswordHandler {
+	return &PasswordHandler{svc: svc}
+}
+
+// RegisterRoutes wires the password reset endpoints. Requesting and using a reset link is
+// public; mailing a link to the signed-in user requires authentication.
+func (h *PasswordHandler) RegisterRoutes(r *gin.Engine, authMiddleware gin.HandlerFunc) {
+	password := r.Group("/auth/password")
+	password.POST("/forgot", h.forgot)
+	password.POST("/reset", h.reset)
+	password.POST("/link", authMiddleware, h.link)
+}
+
+type forgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type resetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// Forgot password
+// @Summary      Request a password reset
+// @Description  Mails a single-use reset link valid for one hour. The response is the same whether or not the address is registered.
+// @Description  An account is mailed at most one link a minute; further requests are ignored.
+// @Tags         auth
+// @Accept       json
+// @Param        payload  body      forgotPasswordRequest  true  "Account e-mail"
+// @Success      202      {string}  string  "Accepted"
+// @Failure      400      {object}  gin.H
+// @Router       /auth/password/forgot [post]
+func (h *PasswordHandler) forgot(c *gin.Context) {
+	var req forgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.svc.Forgot(c.Request.Context(), req.Email)
+	c.Status(http.StatusAccepted)
+}
+
+// Reset password
+// @Summary      Reset password
+// @Description  Sets a new password using the token from the reset e-mail and signs out every session
+// @Tags         auth
+// @Accept       json
+// @Param        payload  body      resetPasswordRequest  true  "Reset token and new password"
+// @Success      204      {string}  string  "No Content"
+// @Failure      400      {object}  gin.H
+// @Failure      500      {object}  gin.H
+// @Router       /auth/password/reset [post]
+func (h *PasswordHandler) reset(c *gin.Context) {
+	var req resetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.svc.Reset(c.Request.Context(), req.Token, req.Password); err != nil {
+		if errors.Is(err, use_case.ErrInvalidResetToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Mail a password link
+// @Summary      Mail a password link to the current user
+// @Description  Mails the signed-in user a single-use link to set a password. Accounts created through social login have none
+// @Description  and need one before changing the e-mail address or password, managing two-factor authentication or deleting the account.
+// @Tags         auth
+// @Security     BearerAuth
+// @Success      202  {string}  string  "Accepted"
+// @Failure      401  {object}  gin.H
+// @Failure      500  {object}  gin.H
+// @Router       /auth/password/link [post]
+func (h *PasswordHandler) link(c *gin.Context) {
+	uid, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+
+	if err := h.svc.SendLink(c.Request.Context(), uid); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not send password e-mail"})
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
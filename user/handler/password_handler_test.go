@@ -0,0 +1,170 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/auth"
+	"github.com/VibeTeam/fitness-tracker-backend/user/handler"
+	"github.com/VibeTeam/fitness-tracker-backend/user/mailer"
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/use_case"
+)
+
+/* ---------- minimal in‑memory PasswordResetRepository ----------------------- */
+
+type memResetRepo struct {
+	mu     sync.Mutex
+	tokens []*models.PasswordResetToken
+}
+
+func (r *memResetRepo) Create(_ context.Context, t *models.PasswordResetToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t.ID = uint(len(r.tokens) + 1)
+	cp := *t
+	r.tokens = append(r.tokens, &cp)
+	return nil
+}
+
+func (r *memResetRepo) GetByHash(_ context.Context, hash string) (*models.PasswordResetToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.TokenHash == hash {
+			cp := *t
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *memResetRepo) LatestByUser(_ context.Context, userID uint) (*models.PasswordResetToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.tokens) - 1; i >= 0; i-- {
+		if r.tokens[i].UserID == userID {
+			cp := *r.tokens[i]
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *memResetRepo) MarkUsed(_ context.Context, id uint, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.tokens[id-1].UsedAt != nil {
+		return false, nil
+	}
+	r.tokens[id-1].UsedAt = &at
+	return true, nil
+}
+
+func (r *memResetRepo) InvalidateByUser(_ context.Context, userID uint, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.UsedAt == nil {
+			t.UsedAt = &at
+		}
+	}
+	return nil
+}
+
+// DeleteByUser blanks the rows of a user instead of removing them, as IDs index the slice.
+func (r *memResetRepo) DeleteByUser(_ context.Context, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, t := range r.tokens {
+		if t.UserID == userID {
+			r.tokens[i] = &models.PasswordResetToken{ID: t.ID}
+		}
+	}
+	return nil
+}
+
+/* --------------------------------------------------------------------------- */
+
+func TestPasswordResetEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := newMemRepo()
+	tm := auth.NewManager("access-key", "refresh-key", time.Minute, time.Hour)
+	authSvc := use_case.NewAuthService(repo, &memRefreshRepo{}, &memSessionRepo{}, tm)
+	_, _, err := authSvc.Register(context.Background(), "reset@example.com", "old")
+	require.NoError(t, err)
+
+	var mail bytes.Buffer
+	svc := use_case.NewPasswordResetService(repo, &memResetRepo{}, authSvc, mailer.NewLog(&mail, "noreply@example.com"), "http://localhost/reset")
+	router := gin.New()
+	handler.NewPasswordHandler(svc).RegisterRoutes(router, func(c *gin.Context) { c.Set("user_id", uint(1)) })
+
+	post := func(path, body string) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusBadRequest, post("/auth/password/forgot", `{"email":"not-an-email"}`))
+	require.Equal(t, http.StatusAccepted, post("/auth/password/forgot", `{"email":"unknown@example.com"}`))
+	require.Equal(t, http.StatusAccepted, post("/auth/password/forgot", `{"email":"reset@example.com"}`))
+	svc.Wait()
+
+	m := regexp.MustCompile(`token=(\S+)`).FindStringSubmatch(mail.String())
+	require.Len(t, m, 2)
+
+	require.Equal(t, http.StatusBadRequest, post("/auth/password/reset", `{"token":"bogus","password":"new"}`))
+	require.Equal(t, http.StatusNoContent, post("/auth/password/reset", `{"token":"`+m[1]+`","password":"new"}`))
+
+	_, err = authSvc.Login(context.Background(), "reset@example.com", "new", use_case.Device{})
+	require.NoError(t, err)
+
+	// Signed-in users can have a link mailed without typing their address.
+	mail.Reset()
+	require.Equal(t, http.StatusAccepted, post("/auth/password/link", ""))
+	require.Contains(t, mail.String(), "reset@example.com")
+}
+
+// brokenMailer fails every delivery, like an unreachable SMTP relay.
+type brokenMailer struct{}
+
+func (brokenMailer) Send(context.Context, mailer.Message) error {
+	return errors.New("connection refused")
+}
+
+func TestForgotPasswordAlwaysAccepted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := newMemRepo()
+	tm := auth.NewManager("access-key", "refresh-key", time.Minute, time.Hour)
+	authSvc := use_case.NewAuthService(repo, &memRefreshRepo{}, &memSessionRepo{}, tm)
+	_, _, err := authSvc.Register(context.Background(), "known@example.com", "pwd")
+	require.NoError(t, err)
+
+	svc := use_case.NewPasswordResetService(repo, &memResetRepo{}, authSvc, brokenMailer{}, "http://localhost/reset")
+	router := gin.New()
+	handler.NewPasswordHandler(svc).RegisterRoutes(router, func(c *gin.Context) { c.Set("user_id", uint(1)) })
+
+	// A mail failure must not tell registered addresses apart from unknown ones.
+	for _, email := range []string{"known@example.com", "unknown@example.com"} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/auth/password/forgot", bytes.NewBufferString(`{"email":"`+email+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusAccepted, rec.Code, email)
+	}
+	svc.Wait()
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/use_case"
+)
+
+// SocialLoginHandler exposes sign-in through OpenID Connect identity providers.
+type SocialLoginHandler struct {
+	svc *use_case.SocialLoginService
+}
+
+// NewSocialLoginHandler creates a new SocialLoginHandler.
+func NewSocialLoginHandler(svc *use_case.SocialLoginService) *SocialLoginHandler {
+	return &SocialLoginHandler{svc: svc}
+}
+
+// RegisterRoutes wires the social login endpoints; all of them are public.
+func (h *SocialLoginHandler) RegisterRoutes(r *gin.Engine) {
+	oidc := r.Group("/auth/oidc")
+	oidc.GET("/providers", h.providers)
+	oidc.GET("/:provider/authorize", h.authorize)
+	oidc.POST("/:provider/callback", h.callback)
+}
+
+type providersResponse struct {
+	Providers []string `json:"providers"`
+}
+
+type authorizeResponse struct {
+	// AuthorizationURL is opened in a browser; the provider redirects back with code and state.
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+type socialCallbackRequest struct {
+	Code  string `json:"code" form:"code" binding:"required"`
+	State string `json:"state" form:"state" binding:"required"`
+	// DeviceName labels the new session in the session list, e.g. "Pixel 8".
+	DeviceName string `json:"device_name" form:"device_name"`
+}
+
+// List identity providers
+// @Summary      List identity providers
+// @Tags         auth
+// @Produce      json
+// @Success      200  {object}  providersResponse
+// @Router       /auth/oidc/providers [get]
+func (h *SocialLoginHandler) providers(c *gin.Context) {
+	c.JSON(http.StatusOK, providersResponse{Providers: h.svc.Providers()})
+}
+
+// Start social login
+// @Summary      Start sign-in with an identity provider
+// @Description  Returns the provider's authorization URL for the authorization code flow with PKCE
+// @Tags         auth
+// @Produce      json
+// @Param        provider  path      string  true  "Provider name, e.g. google or apple"
+// @Success      200       {object}  authorizeResponse
+// @Failure      404       {object}  gin.H
+// @Failure      502       {object}  gin.H
+// @Router       /auth/oidc/{provider}/authorize [get]
+func (h *SocialLoginHandler) authorize(c *gin.Context) {
+	authURL, err := h.svc.Start(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		if errors.Is(err, use_case.ErrUnknownProvider) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, authorizeResponse{AuthorizationURL: authURL})
+}
+
+// Finish social login
+// @Summary      Finish sign-in with an identity provider
+// @Description  Exchanges the code and state from the provider's redirect for a JWT pair, or an mfaChallengeResponse for users
+// @Description  with two-factor authentication. The first sign-in links the provider account to the user with the same e-mail
+// @Description  address, when both the provider and the account have verified it, or creates a new user without a password.
+// @Tags         auth
+// @Accept       json
+// @Accept       x-www-form-urlencoded
+// @Produce      json
+// @Param        provider  path      string                 true  "Provider name"
+// @Param        payload   body      socialCallbackRequest  true  "Code and state"
+// @Success      200       {object}  tokenResponse
+// @Failure      400       {object}  gin.H
+// @Failure      401       {object}  gin.H
+// @Failure      403       {object}  gin.H
+// @Failure      404       {object}  gin.H
+// @Failure      409       {object}  gin.H
+// @Failure      500       {object}  gin.H
+// @Router       /auth/oidc/{provider}/callback [post]
+func (h *SocialLoginHandler) callback(c *gin.Context) {
+	var req socialCallbackRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	device := use_case.Device{Name: req.DeviceName, UserAgent: c.Request.UserAgent(), IP: c.ClientIP()}
+	res, err := h.svc.Finish(c.Request.Context(), c.Param("provider"), req.Code, req.State, device)
+	if err != nil {
+		switch {
+		case errors.Is(err, use_case.ErrUnknownProvider):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, use_case.ErrInvalidLoginState), errors.Is(err, use_case.ErrIdentityEmailMissing):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, use_case.ErrSocialLoginFailed):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, use_case.ErrEmailNotVerified):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, use_case.ErrIdentityEmailUnverified), errors.Is(err, use_case.ErrAccountEmailUnverified):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	if res.MFARequired() {
+		c.JSON(http.StatusOK, mfaChallengeResponse{MFARequired: true, MFAToken: res.MFAToken})
+		return
+	}
+	c.JSON(http.StatusOK, tokenResponse{AccessToken: res.AccessToken, RefreshToken: res.RefreshToken})
+}
@@ -0,0 +1,149 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/auth"
+	"github.com/VibeTeam/fitness-tracker-backend/user/handler"
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/oidc"
+	"github.com/VibeTeam/fitness-tracker-backend/user/use_case"
+)
+
+/* ---------- minimal in‑memory identity and login state repositories --------- */
+
+type memIdentityRepo struct {
+	mu         sync.Mutex
+	identities []*models.UserIdentity
+}
+
+func (r *memIdentityRepo) Create(_ context.Context, i *models.UserIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *i
+	r.identities = append(r.identities, &cp)
+	return nil
+}
+
+func (r *memIdentityRepo) GetByProviderSubject(_ context.Context, provider, subject string) (*models.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, i := range r.identities {
+		if i.Provider == provider && i.Subject == subject {
+			cp := *i
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *memIdentityRepo) DeleteByUser(_ context.Context, _ uint) error { return nil }
+
+type memLoginStateRepo struct {
+	mu     sync.Mutex
+	states []*models.OIDCLoginState
+}
+
+func (r *memLoginStateRepo) Create(_ context.Context, s *models.OIDCLoginState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s.ID = uint(len(r.states) + 1)
+	cp := *s
+	r.states = append(r.states, &cp)
+	return nil
+}
+
+func (r *memLoginStateRepo) GetByHash(_ context.Context, hash string) (*models.OIDCLoginState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.states {
+		if s.StateHash == hash {
+			cp := *s
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *memLoginStateRepo) MarkUsed(_ context.Context, id uint, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.states[id-1].UsedAt != nil {
+		return false, nil
+	}
+	r.states[id-1].UsedAt = &at
+	return true, nil
+}
+
+/* --------------------------------------------------------------------------- */
+
+// stubProvider accepts the code "ok" for any verifier and returns a fixed verified identity.
+type stubProvider struct{}
+
+func (stubProvider) Name() string { return "google" }
+
+func (stubProvider) AuthCodeURL(_ context.Context, state, _, _ string) (string, error) {
+	return "https://accounts.example/authorize?state=" + url.QueryEscape(state), nil
+}
+
+func (stubProvider) Exchange(_ context.Context, code, _, _ string) (*oidc.Identity, error) {
+	if code != "ok" {
+		return nil, oidc.ErrInvalidIDToken
+	}
+	return &oidc.Identity{Subject: "g-42", Email: "social@example.com", EmailVerified: true}, nil
+}
+
+func TestSocialLoginEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := newMemRepo()
+	tm := auth.NewManager("access-key", "refresh-key", time.Minute, time.Hour)
+	authSvc := use_case.NewAuthService(repo, &memRefreshRepo{}, &memSessionRepo{}, tm)
+	svc := use_case.NewSocialLoginService(repo, &memIdentityRepo{}, &memLoginStateRepo{}, authSvc, stubProvider{})
+	router := gin.New()
+	handler.NewSocialLoginHandler(svc).RegisterRoutes(router)
+
+	do := func(method, path, body string, out any) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rec, req)
+		if out != nil {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), out))
+		}
+		return rec.Code
+	}
+
+	var providers map[string][]string
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/auth/oidc/providers", "", &providers))
+	require.Equal(t, []string{"google"}, providers["providers"])
+	require.Equal(t, http.StatusNotFound, do(http.MethodGet, "/auth/oidc/friendster/authorize", "", nil))
+
+	start := func() string {
+		var resp map[string]string
+		require.Equal(t, http.StatusOK, do(http.MethodGet, "/auth/oidc/google/authorize", "", &resp))
+		u, err := url.Parse(resp["authorization_url"])
+		require.NoError(t, err)
+		return u.Query().Get("state")
+	}
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/auth/oidc/google/callback", `{"code":"ok","state":"forged"}`, nil))
+	require.Equal(t, http.StatusUnauthorized, do(http.MethodPost, "/auth/oidc/google/callback", `{"code":"bad","state":"`+start()+`"}`, nil))
+
+	var tokens map[string]string
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/auth/oidc/google/callback", `{"code":"ok","state":"`+start()+`"}`, &tokens))
+	require.NotEmpty(t, tokens["access_token"])
+	require.NotEmpty(t, tokens["refresh_token"])
+}
@@ -1,46 +1,67 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/crypto/bcrypt"
 
 	"github.com/VibeTeam/fitness-tracker-backend/shared/middleware"
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
 	"github.com/VibeTeam/fitness-tracker-backend/user/models"
 	"github.com/VibeTeam/fitness-tracker-backend/user/repository"
+	"github.com/VibeTeam/fitness-tracker-backend/user/use_case"
 )
 
 // UserHandler bundles dependencies for user-related HTTP endpoints.
 type UserHandler struct {
-	repo repository.UserRepository
+	repo     repository.UserRepository
+	accounts *use_case.AccountService
 }
 
 // New creates a new UserHandler instance.
-func New(repo repository.UserRepository) *UserHandler {
-	return &UserHandler{repo: repo}
+func New(repo repository.UserRepository, accounts *use_case.AccountService) *UserHandler {
+	return &UserHandler{repo: repo, accounts: accounts}
 }
 
-// RegisterRoutes attaches user CRUD endpoints to the supplied Gin router.
+// RegisterRoutes attaches user endpoints to the supplied Gin router. Sign-up is public,
+// /users/me serves the authenticated user and managing other accounts requires the admin role.
 func (h *UserHandler) RegisterRoutes(r *gin.Engine, authMiddleware gin.HandlerFunc) {
 	users := r.Group("/users")
 	usersWithAuth := r.Group("/users")
+	usersAdmin := r.Group("/users")
 
 	{
-		users.GET("", h.list)
-		users.GET("/:id", h.getByID)
 		users.POST("", h.create)
-		users.PUT("/:id", h.update)
-		users.DELETE("/:id", h.delete)
 	}
 
 	usersWithAuth.Use(authMiddleware)
 	{
 		usersWithAuth.GET("/me", h.getMe)
+		usersWithAuth.PATCH("/me", h.updateMe)
+		usersWithAuth.DELETE("/me", h.deleteMe)
+	}
+
+	usersAdmin.Use(authMiddleware, middleware.RequireCurrentRole(h.storedRole, models.RoleAdmin))
+	{
+		usersAdmin.GET("", h.list)
+		usersAdmin.GET("/:id", h.getByID)
+		usersAdmin.PUT("/:id", h.update)
+		usersAdmin.DELETE("/:id", h.delete)
 	}
 }
 
+// storedRole looks up the role of a user, so admin routes see demotions before tokens expire.
+func (h *UserHandler) storedRole(ctx context.Context, userID uint) (string, error) {
+	user, err := h.repo.GetByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return user.Role, nil
+}
+
 type createUserRequest struct {
 	Name     string `json:"name" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
@@ -51,17 +72,32 @@ type updateUserRequest struct {
 	Name     *string `json:"name"`
 	Email    *string `json:"email"`
 	Password *string `json:"password"`
+	Role     *string `json:"role"`
+}
+
+type updateMeRequest struct {
+	Name  *string `json:"name"`
+	Email *string `json:"email"`
+	// Password is the new password.
+	Password *string `json:"password"`
+	// CurrentPassword is required to change the e-mail address or the password.
+	CurrentPassword string `json:"current_password"`
+}
+
+type deleteMeRequest struct {
+	Password string `json:"password" binding:"required"`
 }
 
 // Create user
 // @Summary      Register new user
-// @Description  Creates a user and returns the stored record
+// @Description  Creates a user and returns the stored record. When e-mail verification is enabled a confirmation link is mailed.
 // @Tags         users
 // @Accept       json
 // @Produce      json
 // @Param        payload  body      createUserRequest  true  "User info"
 // @Success      201      {object}  models.User
 // @Failure      400      {object}  gin.H
+// @Failure      409      {object}  gin.H
 // @Failure      500      {object}  gin.H
 // @Router       /users [post]
 func (h *UserHandler) create(c *gin.Context) {
@@ -71,19 +107,12 @@ func (h *UserHandler) create(c *gin.Context) {
 		return
 	}
 
-	// hash password
-	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	user, err := h.accounts.SignUp(c.Request.Context(), req.Name, req.Email, req.Password)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	user := &models.User{
-		Name:         req.Name,
-		Email:        req.Email,
-		PasswordHash: string(hash),
-	}
-	if err := h.repo.Create(c.Request.Context(), user); err != nil {
+		if errors.Is(err, use_case.ErrEmailAlreadyUsed) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -92,40 +121,51 @@ func (h *UserHandler) create(c *gin.Context) {
 }
 
 // List users
-// @Summary      List users
+// @Summary      List users (admin)
 // @Tags         users
 // @Produce      json
-// @Param        limit   query     int  false  "Limit"
-// @Param        offset  query     int  false  "Offset"
-// @Success      200     {array}   models.User
+// @Param        limit   query     int     false  "Page size (default 100, max 500)"
+// @Param        cursor  query     string  false  "next_cursor of the previous page"
+// @Success      200     {object}  pagination.Page[models.User]
+// @Failure      400     {object}  gin.H
+// @Failure      401     {object}  gin.H
+// @Failure      403     {object}  gin.H
 // @Failure      500     {object}  gin.H
 // @Router       /users [get]
 // @Security     BearerAuth
 func (h *UserHandler) list(c *gin.Context) {
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	p, err := pagination.Parse(c.Query("limit"), c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	users, err := h.repo.List(c.Request.Context(), limit, offset)
+	users, err := h.repo.List(c.Request.Context(), p.Limit+1, p.After)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	page := pagination.NewPage(users, p, func(u *models.User) pagination.Cursor {
+		return pagination.Cursor{Time: u.CreatedAt, ID: u.ID}
+	})
 	// map to response slice
-	resp := make([]gin.H, len(users))
-	for i, u := range users {
-		resp[i] = userResponse(u)
+	resp := pagination.Page[gin.H]{Items: make([]gin.H, len(page.Items)), NextCursor: page.NextCursor}
+	for i, u := range page.Items {
+		resp.Items[i] = userResponse(u)
 	}
 	c.JSON(http.StatusOK, resp)
 }
 
 // Get user by ID
-// @Summary      Get user by ID
+// @Summary      Get user by ID (admin)
 // @Tags         users
 // @Produce      json
 // @Param        id   path      int  true  "User ID"
 // @Success      200  {object}  models.User
 // @Failure      400  {object}  gin.H
+// @Failure      401  {object}  gin.H
+// @Failure      403  {object}  gin.H
 // @Failure      404  {object}  gin.H
 // @Router       /users/{id} [get]
 // @Security     BearerAuth
@@ -144,7 +184,9 @@ func (h *UserHandler) getByID(c *gin.Context) {
 }
 
 // Update user
-// @Summary      Update user
+// @Summary      Update user (admin)
+// @Description  Updates any account, including its role (user, coach or admin). A new e-mail address has to be verified
+// @Description  again and a new password signs the user out everywhere.
 // @Tags         users
 // @Accept       json
 // @Produce      json
@@ -152,7 +194,10 @@ func (h *UserHandler) getByID(c *gin.Context) {
 // @Param        payload  body      updateUserRequest   true  "Update information"
 // @Success      200      {object}  models.User
 // @Failure      400      {object}  gin.H
+// @Failure      401      {object}  gin.H
+// @Failure      403      {object}  gin.H
 // @Failure      404      {object}  gin.H
+// @Failure      409      {object}  gin.H
 // @Failure      500      {object}  gin.H
 // @Router       /users/{id} [put]
 // @Security     BearerAuth
@@ -168,28 +213,78 @@ func (h *UserHandler) update(c *gin.Context) {
 		return
 	}
 
+	if req.Role != nil && !models.ValidRole(*req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "role must be user, coach or admin"})
+		return
+	}
+
 	user, err := h.repo.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
-
-	if req.Name != nil {
-		user.Name = *req.Name
+	err = h.accounts.AdminUpdate(c.Request.Context(), user, use_case.AdminUpdate{
+		Name:     req.Name,
+		Email:    req.Email,
+		Password: req.Password,
+		Role:     req.Role,
+	})
+	switch {
+	case errors.Is(err, use_case.ErrEmailAlreadyUsed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
-	if req.Email != nil {
-		user.Email = *req.Email
+
+	c.JSON(http.StatusOK, userResponse(user))
+}
+
+// Update current user
+// @Summary      Update current user
+// @Description  Updates the authenticated user's own profile. Changing the e-mail address or the password requires
+// @Description  current_password; a new e-mail address has to be verified again and a new password signs out other devices.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      updateMeRequest  true  "Fields to change"
+// @Success      200      {object}  models.User
+// @Failure      400      {object}  gin.H
+// @Failure      401      {object}  gin.H
+// @Failure      403      {object}  gin.H
+// @Failure      409      {object}  gin.H
+// @Failure      500      {object}  gin.H
+// @Router       /users/me [patch]
+// @Security     BearerAuth
+func (h *UserHandler) updateMe(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
 	}
-	if req.Password != nil {
-		hash, err := bcrypt.GenerateFromPassword([]byte(*req.Password), bcrypt.DefaultCost)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		user.PasswordHash = string(hash)
+	var req updateMeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
+	sessionID, _ := middleware.SessionID(c)
 
-	if err := h.repo.Update(c.Request.Context(), user); err != nil {
+	user, err := h.accounts.UpdateProfile(c.Request.Context(), userID, use_case.ProfileUpdate{
+		Name:            req.Name,
+		Email:           req.Email,
+		NewPassword:     req.Password,
+		CurrentPassword: req.CurrentPassword,
+		SessionID:       sessionID,
+	})
+	switch {
+	case errors.Is(err, use_case.ErrWrongPassword), errors.Is(err, use_case.ErrPasswordNotSet):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, use_case.ErrEmailAlreadyUsed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -197,12 +292,55 @@ func (h *UserHandler) update(c *gin.Context) {
 	c.JSON(http.StatusOK, userResponse(user))
 }
 
+// Delete current user
+// @Summary      Delete current user
+// @Description  Deletes the authenticated user's account together with all workout history after confirming the password.
+// @Description  Programs the user authored are deleted too, which ends other users' enrollments in them.
+// @Tags         users
+// @Accept       json
+// @Param        payload  body      deleteMeRequest  true  "Password confirmation"
+// @Success      204      {string}  string  "No Content"
+// @Failure      400      {object}  gin.H
+// @Failure      401      {object}  gin.H
+// @Failure      403      {object}  gin.H
+// @Failure      500      {object}  gin.H
+// @Router       /users/me [delete]
+// @Security     BearerAuth
+func (h *UserHandler) deleteMe(c *gin.Context) {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	var req deleteMeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.accounts.DeleteSelf(c.Request.Context(), userID, req.Password); err != nil {
+		if errors.Is(err, use_case.ErrWrongPassword) || errors.Is(err, use_case.ErrPasswordNotSet) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // Delete user
-// @Summary      Delete user
+// @Summary      Delete user (admin)
+// @Description  Deletes the account together with all of its workout history. Programs the user authored are deleted
+// @Description  too, which ends other users' enrollments in them.
 // @Tags         users
 // @Param        id   path      int  true  "User ID"
 // @Success      204  {string}  string  "No Content"
 // @Failure      400  {object}  gin.H
+// @Failure      401  {object}  gin.H
+// @Failure      403  {object}  gin.H
+// @Failure      404  {object}  gin.H
 // @Failure      500  {object}  gin.H
 // @Router       /users/{id} [delete]
 // @Security     BearerAuth
@@ -213,7 +351,12 @@ func (h *UserHandler) delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.Delete(c.Request.Context(), uint(id)); err != nil {
+	user, err := h.repo.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.accounts.Delete(c.Request.Context(), user); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -250,9 +393,12 @@ func (h *UserHandler) getMe(c *gin.Context) {
 // helper to shape user JSON response without password hash
 func userResponse(u *models.User) gin.H {
 	return gin.H{
-		"id":         u.ID,
-		"name":       u.Name,
-		"email":      u.Email,
-		"created_at": u.CreatedAt,
+		"id":             u.ID,
+		"name":           u.Name,
+		"email":          u.Email,
+		"role":           u.Role,
+		"email_verified": u.EmailVerifiedAt != nil,
+		"mfa_enabled":    u.MFAEnabled(),
+		"created_at":     u.CreatedAt,
 	}
 }
@@ -9,12 +9,17 @@ import (
 	"net/http/httptest"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
+	"github.com/VibeTeam/fitness-tracker-backend/user/auth"
 	"github.com/VibeTeam/fitness-tracker-backend/user/handler"
 	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/use_case"
 )
 
 /* ----------- in‑memory UserRepository implementation ------------------------ */
@@ -26,8 +31,16 @@ type userMemRepo struct {
 }
 
 // GetByEmail implements repository.UserRepository.
-func (r *userMemRepo) GetByEmail(ctx context.Context, email string) (*models.User, error) {
-	panic("unimplemented")
+func (r *userMemRepo) GetByEmail(_ context.Context, email string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.store {
+		if u.Email == email {
+			cp := *u
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("not found")
 }
 
 func newUserRepo() *userMemRepo {
@@ -54,7 +67,7 @@ func (r *userMemRepo) GetByID(_ context.Context, id uint) (*models.User, error)
 	return nil, errors.New("not found")
 }
 
-func (r *userMemRepo) List(_ context.Context, _, _ int) ([]*models.User, error) {
+func (r *userMemRepo) List(_ context.Context, _ int, _ *pagination.Cursor) ([]*models.User, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	out := make([]*models.User, 0, len(r.store))
@@ -86,14 +99,45 @@ func (r *userMemRepo) Count(_ context.Context) (int, error) {
 	return len(r.store), nil
 }
 
+func (r *userMemRepo) AdvanceTOTPStep(_ context.Context, id uint, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.store[id]
+	if !ok || u.TOTPLastStep >= step {
+		return false, nil
+	}
+	u.TOTPLastStep = step
+	return true, nil
+}
+
 /* --------------------------------------------------------------------------- */
 
 func setupRouter() (*gin.Engine, *userMemRepo) {
+	return setupRouterAs(models.RoleAdmin)
+}
+
+// deletedWorkoutData records the users whose workout data the account service asked to delete.
+type deletedWorkoutData struct{ users []uint }
+
+func (d *deletedWorkoutData) DeleteByUser(_ context.Context, userID uint) error {
+	d.users = append(d.users, userID)
+	return nil
+}
+
+var cleaner = &deletedWorkoutData{}
+
+// setupRouterAs wires the handler behind a stub auth that signs every request in as user 1 with the given role.
+func setupRouterAs(role string) (*gin.Engine, *userMemRepo) {
 	repo := newUserRepo()
-	h := handler.New(repo)
+	tm := auth.NewManager("access-key", "refresh-key", time.Minute, time.Hour)
+	authSvc := use_case.NewAuthService(repo, &memRefreshRepo{}, &memSessionRepo{}, tm)
+	h := handler.New(repo, use_case.NewAccountService(repo, authSvc, nil, cleaner))
 
 	r := gin.New()
-	h.RegisterRoutes(r, func(c *gin.Context) {})
+	h.RegisterRoutes(r, func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Set("role", role)
+	})
 	return r, repo
 }
 
@@ -115,10 +159,129 @@ func TestCreateUser_Success(t *testing.T) {
 }
 
 func TestGetUserByID_NotFound(t *testing.T) {
-	r, _ := setupRouter()
+	r, repo := setupRouter()
+	require.NoError(t, repo.Create(context.Background(), &models.User{Name: "Admin", Email: "a@e.com", Role: models.RoleAdmin}))
 
 	req := httptest.NewRequest(http.MethodGet, "/users/99", nil)
 	rec := httptest.NewRecorder()
 	r.ServeHTTP(rec, req)
 	require.Equal(t, http.StatusNotFound, rec.Code)
 }
+
+func TestDeleteUser_NotFound(t *testing.T) {
+	r, repo := setupRouter()
+	require.NoError(t, repo.Create(context.Background(), &models.User{Name: "Admin", Email: "a@e.com", Role: models.RoleAdmin}))
+	cleaner.users = nil
+
+	req := httptest.NewRequest(http.MethodDelete, "/users/99", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.Empty(t, cleaner.users)
+}
+
+func TestAdminRoutes_CheckStoredRole(t *testing.T) {
+	// the token still says admin, but the user was demoted since
+	r, repo := setupRouter()
+	require.NoError(t, repo.Create(context.Background(), &models.User{Name: "Former admin", Email: "f@e.com", Role: models.RoleUser}))
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestAdminRoutes_ForbiddenForUsers(t *testing.T) {
+	r, repo := setupRouterAs(models.RoleUser)
+	require.NoError(t, repo.Create(context.Background(), &models.User{Name: "Me", Email: "me@e.com", Role: models.RoleUser}))
+	require.NoError(t, repo.Create(context.Background(), &models.User{Name: "Other", Email: "o@e.com", Role: models.RoleUser}))
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/users", nil),
+		httptest.NewRequest(http.MethodGet, "/users/2", nil),
+		httptest.NewRequest(http.MethodPut, "/users/2", bytes.NewBufferString(`{"name":"x"}`)),
+		httptest.NewRequest(http.MethodDelete, "/users/2", nil),
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusForbidden, rec.Code, req.Method+" "+req.URL.Path)
+	}
+	_, err := repo.GetByID(context.Background(), 2)
+	require.NoError(t, err)
+}
+
+// patchMe sends PATCH /users/me with the given JSON body.
+func patchMe(r *gin.Engine, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPatch, "/users/me", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestUpdateMe(t *testing.T) {
+	r, repo := setupRouterAs(models.RoleUser)
+	hash, _ := bcrypt.GenerateFromPassword([]byte("old"), bcrypt.MinCost)
+	now := time.Now()
+	require.NoError(t, repo.Create(context.Background(), &models.User{Name: "Me", Email: "me@e.com", PasswordHash: string(hash), Role: models.RoleUser, EmailVerifiedAt: &now}))
+	require.NoError(t, repo.Create(context.Background(), &models.User{Name: "Other", Email: "taken@e.com", Role: models.RoleUser}))
+
+	require.Equal(t, http.StatusOK, patchMe(r, `{"name":"Renamed","role":"admin"}`).Code)
+	u, _ := repo.GetByID(context.Background(), 1)
+	require.Equal(t, "Renamed", u.Name)
+	require.Equal(t, models.RoleUser, u.Role, "role is not self-service")
+
+	// Sensitive changes need the current password.
+	require.Equal(t, http.StatusForbidden, patchMe(r, `{"password":"new"}`).Code)
+	require.Equal(t, http.StatusForbidden, patchMe(r, `{"email":"new@e.com","current_password":"wrong"}`).Code)
+	require.Equal(t, http.StatusConflict, patchMe(r, `{"email":"taken@e.com","current_password":"old"}`).Code)
+
+	require.Equal(t, http.StatusOK, patchMe(r, `{"email":"new@e.com","password":"new","current_password":"old"}`).Code)
+	u, _ = repo.GetByID(context.Background(), 1)
+	require.Equal(t, "new@e.com", u.Email)
+	require.Nil(t, u.EmailVerifiedAt, "a changed e-mail must be verified again")
+	require.NoError(t, bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte("new")))
+}
+
+func TestDeleteMe(t *testing.T) {
+	r, repo := setupRouterAs(models.RoleUser)
+	hash, _ := bcrypt.GenerateFromPassword([]byte("pwd"), bcrypt.MinCost)
+	require.NoError(t, repo.Create(context.Background(), &models.User{Name: "Me", Email: "me@e.com", PasswordHash: string(hash), Role: models.RoleUser}))
+	cleaner.users = nil
+
+	req := httptest.NewRequest(http.MethodDelete, "/users/me", bytes.NewBufferString(`{"password":"nope"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+	require.Empty(t, cleaner.users)
+
+	req = httptest.NewRequest(http.MethodDelete, "/users/me", bytes.NewBufferString(`{"password":"pwd"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.Equal(t, []uint{1}, cleaner.users)
+	_, err := repo.GetByID(context.Background(), 1)
+	require.Error(t, err)
+}
+
+func TestAdminSetsRole(t *testing.T) {
+	r, repo := setupRouter()
+	require.NoError(t, repo.Create(context.Background(), &models.User{Name: "Admin", Email: "a@e.com", Role: models.RoleAdmin}))
+	require.NoError(t, repo.Create(context.Background(), &models.User{Name: "Coach", Email: "c@e.com", Role: models.RoleUser}))
+
+	req := httptest.NewRequest(http.MethodPut, "/users/2", bytes.NewBufferString(`{"role":"coach"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	u, _ := repo.GetByID(context.Background(), 2)
+	require.Equal(t, models.RoleCoach, u.Role)
+
+	req = httptest.NewRequest(http.MethodPut, "/users/2", bytes.NewBufferString(`{"role":"root"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
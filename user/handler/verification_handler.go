@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/use_case"
+)
+
+// VerificationHandler exposes the public e-mail verification endpoints.
+type VerificationHandler struct {
+	svc *use_case.EmailVerificationService
+}
+
+// NewVerificationHandler creates a new VerificationHandler.
+func NewVerificationHandler(svc *use_case.EmailVerificationService) *VerificationHandler {
+	return &VerificationHandler{svc: svc}
+}
+
+// RegisterRoutes wires the verification endpoints; both are public so the mailed link works
+// without a session.
+func (h *VerificationHandler) RegisterRoutes(r *gin.Engine) {
+	verify := r.Group("/auth/verify")
+	verify.GET("", h.verify)
+	verify.POST("/resend", h.resend)
+}
+
+type resendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// Verify e-mail
+// @Summary      Verify e-mail address
+// @Description  Confirms the address the token was mailed to. Access tokens issued before carry the old state; refresh them to unlock verified-only features.
+// @Tags         auth
+// @Produce      json
+// @Param        token  query     string  true  "Token from the verification e-mail"
+// @Success      200    {object}  gin.H
+// @Failure      400    {object}  gin.H
+// @Failure      500    {object}  gin.H
+// @Router       /auth/verify [get]
+func (h *VerificationHandler) verify(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "token is required"})
+		return
+	}
+
+	user, err := h.svc.Verify(c.Request.Context(), token)
+	if err != nil {
+		if errors.Is(err, use_case.ErrInvalidVerificationToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"email": user.Email, "email_verified": true})
+}
+
+// Resend verification
+// @Summary      Resend verification e-mail
+// @Description  Mails a new verification link and invalidates earlier ones. The response is the same for unknown or already verified addresses.
+// @Description  An account is mailed at most one link a minute; further requests are ignored.
+// @Tags         auth
+// @Accept       json
+// @Param        payload  body      resendVerificationRequest  true  "Account e-mail"
+// @Success      202      {string}  string  "Accepted"
+// @Failure      400      {object}  gin.H
+// @Router       /auth/verify/resend [post]
+func (h *VerificationHandler) resend(c *gin.Context) {
+	var req resendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.svc.Resend(c.Request.Context(), req.Email)
+	c.Status(http.StatusAccepted)
+}
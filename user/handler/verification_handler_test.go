@@ -0,0 +1,166 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/auth"
+	"github.com/VibeTeam/fitness-tracker-backend/user/handler"
+	"github.com/VibeTeam/fitness-tracker-backend/user/mailer"
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/use_case"
+)
+
+/* ---------- minimal in‑memory EmailVerificationRepository ------------------- */
+
+type memVerificationRepo struct {
+	mu     sync.Mutex
+	tokens []*models.EmailVerificationToken
+}
+
+func (r *memVerificationRepo) Create(_ context.Context, t *models.EmailVerificationToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t.ID = uint(len(r.tokens) + 1)
+	cp := *t
+	r.tokens = append(r.tokens, &cp)
+	return nil
+}
+
+func (r *memVerificationRepo) GetByHash(_ context.Context, hash string) (*models.EmailVerificationToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.TokenHash == hash {
+			cp := *t
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *memVerificationRepo) LatestByUser(_ context.Context, userID uint) (*models.EmailVerificationToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.tokens) - 1; i >= 0; i-- {
+		if r.tokens[i].UserID == userID {
+			cp := *r.tokens[i]
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *memVerificationRepo) MarkUsed(_ context.Context, id uint, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.tokens[id-1].UsedAt != nil {
+		return false, nil
+	}
+	r.tokens[id-1].UsedAt = &at
+	return true, nil
+}
+
+func (r *memVerificationRepo) InvalidateByUser(_ context.Context, userID uint, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.UsedAt == nil {
+			t.UsedAt = &at
+		}
+	}
+	return nil
+}
+
+// DeleteByUser blanks the rows of a user instead of removing them, as IDs index the slice.
+func (r *memVerificationRepo) DeleteByUser(_ context.Context, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, t := range r.tokens {
+		if t.UserID == userID {
+			r.tokens[i] = &models.EmailVerificationToken{ID: t.ID}
+		}
+	}
+	return nil
+}
+
+/* --------------------------------------------------------------------------- */
+
+func TestVerificationEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := newUserRepo()
+	tm := auth.NewManager("access-key", "refresh-key", time.Minute, time.Hour)
+	authSvc := use_case.NewAuthService(repo, &memRefreshRepo{}, &memSessionRepo{}, tm)
+	var mail bytes.Buffer
+	tokens := &memVerificationRepo{}
+	verifier := use_case.NewEmailVerificationService(repo, tokens, mailer.NewLog(&mail, "noreply@example.com"), "http://localhost/auth/verify")
+	authSvc.SetEmailVerification(verifier, use_case.VerificationRequired)
+
+	router := gin.New()
+	handler.New(repo, use_case.NewAccountService(repo, authSvc, verifier)).RegisterRoutes(router, func(c *gin.Context) {})
+	handler.NewAuthHandler(authSvc).RegisterRoutes(router, func(c *gin.Context) {})
+	handler.NewVerificationHandler(verifier).RegisterRoutes(router)
+
+	do := func(method, path, body string) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	const login = `{"email":"verify@example.com","password":"pwd"}`
+
+	require.Equal(t, http.StatusCreated, do(http.MethodPost, "/users", `{"name":"V","email":"verify@example.com","password":"pwd"}`))
+	require.Equal(t, http.StatusConflict, do(http.MethodPost, "/users", `{"name":"V","email":"verify@example.com","password":"pwd"}`))
+	require.Equal(t, http.StatusForbidden, do(http.MethodPost, "/auth/login", login))
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/auth/verify/resend", `{"email":"nope"}`))
+	require.Equal(t, http.StatusAccepted, do(http.MethodPost, "/auth/verify/resend", `{"email":"unknown@example.com"}`))
+	verifier.Wait()
+	tokens.tokens[0].CreatedAt = time.Now().Add(-use_case.VerificationLinkCooldown)
+	require.Equal(t, http.StatusAccepted, do(http.MethodPost, "/auth/verify/resend", `{"email":"verify@example.com"}`))
+	verifier.Wait()
+
+	m := regexp.MustCompile(`token=(\S+)`).FindAllStringSubmatch(mail.String(), -1)
+	require.Len(t, m, 2, "one link on sign-up, one on resend")
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/auth/verify", ""))
+	require.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/auth/verify?token="+m[0][1], ""), "resend invalidates the first link")
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/auth/verify?token="+m[1][1], ""))
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/auth/login", login))
+}
+
+func TestResendVerificationAlwaysAccepted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := newUserRepo()
+	tm := auth.NewManager("access-key", "refresh-key", time.Minute, time.Hour)
+	authSvc := use_case.NewAuthService(repo, &memRefreshRepo{}, &memSessionRepo{}, tm)
+	_, _, err := authSvc.Register(context.Background(), "unverified@example.com", "pwd")
+	require.NoError(t, err)
+
+	verifier := use_case.NewEmailVerificationService(repo, &memVerificationRepo{}, brokenMailer{}, "http://localhost/auth/verify")
+	router := gin.New()
+	handler.NewVerificationHandler(verifier).RegisterRoutes(router)
+
+	// A mail failure must not tell unverified accounts apart from unknown addresses.
+	for _, email := range []string{"unverified@example.com", "unknown@example.com"} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/auth/verify/resend", bytes.NewBufferString(`{"email":"`+email+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusAccepted, rec.Code, email)
+	}
+	verifier.Wait()
+}
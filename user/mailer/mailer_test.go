@@ -0,0 +1,23 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogMailer(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewLog(&buf, "noreply@example.com")
+
+	err := m.Send(context.Background(), Message{To: "a@b.c", Subject: "Hi", Body: "line one\nline two"})
+	require.NoError(t, err)
+
+	out := buf.String()
+	require.Contains(t, out, "From: noreply@example.com\r\n")
+	require.Contains(t, out, "To: a@b.c\r\n")
+	require.Contains(t, out, "Subject: Hi\r\n")
+	require.Contains(t, out, "\r\n\r\nline one\r\nline two")
+}
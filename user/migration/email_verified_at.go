@@ -0,0 +1,35 @@
+// Package migration holds one-off data migrations for the user domain that
+// go beyond what GORM's AutoMigrate can express.
+package migration
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+)
+
+// EmailVerifiedAt adds the users.email_verified_at column to an existing users table and
+// marks every account that predates e-mail verification as verified at its creation time,
+// so enabling verification does not lock existing users out. It only acts while the column
+// is missing and must run before AutoMigrate adds it; afterwards it is a no-op, so it is safe
+// to run on every start. It returns the number of accounts marked verified.
+func EmailVerifiedAt(ctx context.Context, db *gorm.DB) (int, error) {
+	db = db.WithContext(ctx)
+	m := db.Migrator()
+	if !m.HasTable(&models.User{}) || m.HasColumn(&models.User{}, "EmailVerifiedAt") {
+		return 0, nil
+	}
+
+	var backfilled int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Migrator().AddColumn(&models.User{}, "EmailVerifiedAt"); err != nil {
+			return err
+		}
+		res := tx.Exec("UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL")
+		backfilled = res.RowsAffected
+		return res.Error
+	})
+	return int(backfilled), err
+}
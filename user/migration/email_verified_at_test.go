@@ -0,0 +1,69 @@
+package migration
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+)
+
+func TestEmailVerifiedAt(t *testing.T) {
+	ctx := context.Background()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A users table from before e-mail verification existed.
+	if err := db.Exec(`CREATE TABLE users (id integer PRIMARY KEY AUTOINCREMENT, name text NOT NULL, email text NOT NULL UNIQUE,
+		password_hash text NOT NULL, role text NOT NULL DEFAULT 'user', totp_secret text, totp_last_step integer,
+		mfa_enabled_at datetime, created_at datetime)`).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec(`INSERT INTO users (name, email, password_hash, created_at) VALUES
+		('Old', 'old@b.c', 'x', '2024-01-02 03:04:05'), ('Older', 'older@b.c', 'x', '2023-01-02 03:04:05')`).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := EmailVerifiedAt(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 accounts backfilled, got %d", n)
+	}
+	if err := db.AutoMigrate(&models.User{}); err != nil {
+		t.Fatal(err)
+	}
+	var old models.User
+	if err := db.Where("email = ?", "old@b.c").First(&old).Error; err != nil {
+		t.Fatal(err)
+	}
+	if old.EmailVerifiedAt == nil || !old.EmailVerifiedAt.Equal(old.CreatedAt) {
+		t.Fatalf("expected verified at creation time, got %v", old.EmailVerifiedAt)
+	}
+
+	// Accounts created after the column exists stay unverified on later starts.
+	if err := db.Create(&models.User{Name: "New", Email: "new@b.c", PasswordHash: "x"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	n, err = EmailVerifiedAt(ctx, db)
+	if err != nil || n != 0 {
+		t.Fatalf("second run: %d, %v", n, err)
+	}
+	var fresh models.User
+	if err := db.Where("email = ?", "new@b.c").First(&fresh).Error; err != nil {
+		t.Fatal(err)
+	}
+	if fresh.EmailVerified() {
+		t.Fatal("a new account must not be backfilled")
+	}
+
+	// A fresh database has no users table yet; AutoMigrate creates it as usual.
+	empty, _ := gorm.Open(sqlite.Open("file:empty?mode=memory"), &gorm.Config{})
+	if n, err := EmailVerifiedAt(ctx, empty); err != nil || n != 0 {
+		t.Fatalf("fresh database: %d, %v", n, err)
+	}
+}
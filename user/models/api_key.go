@@ -0,0 +1,32 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// APIKey is a long-lived credential a user creates for scripts and devices. The key is shown
+// once; the server keeps its public Prefix, which identifies it, and a hash of its secret.
+type APIKey struct {
+	ID     uint   `gorm:"primaryKey;autoIncrement"`
+	UserID uint   `gorm:"index;not null"`
+	Name   string `gorm:"not null"`
+	Prefix string `gorm:"uniqueIndex;not null"`
+	// SecretHash is the SHA-256 hex digest of the secret part of the key.
+	SecretHash string `gorm:"not null"`
+	// Scopes is the space-separated list of granted scopes.
+	Scopes     string `gorm:"not null"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+// ScopeList returns the granted scopes.
+func (k *APIKey) ScopeList() []string {
+	return strings.Fields(k.Scopes)
+}
+
+// Expired reports whether the key has an expiry that has passed at t.
+func (k *APIKey) Expired(t time.Time) bool {
+	return k.ExpiresAt != nil && !t.Before(*k.ExpiresAt)
+}
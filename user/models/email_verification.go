@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// EmailVerificationToken is a single-use token mailed to confirm an e-mail address. Email is
+// the address it was sent to, so a token stops working once the user changes address again.
+// Only a SHA-256 hash of the token is stored.
+type EmailVerificationToken struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	UserID    uint      `gorm:"index;not null"`
+	Email     string    `gorm:"not null"`
+	TokenHash string    `gorm:"uniqueIndex;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
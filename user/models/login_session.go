@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// LoginSession is one signed-in device of a user. It is created on login and lives as long
+// as the refresh token family with the same FamilyID.
+type LoginSession struct {
+	ID         uint   `gorm:"primaryKey;autoIncrement"`
+	UserID     uint   `gorm:"index;not null"`
+	FamilyID   string `gorm:"uniqueIndex;not null"`
+	DeviceName string
+	UserAgent  string
+	IP         string
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	LastUsedAt time.Time `gorm:"not null"`
+	RevokedAt  *time.Time
+}
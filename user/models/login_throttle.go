@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// LoginThrottle counts the recent failed logins of one account or client IP address.
+type LoginThrottle struct {
+	ID uint `gorm:"primaryKey;autoIncrement"`
+	// Subject is what is throttled: "account:" followed by the e-mail address, or "ip:" and the address.
+	Subject       string    `gorm:"uniqueIndex;not null"`
+	Failures      int       `gorm:"not null"`
+	LastFailureAt time.Time `gorm:"not null"`
+	LockedUntil   *time.Time
+}
+
+// Locked reports whether logins are refused at t and for how much longer.
+func (t *LoginThrottle) Locked(at time.Time) (bool, time.Duration) {
+	if t.LockedUntil == nil || !at.Before(*t.LockedUntil) {
+		return false, 0
+	}
+	return true, t.LockedUntil.Sub(at)
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// OIDCLoginState is a pending social login: the state parameter sent to the identity
+// provider, plus the nonce and PKCE code verifier needed to finish it. Only a SHA-256 hash of
+// the state is stored.
+type OIDCLoginState struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement"`
+	StateHash    string    `gorm:"uniqueIndex;not null"`
+	Provider     string    `gorm:"not null"`
+	Nonce        string    `gorm:"not null"`
+	CodeVerifier string    `gorm:"not null"`
+	ExpiresAt    time.Time `gorm:"not null"`
+	UsedAt       *time.Time
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
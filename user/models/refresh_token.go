@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// RefreshToken is the server-side record of an issued refresh token. Only a SHA-256 hash of
+// the token is stored. Every token minted by rotating another one shares its FamilyID, so a
+// whole login can be revoked at once.
+type RefreshToken struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	UserID    uint      `gorm:"index;not null"`
+	FamilyID  string    `gorm:"index;not null"`
+	TokenHash string    `gorm:"uniqueIndex;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	// UsedAt is set once the token has been exchanged for a new pair.
+	UsedAt *time.Time
+	// RevokedAt is set on logout or when reuse of the family is detected.
+	RevokedAt *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
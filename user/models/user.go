@@ -4,10 +4,41 @@ import (
 	"time"
 )
 
+// Roles a user can hold. Every account starts as RoleUser.
+const (
+	RoleUser  = "user"
+	RoleCoach = "coach"
+	RoleAdmin = "admin"
+)
+
+// ValidRole reports whether role is one of the known roles.
+func ValidRole(role string) bool {
+	return role == RoleUser || role == RoleCoach || role == RoleAdmin
+}
+
 type User struct {
-	ID           uint      `gorm:"primaryKey;autoIncrement"`
-	Name         string    `gorm:"type:text;not null"`
-	Email        string    `gorm:"uniqueIndex;not null"`
-	PasswordHash string    `gorm:"not null"`
+	ID           uint   `gorm:"primaryKey;autoIncrement"`
+	Name         string `gorm:"type:text;not null"`
+	Email        string `gorm:"uniqueIndex;not null"`
+	PasswordHash string `gorm:"not null"`
+	Role         string `gorm:"type:text;not null;default:user"`
+	// EmailVerifiedAt is nil until the current e-mail address has been confirmed.
+	EmailVerifiedAt *time.Time
+	// TOTPSecret is the authenticator app secret, set on enrollment and kept while MFA is enabled.
+	TOTPSecret string `json:"-"`
+	// TOTPLastStep is the time step of the last accepted code; codes of that step or earlier are refused.
+	TOTPLastStep int64 `json:"-"`
+	// MFAEnabledAt is nil until TOTP enrollment has been confirmed with a first code.
+	MFAEnabledAt *time.Time
 	CreatedAt    time.Time `gorm:"autoCreateTime"`
 }
+
+// EmailVerified reports whether the current e-mail address has been confirmed.
+func (u *User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// MFAEnabled reports whether logins need a second factor.
+func (u *User) MFAEnabled() bool {
+	return u.MFAEnabledAt != nil
+}
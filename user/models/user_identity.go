@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// UserIdentity links a user to an account at an external identity provider such as Google.
+// A user may have several identities; each provider account belongs to one user.
+type UserIdentity struct {
+	ID       uint   `gorm:"primaryKey;autoIncrement"`
+	UserID   uint   `gorm:"index;not null"`
+	Provider string `gorm:"uniqueIndex:idx_identity_provider_subject;not null"`
+	// Subject is the provider's stable user ID (the ID token's sub claim).
+	Subject string `gorm:"uniqueIndex:idx_identity_provider_subject;not null"`
+	// Email is the address the provider reported when the identity was linked.
+	Email     string
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
@@ -0,0 +1,272 @@
+// Package oidc signs users in with OpenID Connect identity providers such as Google and
+// Apple, using the authorization code flow with PKCE.
+//
+// Providers are described by their issuer; endpoints and signing keys are discovered from
+// the issuer's /.well-known/openid-configuration on first use and cached.
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Identity is the user an identity provider vouches for.
+type Identity struct {
+	// Subject is the provider's stable user ID; together with the provider name it identifies the account.
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Provider is an identity provider the user can sign in with.
+type Provider interface {
+	// Name is the provider's key in URLs and in stored identities, e.g. "google".
+	Name() string
+	// AuthCodeURL returns the URL the user agent is sent to for signing in.
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	// Exchange redeems an authorization code and returns the identity from the validated ID token.
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error)
+}
+
+// ErrInvalidIDToken is returned when an ID token fails signature, issuer, audience, expiry or nonce checks.
+var ErrInvalidIDToken = errors.New("invalid ID token")
+
+// Config describes an OpenID Connect client registration.
+type Config struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL must match a redirect URI registered with the provider.
+	RedirectURL string
+	// Scopes defaults to openid, email and profile.
+	Scopes []string
+	// AuthParams are extra query parameters of the authorization request.
+	AuthParams map[string]string
+	// HTTPClient defaults to a client with a 10 second timeout.
+	HTTPClient *http.Client
+}
+
+// Google returns the configuration for "Sign in with Google".
+func Google(clientID, clientSecret, redirectURL string) Config {
+	return Config{Name: "google", Issuer: "https://accounts.google.com", ClientID: clientID, ClientSecret: clientSecret, RedirectURL: redirectURL}
+}
+
+// Apple returns the configuration for "Sign in with Apple". Apple's client secret is an ES256
+// JWT signed with the team's private key, generated outside this service. Apple only returns
+// the e-mail address on the first sign-in and answers with a form POST to the redirect URL.
+func Apple(clientID, clientSecret, redirectURL string) Config {
+	return Config{
+		Name: "apple", Issuer: "https://appleid.apple.com", ClientID: clientID, ClientSecret: clientSecret, RedirectURL: redirectURL,
+		Scopes:     []string{"openid", "email", "name"},
+		AuthParams: map[string]string{"response_mode": "form_post"},
+	}
+}
+
+// Client is a Provider for any OpenID Connect compliant issuer.
+type Client struct {
+	cfg  Config
+	http *http.Client
+
+	mu        sync.Mutex
+	discovery *discovery
+	keys      *keySet
+}
+
+// New returns a client for the provider described by cfg. Nothing is fetched until first use.
+func New(cfg Config) *Client {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	hc := cfg.HTTPClient
+	if hc == nil {
+		hc = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Client{cfg: cfg, http: hc}
+}
+
+// Name implements Provider.
+func (c *Client) Name() string {
+	return c.cfg.Name
+}
+
+// AuthCodeURL implements Provider. The PKCE challenge uses the S256 method.
+func (c *Client) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	d, err := c.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", c.cfg.ClientID)
+	q.Set("redirect_uri", c.cfg.RedirectURL)
+	q.Set("scope", strings.Join(c.cfg.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", codeChallenge)
+	q.Set("code_challenge_method", "S256")
+	for k, v := range c.cfg.AuthParams {
+		q.Set(k, v)
+	}
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return d.AuthorizationEndpoint + sep + q.Encode(), nil
+}
+
+// Exchange implements Provider.
+func (c *Client) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error) {
+	d, err := c.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", c.cfg.RedirectURL)
+	form.Set("client_id", c.cfg.ClientID)
+	form.Set("code_verifier", codeVerifier)
+	if c.cfg.ClientSecret != "" {
+		form.Set("client_secret", c.cfg.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var tok struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := c.doJSON(req, &tok); err != nil && tok.Error == "" {
+		return nil, fmt.Errorf("oidc: token request: %w", err)
+	}
+	if tok.Error != "" {
+		return nil, fmt.Errorf("oidc: token request: %s %s", tok.Error, tok.ErrorDescription)
+	}
+	if tok.IDToken == "" {
+		return nil, errors.New("oidc: token response has no id_token")
+	}
+	return c.verify(ctx, d, tok.IDToken, nonce)
+}
+
+// idTokenClaims are the ID token claims this package reads.
+type idTokenClaims struct {
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified flexBool `json:"email_verified"`
+	Name          string   `json:"name"`
+	jwt.RegisteredClaims
+}
+
+// flexBool accepts both JSON booleans and the "true"/"false" strings Apple sends.
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	*b = flexBool(s == "true")
+	return nil
+}
+
+// verify checks the ID token's signature against the issuer's keys, its issuer, audience,
+// expiry and nonce.
+func (c *Client) verify(ctx context.Context, d *discovery, raw, nonce string) (*Identity, error) {
+	var claims idTokenClaims
+	_, err := jwt.ParseWithClaims(raw, &claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return c.key(ctx, d, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "ES256"}),
+		jwt.WithIssuer(d.Issuer),
+		jwt.WithAudience(c.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+	if claims.Nonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+	return &Identity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+// discovery is the part of the provider metadata this package uses.
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// discover fetches and caches the provider metadata.
+func (c *Client) discover(ctx context.Context) (*discovery, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.discovery != nil {
+		return c.discovery, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.cfg.Issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	var d discovery
+	if err := c.doJSON(req, &d); err != nil {
+		return nil, fmt.Errorf("oidc: discovery for %s: %w", c.cfg.Name, err)
+	}
+	if d.Issuer != c.cfg.Issuer {
+		return nil, fmt.Errorf("oidc: discovery for %s: issuer %q does not match %q", c.cfg.Name, d.Issuer, c.cfg.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc: discovery for %s: incomplete provider metadata", c.cfg.Name)
+	}
+	c.discovery = &d
+	return c.discovery, nil
+}
+
+// doJSON sends req and decodes the JSON body into v. Non-2xx responses are decoded too, so
+// callers can read OAuth error fields, and reported as an error.
+func (c *Client) doJSON(req *http.Request, v any) error {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	decodeErr := json.NewDecoder(resp.Body).Decode(v)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return decodeErr
+}
+
+// PKCEChallenge returns the S256 code challenge of a PKCE code verifier.
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
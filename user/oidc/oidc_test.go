@@ -0,0 +1,187 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// mockProvider is a minimal OpenID Connect provider: discovery, JWKS and a token endpoint
+// that checks the PKCE verifier and answers with an ID token.
+type mockProvider struct {
+	*httptest.Server
+	rsaKey *rsa.PrivateKey
+	ecKey  *ecdsa.PrivateKey
+
+	mu sync.Mutex
+	// grants maps issued authorization codes to their PKCE challenge and ID token claims.
+	grants map[string]grant
+	// jwksHits counts JWKS downloads.
+	jwksHits int
+}
+
+type grant struct {
+	challenge string
+	claims    jwt.MapClaims
+	method    jwt.SigningMethod
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	m := &mockProvider{rsaKey: rsaKey, ecKey: ecKey, grants: map[string]grant{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 m.URL,
+			"authorization_endpoint": m.URL + "/authorize",
+			"token_endpoint":         m.URL + "/token",
+			"jwks_uri":               m.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		m.jwksHits++
+		m.mu.Unlock()
+		b64 := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa1", "use": "sig", "n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+			{"kty": "EC", "kid": "ec1", "crv": "P-256", "x": b64(ecKey.X.FillBytes(make([]byte, 32))), "y": b64(ecKey.Y.FillBytes(make([]byte, 32)))},
+		}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		g, ok := m.grants[r.PostFormValue("code")]
+		delete(m.grants, r.PostFormValue("code"))
+		m.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if !ok || PKCEChallenge(r.PostFormValue("code_verifier")) != g.challenge || r.PostFormValue("client_id") != "client-1" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": m.sign(t, g), "token_type": "Bearer"})
+	})
+	m.Server = httptest.NewServer(mux)
+	t.Cleanup(m.Close)
+	return m
+}
+
+// authorize simulates the user signing in: it issues a code for the PKCE challenge of an
+// authorization URL with the given extra claims.
+func (m *mockProvider) authorize(t *testing.T, authURL string, method jwt.SigningMethod, extra jwt.MapClaims) (code, state string) {
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	q := u.Query()
+	require.Equal(t, "S256", q.Get("code_challenge_method"))
+
+	claims := jwt.MapClaims{
+		"iss":   m.URL,
+		"aud":   q.Get("client_id"),
+		"sub":   "user-123",
+		"email": "sam@example.com",
+		"nonce": q.Get("nonce"),
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}
+	for k, v := range extra {
+		claims[k] = v
+	}
+	code = "code-" + q.Get("state")
+	m.mu.Lock()
+	m.grants[code] = grant{challenge: q.Get("code_challenge"), claims: claims, method: method}
+	m.mu.Unlock()
+	return code, q.Get("state")
+}
+
+func (m *mockProvider) sign(t *testing.T, g grant) string {
+	tok := jwt.NewWithClaims(g.method, g.claims)
+	var key any = m.rsaKey
+	tok.Header["kid"] = "rsa1"
+	if g.method == jwt.SigningMethodES256 {
+		key = m.ecKey
+		tok.Header["kid"] = "ec1"
+	}
+	s, err := tok.SignedString(key)
+	require.NoError(t, err)
+	return s
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	m := newMockProvider(t)
+	c := New(Config{Name: "mock", Issuer: m.URL, ClientID: "client-1", RedirectURL: "app://callback"})
+	ctx := context.Background()
+
+	for _, method := range []jwt.SigningMethod{jwt.SigningMethodRS256, jwt.SigningMethodES256} {
+		verifier := "verifier-" + method.Alg() + "-0123456789012345678901234567890123456789"
+		authURL, err := c.AuthCodeURL(ctx, "state-"+method.Alg(), "nonce-1", PKCEChallenge(verifier))
+		require.NoError(t, err)
+		code, state := m.authorize(t, authURL, method, jwt.MapClaims{"email_verified": "true", "name": "Sam"})
+		require.Equal(t, "state-"+method.Alg(), state)
+
+		id, err := c.Exchange(ctx, code, verifier, "nonce-1")
+		require.NoError(t, err, method.Alg())
+		require.Equal(t, &Identity{Subject: "user-123", Email: "sam@example.com", EmailVerified: true, Name: "Sam"}, id)
+	}
+	require.Equal(t, 1, m.jwksHits, "keys are cached")
+}
+
+func TestExchangeRejects(t *testing.T) {
+	m := newMockProvider(t)
+	c := New(Config{Name: "mock", Issuer: m.URL, ClientID: "client-1", RedirectURL: "app://callback"})
+	ctx := context.Background()
+	const verifier = "verifier-0123456789012345678901234567890123456789"
+
+	for name, tc := range map[string]struct {
+		verifier string
+		nonce    string
+		claims   jwt.MapClaims
+	}{
+		"wrong PKCE verifier": {verifier: "other-verifier", nonce: "n"},
+		"wrong nonce":         {verifier: verifier, nonce: "replayed"},
+		"wrong audience":      {verifier: verifier, nonce: "n", claims: jwt.MapClaims{"aud": "someone-else"}},
+		"wrong issuer":        {verifier: verifier, nonce: "n", claims: jwt.MapClaims{"iss": "https://evil.example"}},
+		"expired":             {verifier: verifier, nonce: "n", claims: jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}},
+	} {
+		authURL, err := c.AuthCodeURL(ctx, name, "n", PKCEChallenge(verifier))
+		require.NoError(t, err)
+		code, _ := m.authorize(t, authURL, jwt.SigningMethodRS256, tc.claims)
+
+		_, err = c.Exchange(ctx, code, tc.verifier, tc.nonce)
+		require.Error(t, err, name)
+	}
+
+	// A token signed by a key outside the provider's JWKS is refused.
+	authURL, err := c.AuthCodeURL(ctx, "forged", "n", PKCEChallenge(verifier))
+	require.NoError(t, err)
+	code, _ := m.authorize(t, authURL, jwt.SigningMethodRS256, nil)
+	m.rsaKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, err = c.Exchange(ctx, code, verifier, "n")
+	require.ErrorIs(t, err, ErrInvalidIDToken)
+}
+
+func TestDiscoveryIssuerMismatch(t *testing.T) {
+	m := newMockProvider(t)
+	// The metadata is found, but names an issuer other than the configured one.
+	c := New(Config{Name: "mock", Issuer: m.URL + "/", ClientID: "client-1"})
+	_, err := c.AuthCodeURL(context.Background(), "s", "n", "c")
+	require.ErrorContains(t, err, "does not match")
+}
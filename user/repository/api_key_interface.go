@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+)
+
+// APIKeyRepository persists the API keys of users.
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	GetByID(ctx context.Context, id uint) (*models.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*models.APIKey, error)
+	// ListByUser lists the keys of a user, newest first.
+	ListByUser(ctx context.Context, userID uint) ([]*models.APIKey, error)
+	Update(ctx context.Context, key *models.APIKey) error
+	Delete(ctx context.Context, id uint) error
+	Touch(ctx context.Context, id uint, at time.Time) error
+	// DeleteByUser removes every key of a user.
+	DeleteByUser(ctx context.Context, userID uint) error
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+)
+
+// EmailVerificationRepository persists e-mail verification tokens.
+type EmailVerificationRepository interface {
+	Create(ctx context.Context, token *models.EmailVerificationToken) error
+	GetByHash(ctx context.Context, hash string) (*models.EmailVerificationToken, error)
+	// LatestByUser returns the token issued to a user most recently, used or not.
+	LatestByUser(ctx context.Context, userID uint) (*models.EmailVerificationToken, error)
+	// MarkUsed sets UsedAt on a token that has not been used yet and reports whether it did.
+	MarkUsed(ctx context.Context, id uint, at time.Time) (bool, error)
+	// InvalidateByUser marks every unused token of a user as used.
+	InvalidateByUser(ctx context.Context, userID uint, at time.Time) error
+	// DeleteByUser removes every token of a user.
+	DeleteByUser(ctx context.Context, userID uint) error
+}
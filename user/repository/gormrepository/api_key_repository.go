@@ -0,0 +1,67 @@
+package gormrepository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/repository"
+)
+
+// gormAPIKeyRepository implements repository.APIKeyRepository using GORM.
+type gormAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository returns a GORM-backed APIKey repository.
+func NewAPIKeyRepository(db *gorm.DB) repository.APIKeyRepository {
+	return &gormAPIKeyRepository{db: db}
+}
+
+func (r *gormAPIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+func (r *gormAPIKeyRepository) GetByID(ctx context.Context, id uint) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.db.WithContext(ctx).First(&key, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *gormAPIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.db.WithContext(ctx).Where("prefix = ?", prefix).First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *gormAPIKeyRepository) ListByUser(ctx context.Context, userID uint) ([]*models.APIKey, error) {
+	var keys []*models.APIKey
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id DESC").Find(&keys).Error
+	return keys, err
+}
+
+func (r *gormAPIKeyRepository) Update(ctx context.Context, key *models.APIKey) error {
+	return r.db.WithContext(ctx).Save(key).Error
+}
+
+func (r *gormAPIKeyRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.APIKey{}, id).Error
+}
+
+func (r *gormAPIKeyRepository) Touch(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.APIKey{}).
+		Where("id = ?", id).
+		Update("last_used_at", at).Error
+}
+
+func (r *gormAPIKeyRepository) DeleteByUser(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.APIKey{}).Error
+}
@@ -0,0 +1,60 @@
+package gormrepository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/repository"
+)
+
+// gormEmailVerificationRepository implements repository.EmailVerificationRepository using GORM.
+type gormEmailVerificationRepository struct {
+	db *gorm.DB
+}
+
+// NewEmailVerificationRepository returns a GORM-backed EmailVerificationToken repository.
+func NewEmailVerificationRepository(db *gorm.DB) repository.EmailVerificationRepository {
+	return &gormEmailVerificationRepository{db: db}
+}
+
+func (r *gormEmailVerificationRepository) Create(ctx context.Context, token *models.EmailVerificationToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *gormEmailVerificationRepository) GetByHash(ctx context.Context, hash string) (*models.EmailVerificationToken, error) {
+	var token models.EmailVerificationToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *gormEmailVerificationRepository) LatestByUser(ctx context.Context, userID uint) (*models.EmailVerificationToken, error) {
+	var token models.EmailVerificationToken
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC, id DESC").First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *gormEmailVerificationRepository) MarkUsed(ctx context.Context, id uint, at time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&models.EmailVerificationToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *gormEmailVerificationRepository) InvalidateByUser(ctx context.Context, userID uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.EmailVerificationToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", at).Error
+}
+
+func (r *gormEmailVerificationRepository) DeleteByUser(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.EmailVerificationToken{}).Error
+}
@@ -0,0 +1,74 @@
+package gormrepository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/repository"
+)
+
+// gormLoginSessionRepository implements repository.LoginSessionRepository using GORM.
+type gormLoginSessionRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginSessionRepository returns a GORM-backed LoginSession repository.
+func NewLoginSessionRepository(db *gorm.DB) repository.LoginSessionRepository {
+	return &gormLoginSessionRepository{db: db}
+}
+
+func (r *gormLoginSessionRepository) Create(ctx context.Context, session *models.LoginSession) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+func (r *gormLoginSessionRepository) GetByID(ctx context.Context, id uint) (*models.LoginSession, error) {
+	var session models.LoginSession
+	err := r.db.WithContext(ctx).First(&session, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *gormLoginSessionRepository) GetByFamily(ctx context.Context, familyID string) (*models.LoginSession, error) {
+	var session models.LoginSession
+	err := r.db.WithContext(ctx).Where("family_id = ?", familyID).First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *gormLoginSessionRepository) ListActiveByUser(ctx context.Context, userID uint, since time.Time) ([]*models.LoginSession, error) {
+	var sessions []*models.LoginSession
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND last_used_at > ?", userID, since).
+		Order("last_used_at DESC, id DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *gormLoginSessionRepository) Touch(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.LoginSession{}).
+		Where("id = ?", id).
+		Update("last_used_at", at).Error
+}
+
+func (r *gormLoginSessionRepository) Revoke(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.LoginSession{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+}
+
+func (r *gormLoginSessionRepository) RevokeByUser(ctx context.Context, userID uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.LoginSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+}
+
+func (r *gormLoginSessionRepository) DeleteByUser(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.LoginSession{}).Error
+}
@@ -0,0 +1,98 @@
+package gormrepository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/repository"
+)
+
+// gormLoginThrottleRepository implements repository.LoginThrottleRepository using GORM. Failures
+// are counted with an upsert, so concurrent requests on several servers add up correctly.
+type gormLoginThrottleRepository struct {
+	db *gorm.DB
+
+	mu sync.Mutex
+	// window is the longest window seen; throttles idle for longer are pruned.
+	window    time.Duration
+	lastPrune time.Time
+}
+
+// NewLoginThrottleRepository returns a GORM-backed LoginThrottle repository.
+func NewLoginThrottleRepository(db *gorm.DB) repository.LoginThrottleRepository {
+	return &gormLoginThrottleRepository{db: db}
+}
+
+func (r *gormLoginThrottleRepository) Get(ctx context.Context, subject string) (*models.LoginThrottle, error) {
+	var throttle models.LoginThrottle
+	err := r.db.WithContext(ctx).Where("subject = ?", subject).First(&throttle).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+func (r *gormLoginThrottleRepository) RecordFailure(ctx context.Context, subject string, at time.Time, window time.Duration) (*models.LoginThrottle, error) {
+	r.prune(ctx, at, window)
+
+	var throttle models.LoginThrottle
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "subject"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"failures":        gorm.Expr("CASE WHEN login_throttles.last_failure_at < ? THEN 1 ELSE login_throttles.failures + 1 END", at.Add(-window)),
+				"last_failure_at": at,
+			}),
+		}).Create(&models.LoginThrottle{Subject: subject, Failures: 1, LastFailureAt: at}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("subject = ?", subject).First(&throttle).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+func (r *gormLoginThrottleRepository) Lock(ctx context.Context, subject string, until time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.LoginThrottle{}).
+		Where("subject = ?", subject).
+		Update("locked_until", until).Error
+}
+
+func (r *gormLoginThrottleRepository) Reset(ctx context.Context, subject string) error {
+	return r.db.WithContext(ctx).Where("subject = ?", subject).Delete(&models.LoginThrottle{}).Error
+}
+
+// prune deletes throttles that are neither locked nor failed recently, at most once per window
+// and server, so the table of client addresses cannot grow without bound. A failed prune is
+// only logged; the next one catches up.
+func (r *gormLoginThrottleRepository) prune(ctx context.Context, now time.Time, window time.Duration) {
+	r.mu.Lock()
+	r.window = max(r.window, window)
+	if now.Sub(r.lastPrune) < r.window {
+		r.mu.Unlock()
+		return
+	}
+	r.lastPrune = now
+	cutoff := now.Add(-r.window)
+	r.mu.Unlock()
+
+	err := r.db.WithContext(ctx).
+		Where("last_failure_at < ? AND (locked_until IS NULL OR locked_until <= ?)", cutoff, now).
+		Delete(&models.LoginThrottle{}).Error
+	if err != nil {
+		log.Printf("pruning login throttles: %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package gormrepository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/repository"
+)
+
+// gormOIDCLoginStateRepository implements repository.OIDCLoginStateRepository using GORM.
+type gormOIDCLoginStateRepository struct {
+	db *gorm.DB
+}
+
+// NewOIDCLoginStateRepository returns a GORM-backed OIDCLoginState repository.
+func NewOIDCLoginStateRepository(db *gorm.DB) repository.OIDCLoginStateRepository {
+	return &gormOIDCLoginStateRepository{db: db}
+}
+
+func (r *gormOIDCLoginStateRepository) Create(ctx context.Context, state *models.OIDCLoginState) error {
+	return r.db.WithContext(ctx).Create(state).Error
+}
+
+func (r *gormOIDCLoginStateRepository) GetByHash(ctx context.Context, hash string) (*models.OIDCLoginState, error) {
+	var state models.OIDCLoginState
+	err := r.db.WithContext(ctx).Where("state_hash = ?", hash).First(&state).Error
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *gormOIDCLoginStateRepository) MarkUsed(ctx context.Context, id uint, at time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&models.OIDCLoginState{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	return res.RowsAffected == 1, res.Error
+}
@@ -0,0 +1,37 @@
+package gormrepository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/repository"
+)
+
+// gormUserIdentityRepository implements repository.UserIdentityRepository using GORM.
+type gormUserIdentityRepository struct {
+	db *gorm.DB
+}
+
+// NewUserIdentityRepository returns a GORM-backed UserIdentity repository.
+func NewUserIdentityRepository(db *gorm.DB) repository.UserIdentityRepository {
+	return &gormUserIdentityRepository{db: db}
+}
+
+func (r *gormUserIdentityRepository) Create(ctx context.Context, identity *models.UserIdentity) error {
+	return r.db.WithContext(ctx).Create(identity).Error
+}
+
+func (r *gormUserIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	err := r.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *gormUserIdentityRepository) DeleteByUser(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.UserIdentity{}).Error
+}
@@ -5,6 +5,7 @@ import (
 
 	"gorm.io/gorm"
 
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
 	"github.com/VibeTeam/fitness-tracker-backend/user/models"
 	"github.com/VibeTeam/fitness-tracker-backend/user/repository"
 )
@@ -49,12 +50,22 @@ func (r *gormUserRepository) Delete(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Delete(&models.User{}, id).Error
 }
 
-func (r *gormUserRepository) List(ctx context.Context, limit, offset int) ([]*models.User, error) {
+func (r *gormUserRepository) AdvanceTOTPStep(ctx context.Context, id uint, step int64) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND totp_last_step < ?", id, step).
+		Update("totp_last_step", step)
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *gormUserRepository) List(ctx context.Context, limit int, after *pagination.Cursor) ([]*models.User, error) {
+	db := r.db.WithContext(ctx)
+	if after != nil {
+		db = db.Where("created_at < ? OR (created_at = ? AND id < ?)", after.Time, after.Time, after.ID)
+	}
 	var users []*models.User
-	err := r.db.WithContext(ctx).
+	err := db.
 		Limit(limit).
-		Offset(offset).
-		Order("created_at DESC").
+		Order("created_at DESC, id DESC").
 		Find(&users).Error
 	return users, err
 }
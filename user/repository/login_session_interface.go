@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+)
+
+// LoginSessionRepository persists the signed-in devices of users.
+type LoginSessionRepository interface {
+	Create(ctx context.Context, session *models.LoginSession) error
+	GetByID(ctx context.Context, id uint) (*models.LoginSession, error)
+	GetByFamily(ctx context.Context, familyID string) (*models.LoginSession, error)
+	// ListActiveByUser lists the sessions of a user that are not revoked and were used after since,
+	// most recently used first.
+	ListActiveByUser(ctx context.Context, userID uint, since time.Time) ([]*models.LoginSession, error)
+	Touch(ctx context.Context, id uint, at time.Time) error
+	Revoke(ctx context.Context, id uint, at time.Time) error
+	// RevokeByUser revokes every session of a user that is not revoked yet.
+	RevokeByUser(ctx context.Context, userID uint, at time.Time) error
+	// DeleteByUser removes every session of a user, with the addresses and user agents recorded for it.
+	DeleteByUser(ctx context.Context, userID uint) error
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+)
+
+// LoginThrottleRepository stores failed login counters. Implementations must update
+// counters atomically, since concurrent guesses are exactly what they defend against.
+type LoginThrottleRepository interface {
+	// Get returns the throttle of a subject, or nil without an error if it has none.
+	Get(ctx context.Context, subject string) (*models.LoginThrottle, error)
+	// RecordFailure counts a failed login at the given time and returns the updated throttle.
+	// The count starts over when the previous failure is older than window.
+	RecordFailure(ctx context.Context, subject string, at time.Time, window time.Duration) (*models.LoginThrottle, error)
+	// Lock refuses logins of a subject until the given time.
+	Lock(ctx context.Context, subject string, until time.Time) error
+	// Reset forgets the failures of a subject.
+	Reset(ctx context.Context, subject string) error
+}
@@ -0,0 +1,88 @@
+// Package memrepository holds in-memory repositories for state that may live in a single
+// process, such as login throttles of a server without shared storage.
+package memrepository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/repository"
+)
+
+// memLoginThrottleRepository implements repository.LoginThrottleRepository in memory. Counters
+// are lost on restart and not shared between servers.
+type memLoginThrottleRepository struct {
+	mu        sync.Mutex
+	throttles map[string]*models.LoginThrottle
+	// window is the longest window seen; throttles idle for longer are pruned.
+	window    time.Duration
+	lastPrune time.Time
+}
+
+// NewLoginThrottleRepository returns an in-memory LoginThrottle repository.
+func NewLoginThrottleRepository() repository.LoginThrottleRepository {
+	return &memLoginThrottleRepository{throttles: map[string]*models.LoginThrottle{}}
+}
+
+func (r *memLoginThrottleRepository) Get(_ context.Context, subject string) (*models.LoginThrottle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.throttles[subject]
+	if !ok {
+		return nil, nil
+	}
+	cp := *t
+	return &cp, nil
+}
+
+func (r *memLoginThrottleRepository) RecordFailure(_ context.Context, subject string, at time.Time, window time.Duration) (*models.LoginThrottle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.window = max(r.window, window)
+	r.prune(at)
+
+	t, ok := r.throttles[subject]
+	if !ok {
+		t = &models.LoginThrottle{Subject: subject}
+		r.throttles[subject] = t
+	}
+	if t.LastFailureAt.Before(at.Add(-window)) {
+		t.Failures = 0
+	}
+	t.Failures++
+	t.LastFailureAt = at
+	cp := *t
+	return &cp, nil
+}
+
+func (r *memLoginThrottleRepository) Lock(_ context.Context, subject string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if t, ok := r.throttles[subject]; ok {
+		t.LockedUntil = &until
+	}
+	return nil
+}
+
+func (r *memLoginThrottleRepository) Reset(_ context.Context, subject string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.throttles, subject)
+	return nil
+}
+
+// prune drops throttles that are neither locked nor failed recently, at most once per window,
+// so the map of client addresses cannot grow without bound.
+func (r *memLoginThrottleRepository) prune(now time.Time) {
+	if now.Sub(r.lastPrune) < r.window {
+		return
+	}
+	r.lastPrune = now
+	for subject, t := range r.throttles {
+		if locked, _ := t.Locked(now); !locked && t.LastFailureAt.Before(now.Add(-r.window)) {
+			delete(r.throttles, subject)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+)
+
+// OIDCLoginStateRepository persists pending social logins.
+type OIDCLoginStateRepository interface {
+	Create(ctx context.Context, state *models.OIDCLoginState) error
+	GetByHash(ctx context.Context, hash string) (*models.OIDCLoginState, error)
+	// MarkUsed sets UsedAt on a state that has not been used yet and reports whether it did.
+	MarkUsed(ctx context.Context, id uint, at time.Time) (bool, error)
+}
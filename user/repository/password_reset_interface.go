@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+)
+
+// PasswordResetRepository persists password reset tokens.
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token *models.PasswordResetToken) error
+	GetByHash(ctx context.Context, hash string) (*models.PasswordResetToken, error)
+	// LatestByUser returns the token issued to a user most recently, used or not.
+	LatestByUser(ctx context.Context, userID uint) (*models.PasswordResetToken, error)
+	// MarkUsed sets UsedAt on a token that has not been used yet and reports whether it did.
+	MarkUsed(ctx context.Context, id uint, at time.Time) (bool, error)
+	// InvalidateByUser marks every unused token of a user as used.
+	InvalidateByUser(ctx context.Context, userID uint, at time.Time) error
+	// DeleteByUser removes every token of a user.
+	DeleteByUser(ctx context.Context, userID uint) error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// RecoveryCodeRepository persists MFA recovery codes.
+type RecoveryCodeRepository interface {
+	// Replace deletes every recovery code of a user and stores the given hashes instead.
+	Replace(ctx context.Context, userID uint, hashes []string) error
+	// Use marks the user's unused code with the given hash as used and reports whether one existed.
+	Use(ctx context.Context, userID uint, hash string, at time.Time) (bool, error)
+	// CountUnused returns how many recovery codes the user has left.
+	CountUnused(ctx context.Context, userID uint) (int, error)
+	// DeleteByUser removes every recovery code of a user.
+	DeleteByUser(ctx context.Context, userID uint) error
+}
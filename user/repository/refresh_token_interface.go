@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+)
+
+// RefreshTokenRepository persists issued refresh tokens for rotation and revocation.
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *models.RefreshToken) error
+	GetByHash(ctx context.Context, hash string) (*models.RefreshToken, error)
+	// MarkUsed sets UsedAt on a token that has not been used yet. It reports false when the
+	// token was already used, so two concurrent refreshes cannot both succeed.
+	MarkUsed(ctx context.Context, id uint, at time.Time) (bool, error)
+	// RevokeFamily revokes every token of a family that is not revoked yet.
+	RevokeFamily(ctx context.Context, familyID string, at time.Time) error
+	// RevokeByUser revokes every token of a user that is not revoked yet.
+	RevokeByUser(ctx context.Context, userID uint, at time.Time) error
+	// DeleteByUser removes every token of a user.
+	DeleteByUser(ctx context.Context, userID uint) error
+}
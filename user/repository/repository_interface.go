@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
 	"github.com/VibeTeam/fitness-tracker-backend/user/models"
 )
 
@@ -12,6 +13,11 @@ type UserRepository interface {
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
 	Update(ctx context.Context, user *models.User) error
 	Delete(ctx context.Context, id uint) error
-	List(ctx context.Context, limit, offset int) ([]*models.User, error)
+	// AdvanceTOTPStep records step as the user's last accepted TOTP step unless the same or a
+	// later step is already recorded, and reports whether it did. The check and the write are
+	// one statement, so a code can be accepted only once even under concurrent requests.
+	AdvanceTOTPStep(ctx context.Context, id uint, step int64) (bool, error)
+	// List returns up to limit users, newest first, starting after the given cursor.
+	List(ctx context.Context, limit int, after *pagination.Cursor) ([]*models.User, error)
 	Count(ctx context.Context) (int, error)
 }
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+)
+
+// UserIdentityRepository persists links between users and identity provider accounts.
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *models.UserIdentity) error
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*models.UserIdentity, error)
+	// DeleteByUser removes every identity of a user.
+	DeleteByUser(ctx context.Context, userID uint) error
+}
@@ -0,0 +1,57 @@
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA1 test key of RFC 6238, "12345678901234567890", in base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCodeRFC6238(t *testing.T) {
+	// The RFC lists eight-digit codes; six-digit codes are their last six digits.
+	for unix, want := range map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	} {
+		got, err := Code(rfcSecret, Step(time.Unix(unix, 0)))
+		require.NoError(t, err)
+		require.Equal(t, want, got, unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret := GenerateSecret()
+	now := time.Unix(1_700_000_000, 0)
+	code, err := Code(secret, Step(now))
+	require.NoError(t, err)
+
+	step, ok := Validate(secret, code, now)
+	require.True(t, ok)
+	require.Equal(t, Step(now), step)
+
+	_, ok = Validate(secret, code, now.Add(Period))
+	require.True(t, ok, "codes of the previous period are accepted")
+	_, ok = Validate(secret, code, now.Add(3*Period))
+	require.False(t, ok)
+	_, ok = Validate(secret, "12345", now)
+	require.False(t, ok)
+	_, ok = Validate("not base32!", code, now)
+	require.False(t, ok)
+}
+
+func TestURI(t *testing.T) {
+	u, err := url.Parse(URI("Fitness Tracker", "bob@example.com", "ABC"))
+	require.NoError(t, err)
+	require.Equal(t, "otpauth", u.Scheme)
+	require.Equal(t, "totp", u.Host)
+	require.Equal(t, "/Fitness Tracker:bob@example.com", u.Path)
+	require.Equal(t, "ABC", u.Query().Get("secret"))
+	require.Equal(t, "Fitness Tracker", u.Query().Get("issuer"))
+}
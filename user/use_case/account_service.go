@@ -0,0 +1,223 @@
+package use_case
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/repository"
+)
+
+// UserDataCleaner removes data kept for a user outside the account row. The repositories of
+// login sessions, tokens and the workout module implement it so deleting an account does not
+// leave orphaned data behind.
+// DeleteByUser must be idempotent: a deletion that failed part way is retried from the start.
+type UserDataCleaner interface {
+	DeleteByUser(ctx context.Context, userID uint) error
+}
+
+// AccountService implements self-service account management and account deletion.
+type AccountService struct {
+	repo     repository.UserRepository
+	auth     *AuthService
+	verifier *EmailVerificationService
+	cleaners []UserDataCleaner
+}
+
+// NewAccountService wires the user repository, the auth service used to end sessions, the
+// verification service mailing links for new addresses (nil disables verification) and the
+// cleaners run when an account is deleted.
+func NewAccountService(repo repository.UserRepository, auth *AuthService, verifier *EmailVerificationService, cleaners ...UserDataCleaner) *AccountService {
+	return &AccountService{repo: repo, auth: auth, verifier: verifier, cleaners: cleaners}
+}
+
+var (
+	// ErrWrongPassword is returned when the current password supplied for a sensitive change does not match.
+	ErrWrongPassword = errors.New("current password is incorrect")
+	// ErrPasswordNotSet is returned for sensitive changes to accounts created through social login,
+	// which have no password to confirm them with until one is set through a mailed reset link.
+	ErrPasswordNotSet = errors.New("the account has no password yet; set one with the link from POST /auth/password/link")
+)
+
+// checkPassword confirms a sensitive change with the user's current password.
+func checkPassword(user *models.User, password string) error {
+	if user.PasswordHash == "" {
+		return ErrPasswordNotSet
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return ErrWrongPassword
+	}
+	return nil
+}
+
+// SignUp creates an account with the user role and mails a verification link for its address.
+func (s *AccountService) SignUp(ctx context.Context, name, email, password string) (*models.User, error) {
+	if _, err := s.repo.GetByEmail(ctx, email); err == nil {
+		return nil, ErrEmailAlreadyUsed
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Name:         name,
+		Email:        email,
+		PasswordHash: string(hash),
+		Role:         models.RoleUser,
+	}
+	if err := s.repo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	s.sendVerification(ctx, user)
+	return user, nil
+}
+
+// ProfileUpdate lists the profile fields to change; nil fields are left as they are.
+type ProfileUpdate struct {
+	Name        *string
+	Email       *string
+	NewPassword *string
+	// CurrentPassword must be given to change the e-mail address or the password.
+	CurrentPassword string
+	// SessionID is the session making the change. A password change ends every other session.
+	SessionID uint
+}
+
+// UpdateProfile applies u to the user. Changing the e-mail address marks it unverified and
+// mails a verification link to the new address.
+func (s *AccountService) UpdateProfile(ctx context.Context, userID uint, u ProfileUpdate) (*models.User, error) {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	emailChanged := u.Email != nil && *u.Email != user.Email
+	if emailChanged || u.NewPassword != nil {
+		if err := checkPassword(user, u.CurrentPassword); err != nil {
+			return nil, err
+		}
+	}
+	if emailChanged {
+		if _, err := s.repo.GetByEmail(ctx, *u.Email); err == nil {
+			return nil, ErrEmailAlreadyUsed
+		}
+		user.Email = *u.Email
+		user.EmailVerifiedAt = nil
+	}
+	if u.Name != nil {
+		user.Name = *u.Name
+	}
+	if u.NewPassword != nil {
+		hash, err := bcrypt.GenerateFromPassword([]byte(*u.NewPassword), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		user.PasswordHash = string(hash)
+	}
+
+	if err := s.repo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	if u.NewPassword != nil {
+		if err := s.auth.RevokeOtherSessions(ctx, userID, u.SessionID); err != nil {
+			return nil, err
+		}
+	}
+	if emailChanged {
+		s.sendVerification(ctx, user)
+	}
+	return user, nil
+}
+
+// AdminUpdate lists the fields an admin changes on an account; nil fields are left as they are.
+type AdminUpdate struct {
+	Name     *string
+	Email    *string
+	Password *string
+	Role     *string
+}
+
+// AdminUpdate applies u to an account without asking for its password. Like UpdateProfile, a
+// new e-mail address has to be verified again, and a new password ends every session of the user.
+func (s *AccountService) AdminUpdate(ctx context.Context, user *models.User, u AdminUpdate) error {
+	emailChanged := u.Email != nil && *u.Email != user.Email
+	if emailChanged {
+		if _, err := s.repo.GetByEmail(ctx, *u.Email); err == nil {
+			return ErrEmailAlreadyUsed
+		}
+		user.Email = *u.Email
+		user.EmailVerifiedAt = nil
+	}
+	if u.Name != nil {
+		user.Name = *u.Name
+	}
+	if u.Role != nil {
+		user.Role = *u.Role
+	}
+	if u.Password != nil {
+		hash, err := bcrypt.GenerateFromPassword([]byte(*u.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+		user.PasswordHash = string(hash)
+	}
+
+	if err := s.repo.Update(ctx, user); err != nil {
+		return err
+	}
+	if u.Password != nil {
+		if err := s.auth.Logout(ctx, user.ID, ""); err != nil {
+			return err
+		}
+	}
+	if emailChanged {
+		s.sendVerification(ctx, user)
+	}
+	return nil
+}
+
+// sendVerification mails a verification link when verification is enabled. Failures are only
+// logged: the account change has been stored and the user can ask for the link again.
+func (s *AccountService) sendVerification(ctx context.Context, user *models.User) {
+	if s.verifier == nil {
+		return
+	}
+	if err := s.verifier.Send(ctx, user); err != nil {
+		log.Printf("sending verification e-mail to user %d: %v", user.ID, err)
+	}
+}
+
+// DeleteSelf deletes the user's own account after checking the password.
+func (s *AccountService) DeleteSelf(ctx context.Context, userID uint, password string) error {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkPassword(user, password); err != nil {
+		return err
+	}
+	return s.Delete(ctx, user)
+}
+
+// Delete ends all sessions of the user, removes the user's data through the cleaners and the
+// failed logins counted for the address, and finally the account itself. The cleaners keep
+// their own stores and cannot share a transaction, so the account row goes last: if a cleaner
+// fails, the account and whatever data is left stay in place and deleting again finishes the job.
+func (s *AccountService) Delete(ctx context.Context, user *models.User) error {
+	if err := s.auth.Logout(ctx, user.ID, ""); err != nil {
+		return err
+	}
+	for _, c := range s.cleaners {
+		if err := c.DeleteByUser(ctx, user.ID); err != nil {
+			return err
+		}
+	}
+	if err := s.auth.forgetLoginFailures(ctx, user.Email); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, user.ID)
+}
@@ -0,0 +1,173 @@
+package use_case_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/auth"
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/repository/memrepository"
+	"github.com/VibeTeam/fitness-tracker-backend/user/use_case"
+)
+
+func TestPasswordChangeEndsOtherSessions(t *testing.T) {
+	repo := newRepo()
+	tm := auth.NewManager("a", "b", time.Minute, time.Hour)
+	authSvc := use_case.NewAuthService(repo, &inMemRefreshRepo{}, &inMemSessionRepo{}, tm)
+	accounts := use_case.NewAccountService(repo, authSvc, nil)
+	ctx := context.Background()
+
+	_, _, err := authSvc.Register(ctx, "pw@b.c", "old")
+	require.NoError(t, err)
+	laptop, err := authSvc.Login(ctx, "pw@b.c", "old", use_case.Device{Name: "laptop"})
+	require.NoError(t, err)
+
+	newPassword := "new"
+	_, err = accounts.UpdateProfile(ctx, 1, use_case.ProfileUpdate{NewPassword: &newPassword, CurrentPassword: "bad", SessionID: 1})
+	require.ErrorIs(t, err, use_case.ErrWrongPassword)
+
+	_, err = accounts.UpdateProfile(ctx, 1, use_case.ProfileUpdate{NewPassword: &newPassword, CurrentPassword: "old", SessionID: 1})
+	require.NoError(t, err)
+
+	sessions, err := authSvc.Sessions(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, uint(1), sessions[0].ID)
+	_, _, err = authSvc.Refresh(ctx, laptop.RefreshToken)
+	require.ErrorIs(t, err, use_case.ErrInvalidRefreshToken)
+
+	_, err = authSvc.Login(ctx, "pw@b.c", "new", use_case.Device{})
+	require.NoError(t, err)
+}
+
+func TestEmailChangeMailsNewVerificationLink(t *testing.T) {
+	var mail bytes.Buffer
+	authSvc, verifier, repo, _ := newVerifyingAuth(use_case.VerificationRequired, &mail)
+	accounts := use_case.NewAccountService(repo, authSvc, verifier)
+	ctx := context.Background()
+
+	user, err := accounts.SignUp(ctx, "Old", "old@b.c", "pwd")
+	require.NoError(t, err)
+	_, err = verifier.Verify(ctx, lastVerifyToken(t, &mail))
+	require.NoError(t, err)
+
+	mail.Reset()
+	email := "new@b.c"
+	user, err = accounts.UpdateProfile(ctx, user.ID, use_case.ProfileUpdate{Email: &email, CurrentPassword: "pwd"})
+	require.NoError(t, err)
+	require.False(t, user.EmailVerified())
+	require.True(t, strings.Contains(mail.String(), "new@b.c"), "link mailed to the new address")
+
+	_, err = verifier.Verify(ctx, lastVerifyToken(t, &mail))
+	require.NoError(t, err)
+	stored, _ := repo.GetByEmail(ctx, "new@b.c")
+	require.True(t, stored.EmailVerified())
+}
+
+func TestAdminUpdateNeedsVerificationAndEndsSessions(t *testing.T) {
+	var mail bytes.Buffer
+	authSvc, verifier, repo, _ := newVerifyingAuth(use_case.VerificationRequired, &mail)
+	accounts := use_case.NewAccountService(repo, authSvc, verifier)
+	ctx := context.Background()
+
+	user, err := accounts.SignUp(ctx, "Old", "old@b.c", "pwd")
+	require.NoError(t, err)
+	_, err = verifier.Verify(ctx, lastVerifyToken(t, &mail))
+	require.NoError(t, err)
+	_, err = accounts.SignUp(ctx, "Other", "taken@b.c", "pwd")
+	require.NoError(t, err)
+	user, _ = repo.GetByID(ctx, user.ID)
+	session, err := authSvc.Login(ctx, "old@b.c", "pwd", use_case.Device{Name: "laptop"})
+	require.NoError(t, err)
+
+	taken := "taken@b.c"
+	require.ErrorIs(t, accounts.AdminUpdate(ctx, user, use_case.AdminUpdate{Email: &taken}), use_case.ErrEmailAlreadyUsed)
+
+	mail.Reset()
+	email, password := "new@b.c", "reset"
+	require.NoError(t, accounts.AdminUpdate(ctx, user, use_case.AdminUpdate{Email: &email, Password: &password}))
+	stored, _ := repo.GetByID(ctx, user.ID)
+	require.Equal(t, "new@b.c", stored.Email)
+	require.False(t, stored.EmailVerified(), "an address set by an admin must be verified too")
+	require.True(t, strings.Contains(mail.String(), "new@b.c"), "link mailed to the new address")
+
+	_, _, err = authSvc.Refresh(ctx, session.RefreshToken)
+	require.ErrorIs(t, err, use_case.ErrInvalidRefreshToken)
+}
+
+// flakyCleaner fails its first call, like a database hiccup halfway through a deletion.
+type flakyCleaner struct{ calls int }
+
+func (c *flakyCleaner) DeleteByUser(context.Context, uint) error {
+	c.calls++
+	if c.calls == 1 {
+		return errors.New("connection reset")
+	}
+	return nil
+}
+
+func TestDeleteCanBeRetriedAfterACleanerFails(t *testing.T) {
+	repo := newRepo()
+	tm := auth.NewManager("a", "b", time.Minute, time.Hour)
+	authSvc := use_case.NewAuthService(repo, &inMemRefreshRepo{}, &inMemSessionRepo{}, tm)
+	flaky := &flakyCleaner{}
+	accounts := use_case.NewAccountService(repo, authSvc, nil, flaky)
+	ctx := context.Background()
+
+	user, err := accounts.SignUp(ctx, "Del", "del@b.c", "pwd")
+	require.NoError(t, err)
+
+	require.Error(t, accounts.DeleteSelf(ctx, user.ID, "pwd"))
+	_, err = repo.GetByID(ctx, user.ID)
+	require.NoError(t, err, "the account outlives a failed cleanup so it can be retried")
+
+	require.NoError(t, accounts.DeleteSelf(ctx, user.ID, "pwd"))
+	_, err = repo.GetByID(ctx, user.ID)
+	require.Error(t, err)
+	require.Equal(t, 2, flaky.calls)
+}
+
+func TestDeleteRemovesLoginData(t *testing.T) {
+	repo := newRepo()
+	refresh, sessions := &inMemRefreshRepo{}, &inMemSessionRepo{}
+	resets, verifications := &inMemResetRepo{}, &inMemVerificationRepo{}
+	throttles := memrepository.NewLoginThrottleRepository()
+	tm := auth.NewManager("a", "b", time.Minute, time.Hour)
+	authSvc := use_case.NewAuthService(repo, refresh, sessions, tm)
+	authSvc.SetLoginThrottle(use_case.NewLoginThrottle(throttles, testThrottle, testThrottle, &auditRecorder{}))
+	accounts := use_case.NewAccountService(repo, authSvc, nil, refresh, sessions, resets, verifications)
+	ctx := context.Background()
+
+	user, err := accounts.SignUp(ctx, "Del", "del@b.c", "pwd")
+	require.NoError(t, err)
+	_, err = authSvc.Login(ctx, "del@b.c", "pwd", use_case.Device{Name: "laptop", UserAgent: "Firefox", IP: "203.0.113.7"})
+	require.NoError(t, err)
+	_, err = authSvc.Login(ctx, "del@b.c", "guess", use_case.Device{IP: "203.0.113.7"})
+	require.ErrorIs(t, err, use_case.ErrInvalidCredentials)
+	require.NoError(t, resets.Create(ctx, &models.PasswordResetToken{UserID: user.ID, TokenHash: "reset", ExpiresAt: time.Now().Add(time.Hour)}))
+	require.NoError(t, verifications.Create(ctx, &models.EmailVerificationToken{UserID: user.ID, TokenHash: "verify", ExpiresAt: time.Now().Add(time.Hour)}))
+
+	require.NoError(t, accounts.DeleteSelf(ctx, user.ID, "pwd"))
+
+	for _, s := range sessions.sessions {
+		require.NotEqual(t, user.ID, s.UserID, "session with its address and user agent left behind")
+	}
+	for _, tok := range refresh.tokens {
+		require.NotEqual(t, user.ID, tok.UserID)
+	}
+	for _, tok := range resets.tokens {
+		require.NotEqual(t, user.ID, tok.UserID)
+	}
+	for _, tok := range verifications.tokens {
+		require.NotEqual(t, user.ID, tok.UserID)
+	}
+	throttle, err := throttles.Get(ctx, "account:del@b.c")
+	require.NoError(t, err)
+	require.Nil(t, throttle, "failed logins of the deleted address are forgotten")
+}
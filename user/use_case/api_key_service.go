@@ -0,0 +1,177 @@
+package use_case
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/middleware"
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/repository"
+)
+
+// MaxAPIKeysPerUser limits how many API keys a user can hold at once.
+const MaxAPIKeysPerUser = 20
+
+var (
+	// ErrAPIKeyNotFound is returned when an API key does not exist or belongs to another user.
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	// ErrInvalidScope is returned for unknown scopes and for keys without any scope.
+	ErrInvalidScope = errors.New("invalid scope")
+	// ErrInvalidAPIKeyExpiry is returned for expiry times that are not in the future.
+	ErrInvalidAPIKeyExpiry = errors.New("expiry must be in the future")
+	// ErrTooManyAPIKeys is returned when a user already holds MaxAPIKeysPerUser keys.
+	ErrTooManyAPIKeys = errors.New("too many API keys, delete one first")
+)
+
+// APIKeyService manages the API keys users create for integrations, and authenticates
+// requests made with them.
+type APIKeyService struct {
+	keys  repository.APIKeyRepository
+	users repository.UserRepository
+}
+
+// NewAPIKeyService wires the key and user repositories.
+func NewAPIKeyService(keys repository.APIKeyRepository, users repository.UserRepository) *APIKeyService {
+	return &APIKeyService{keys: keys, users: users}
+}
+
+// Create issues a new key with the given scopes and optional expiry. The returned key string
+// is shown to the user once and cannot be recovered later.
+func (s *APIKeyService) Create(ctx context.Context, userID uint, name string, scopes []string, expiresAt *time.Time) (*models.APIKey, string, error) {
+	scopeList, err := normalizeScopes(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", ErrInvalidAPIKeyExpiry
+	}
+	existing, err := s.keys.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(existing) >= MaxAPIKeysPerUser {
+		return nil, "", ErrTooManyAPIKeys
+	}
+
+	prefix, secret := newAPIKeyPrefix(), newSecret()
+	key := &models.APIKey{
+		UserID:     userID,
+		Name:       name,
+		Prefix:     prefix,
+		SecretHash: hashToken(secret),
+		Scopes:     scopeList,
+		ExpiresAt:  expiresAt,
+	}
+	if err := s.keys.Create(ctx, key); err != nil {
+		return nil, "", err
+	}
+	return key, prefix + "_" + secret, nil
+}
+
+// List returns the keys of a user, newest first.
+func (s *APIKeyService) List(ctx context.Context, userID uint) ([]*models.APIKey, error) {
+	return s.keys.ListByUser(ctx, userID)
+}
+
+// Get returns one key of a user.
+func (s *APIKeyService) Get(ctx context.Context, userID, id uint) (*models.APIKey, error) {
+	key, err := s.keys.GetByID(ctx, id)
+	if err != nil || key.UserID != userID {
+		return nil, ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+// Update renames a key and replaces its scopes; an empty name or nil scopes keep the current value.
+func (s *APIKeyService) Update(ctx context.Context, userID, id uint, name string, scopes []string) (*models.APIKey, error) {
+	key, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if name != "" {
+		key.Name = name
+	}
+	if scopes != nil {
+		if key.Scopes, err = normalizeScopes(scopes); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.keys.Update(ctx, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Delete revokes a key; requests made with it fail immediately.
+func (s *APIKeyService) Delete(ctx context.Context, userID, id uint) error {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.keys.Delete(ctx, id)
+}
+
+// Authenticate resolves a key to its user and scopes and records it as used. Role and e-mail
+// verification are read from the user on every request. It matches middleware.APIKeyCheck.
+func (s *APIKeyService) Authenticate(ctx context.Context, raw string) (middleware.APIKeyPrincipal, bool) {
+	// A key is its prefix, an underscore and the secret.
+	n := len(middleware.APIKeyPrefix) + apiKeyPrefixLen
+	if len(raw) <= n+1 || raw[n] != '_' || !strings.HasPrefix(raw, middleware.APIKeyPrefix) {
+		return middleware.APIKeyPrincipal{}, false
+	}
+	key, err := s.keys.GetByPrefix(ctx, raw[:n])
+	now := time.Now()
+	if err != nil || key.Expired(now) || subtle.ConstantTimeCompare([]byte(hashToken(raw[n+1:])), []byte(key.SecretHash)) != 1 {
+		return middleware.APIKeyPrincipal{}, false
+	}
+	user, err := s.users.GetByID(ctx, key.UserID)
+	if err != nil {
+		return middleware.APIKeyPrincipal{}, false
+	}
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > touchInterval {
+		_ = s.keys.Touch(ctx, key.ID, now)
+	}
+	return middleware.APIKeyPrincipal{
+		UserID:        user.ID,
+		Role:          user.Role,
+		EmailVerified: user.EmailVerified(),
+		Scopes:        key.ScopeList(),
+	}, true
+}
+
+// normalizeScopes validates scopes and returns them deduplicated in the order of
+// middleware.Scopes, space-separated.
+func normalizeScopes(scopes []string) (string, error) {
+	granted := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		if !middleware.KnownScope(scope) {
+			return "", fmt.Errorf("%w %q", ErrInvalidScope, scope)
+		}
+		granted[scope] = true
+	}
+	if len(granted) == 0 {
+		return "", fmt.Errorf("%w: at least one scope is required", ErrInvalidScope)
+	}
+	var list []string
+	for _, scope := range middleware.Scopes {
+		if granted[scope] {
+			list = append(list, scope)
+		}
+	}
+	return strings.Join(list, " "), nil
+}
+
+// apiKeyPrefixLen is the number of hex characters identifying a key after middleware.APIKeyPrefix.
+const apiKeyPrefixLen = 12
+
+// newAPIKeyPrefix returns the public part of a new key, e.g. "ftk_3f9c0a7d12be".
+func newAPIKeyPrefix() string {
+	b := make([]byte, apiKeyPrefixLen/2)
+	_, _ = rand.Read(b)
+	return middleware.APIKeyPrefix + hex.EncodeToString(b)
+}
@@ -0,0 +1,185 @@
+package use_case_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/middleware"
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/use_case"
+)
+
+/* -------------------------------------------------------------------------- */
+/* In‑memory APIKeyRepository stub                                            */
+/* -------------------------------------------------------------------------- */
+
+type inMemAPIKeyRepo struct {
+	mu   sync.Mutex
+	keys map[uint]*models.APIKey
+	next uint
+}
+
+func newAPIKeyRepo() *inMemAPIKeyRepo {
+	return &inMemAPIKeyRepo{keys: map[uint]*models.APIKey{}}
+}
+
+func (r *inMemAPIKeyRepo) Create(_ context.Context, k *models.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.next++
+	k.ID = r.next
+	cp := *k
+	r.keys[k.ID] = &cp
+	return nil
+}
+
+func (r *inMemAPIKeyRepo) GetByID(_ context.Context, id uint) (*models.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	k, ok := r.keys[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	cp := *k
+	return &cp, nil
+}
+
+func (r *inMemAPIKeyRepo) GetByPrefix(_ context.Context, prefix string) (*models.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, k := range r.keys {
+		if k.Prefix == prefix {
+			cp := *k
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *inMemAPIKeyRepo) ListByUser(_ context.Context, userID uint) ([]*models.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*models.APIKey
+	for _, k := range r.keys {
+		if k.UserID == userID {
+			cp := *k
+			out = append(out, &cp)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID > out[j].ID })
+	return out, nil
+}
+
+func (r *inMemAPIKeyRepo) Update(_ context.Context, k *models.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *k
+	r.keys[k.ID] = &cp
+	return nil
+}
+
+func (r *inMemAPIKeyRepo) Delete(_ context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.keys, id)
+	return nil
+}
+
+func (r *inMemAPIKeyRepo) Touch(_ context.Context, id uint, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if k, ok := r.keys[id]; ok {
+		k.LastUsedAt = &at
+	}
+	return nil
+}
+
+func (r *inMemAPIKeyRepo) DeleteByUser(_ context.Context, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, k := range r.keys {
+		if k.UserID == userID {
+			delete(r.keys, id)
+		}
+	}
+	return nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+func TestAPIKeyLifecycle(t *testing.T) {
+	users := newRepo()
+	keys := newAPIKeyRepo()
+	svc := use_case.NewAPIKeyService(keys, users)
+	ctx := context.Background()
+	owner := &models.User{Email: "owner@b.c", Role: models.RoleUser}
+	require.NoError(t, users.Create(ctx, owner))
+
+	_, _, err := svc.Create(ctx, owner.ID, "gym", []string{"sessions:delete"}, nil)
+	require.ErrorIs(t, err, use_case.ErrInvalidScope)
+	_, _, err = svc.Create(ctx, owner.ID, "gym", nil, nil)
+	require.ErrorIs(t, err, use_case.ErrInvalidScope)
+	past := time.Now().Add(-time.Hour)
+	_, _, err = svc.Create(ctx, owner.ID, "gym", []string{middleware.ScopeSessionsRead}, &past)
+	require.ErrorIs(t, err, use_case.ErrInvalidAPIKeyExpiry)
+
+	key, raw, err := svc.Create(ctx, owner.ID, "gym", []string{middleware.ScopeSessionsWrite, middleware.ScopeSessionsRead, middleware.ScopeSessionsWrite}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "sessions:read sessions:write", key.Scopes)
+	require.Regexp(t, `^ftk_[0-9a-f]{12}_`, raw)
+	require.NotContains(t, key.SecretHash, raw[len(key.Prefix)+1:])
+
+	principal, ok := svc.Authenticate(ctx, raw)
+	require.True(t, ok)
+	require.Equal(t, middleware.APIKeyPrincipal{UserID: owner.ID, Role: models.RoleUser, Scopes: []string{"sessions:read", "sessions:write"}}, principal)
+	stored, err := svc.Get(ctx, owner.ID, key.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.LastUsedAt)
+
+	for _, bad := range []string{"", "ftk_", raw[:len(raw)-1] + "x", key.Prefix + "_", "ftk_000000000000_" + raw[len(key.Prefix)+1:]} {
+		_, ok := svc.Authenticate(ctx, bad)
+		require.False(t, ok, bad)
+	}
+
+	// Other users can neither see nor change the key.
+	_, err = svc.Get(ctx, owner.ID+1, key.ID)
+	require.ErrorIs(t, err, use_case.ErrAPIKeyNotFound)
+	require.ErrorIs(t, svc.Delete(ctx, owner.ID+1, key.ID), use_case.ErrAPIKeyNotFound)
+
+	updated, err := svc.Update(ctx, owner.ID, key.ID, "", []string{middleware.ScopeCatalogRead})
+	require.NoError(t, err)
+	require.Equal(t, "gym", updated.Name)
+	principal, ok = svc.Authenticate(ctx, raw)
+	require.True(t, ok)
+	require.Equal(t, []string{middleware.ScopeCatalogRead}, principal.Scopes)
+
+	require.NoError(t, svc.Delete(ctx, owner.ID, key.ID))
+	_, ok = svc.Authenticate(ctx, raw)
+	require.False(t, ok)
+}
+
+func TestAPIKeyExpiry(t *testing.T) {
+	users := newRepo()
+	keys := newAPIKeyRepo()
+	svc := use_case.NewAPIKeyService(keys, users)
+	ctx := context.Background()
+	owner := &models.User{Email: "owner@b.c"}
+	require.NoError(t, users.Create(ctx, owner))
+
+	soon := time.Now().Add(time.Hour)
+	key, raw, err := svc.Create(ctx, owner.ID, "script", []string{middleware.ScopeSessionsRead}, &soon)
+	require.NoError(t, err)
+	_, ok := svc.Authenticate(ctx, raw)
+	require.True(t, ok)
+
+	past := time.Now().Add(-time.Second)
+	keys.keys[key.ID].ExpiresAt = &past
+	_, ok = svc.Authenticate(ctx, raw)
+	require.False(t, ok)
+}
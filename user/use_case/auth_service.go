@@ -2,10 +2,15 @@ package use_case
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/VibeTeam/fitness-tracker-backend/user/audit"
 	"github.com/VibeTeam/fitness-tracker-backend/user/auth"
 	"github.com/VibeTeam/fitness-tracker-backend/user/models"
 	"github.com/VibeTeam/fitness-tracker-backend/user/repository"
@@ -13,23 +18,61 @@ import (
 
 // AuthService provides high-level authentication workflows (register/login/token handling).
 type AuthService struct {
-	repo        repository.UserRepository
-	tokenManger *auth.Manager
+	repo          repository.UserRepository
+	refreshTokens repository.RefreshTokenRepository
+	sessions      repository.LoginSessionRepository
+	tokenManger   *auth.Manager
+
+	verifier *EmailVerificationService
+	policy   VerificationPolicy
+	throttle *LoginThrottle
+}
+
+// NewAuthService wires repositories and token manager into a ready-to-use AuthService.
+func NewAuthService(repo repository.UserRepository, refreshTokens repository.RefreshTokenRepository, sessions repository.LoginSessionRepository, tokenMgr *auth.Manager) *AuthService {
+	return &AuthService{repo: repo, refreshTokens: refreshTokens, sessions: sessions, tokenManger: tokenMgr}
 }
 
-// NewAuthService wires repository and token manager into a ready-to-use AuthService.
-func NewAuthService(repo repository.UserRepository, tokenMgr *auth.Manager) *AuthService {
-	return &AuthService{repo: repo, tokenManger: tokenMgr}
+// SetEmailVerification applies policy to registrations and logins of unverified users.
+// Without it, e-mail addresses are never verified.
+func (s *AuthService) SetEmailVerification(verifier *EmailVerificationService, policy VerificationPolicy) {
+	s.verifier = verifier
+	s.policy = policy
 }
 
+// SetLoginThrottle makes Login and MFA verification count failures and refuse attempts with
+// a *TooManyAttemptsError while the account or client address is locked out.
+func (s *AuthService) SetLoginThrottle(throttle *LoginThrottle) {
+	s.throttle = throttle
+}
+
+// Device describes the client a login comes from.
+type Device struct {
+	Name      string
+	UserAgent string
+	IP        string
+}
+
+// touchInterval throttles LastUsedAt updates of a login session to one write per interval.
+const touchInterval = time.Minute
+
 var (
 	// ErrEmailAlreadyUsed is returned when attempting to register with an existing e-mail.
 	ErrEmailAlreadyUsed = errors.New("email is already taken")
 	// ErrInvalidCredentials is returned when login credentials do not match.
 	ErrInvalidCredentials = errors.New("invalid email or password")
+	// ErrInvalidRefreshToken is returned for unknown, expired or revoked refresh tokens.
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	// ErrRefreshTokenReused is returned when an already rotated refresh token is presented again.
+	// The whole token family is revoked, so the user has to log in again.
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected, please log in again")
+	// ErrSessionNotFound is returned when a login session does not exist or belongs to another user.
+	ErrSessionNotFound = errors.New("session not found")
 )
 
-// Register creates a new user and immediately returns freshly minted JWT pair.
+// Register creates a new user and immediately returns freshly minted JWT pair. Under
+// VerificationRequired the account is created but ErrEmailNotVerified is returned instead of
+// tokens. It mails nothing; AccountService.SignUp is the sign-up path that sends the link.
 func (s *AuthService) Register(ctx context.Context, email, password string) (accessToken, refreshToken string, err error) {
 	if _, err := s.repo.GetByEmail(ctx, email); err == nil {
 		return "", "", ErrEmailAlreadyUsed
@@ -43,34 +86,199 @@ func (s *AuthService) Register(ctx context.Context, email, password string) (acc
 	user := &models.User{
 		Email:        email,
 		PasswordHash: string(hash),
+		Role:         models.RoleUser,
 	}
 	if err := s.repo.Create(ctx, user); err != nil {
 		return "", "", err
 	}
+	if s.policy == VerificationRequired {
+		return "", "", ErrEmailNotVerified
+	}
+
+	return s.startSession(ctx, user, Device{})
+}
 
-	accessToken, refreshToken, err = s.tokenManger.NewTokens(int32(user.ID))
-	return
+// LoginResult is the outcome of a successful password check: either a JWT pair or, for users
+// with two-factor authentication, only MFAToken, which MFAService.Verify exchanges for the pair.
+type LoginResult struct {
+	AccessToken  string
+	RefreshToken string
+	MFAToken     string
+}
+
+// MFARequired reports whether the login still needs a second factor.
+func (r *LoginResult) MFARequired() bool {
+	return r.MFAToken != ""
 }
 
 // Login verifies the supplied credentials and returns a new JWT pair upon success.
-func (s *AuthService) Login(ctx context.Context, email, password string) (accessToken, refreshToken string, err error) {
+// Each login starts a new session for the given device.
+func (s *AuthService) Login(ctx context.Context, email, password string, device Device) (*LoginResult, error) {
+	if err := s.allowLogin(ctx, email, device.IP); err != nil {
+		return nil, err
+	}
 	user, err := s.repo.GetByEmail(ctx, email)
 	if err != nil {
-		return "", "", ErrInvalidCredentials
+		s.loginFailed(ctx, audit.LoginFailed, 0, email, device.IP)
+		return nil, ErrInvalidCredentials
 	}
 
 	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
-		return "", "", ErrInvalidCredentials
+		s.loginFailed(ctx, audit.LoginFailed, user.ID, email, device.IP)
+		return nil, ErrInvalidCredentials
+	}
+	return s.completeLogin(ctx, user, device)
+}
+
+// allowLogin checks the login throttle, if there is one.
+func (s *AuthService) allowLogin(ctx context.Context, email, ip string) error {
+	if s.throttle == nil {
+		return nil
 	}
+	return s.throttle.Allow(ctx, email, ip)
+}
 
-	accessToken, refreshToken, err = s.tokenManger.NewTokens(int32(user.ID))
-	return
+// forgetLoginFailures drops the failed logins counted for an account, if there is a login throttle.
+func (s *AuthService) forgetLoginFailures(ctx context.Context, email string) error {
+	if s.throttle == nil {
+		return nil
+	}
+	return s.throttle.Forget(ctx, email)
 }
 
-// Refresh validates the provided refresh token and issues a fresh access/refresh pair.
+// loginFailed records a failed attempt with the login throttle, if there is one.
+func (s *AuthService) loginFailed(ctx context.Context, eventType string, userID uint, email, ip string) {
+	if s.throttle != nil {
+		s.throttle.Failure(ctx, eventType, userID, email, ip)
+	}
+}
+
+// completeLogin applies the checks that follow a successful first factor: the e-mail
+// verification policy and two-factor authentication.
+func (s *AuthService) completeLogin(ctx context.Context, user *models.User, device Device) (*LoginResult, error) {
+	if s.policy == VerificationRequired && !user.EmailVerified() {
+		return nil, ErrEmailNotVerified
+	}
+	if user.MFAEnabled() {
+		challenge, err := s.tokenManger.NewMFAToken(int32(user.ID), device.Name)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{MFAToken: challenge}, nil
+	}
+
+	access, refresh, err := s.startSession(ctx, user, device)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{AccessToken: access, RefreshToken: refresh}, nil
+}
+
+// Refresh rotates the provided refresh token: it is marked used and a fresh pair of the same
+// family is issued. Presenting a used token again revokes the whole family.
 func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (newAccessToken, newRefreshToken string, err error) {
-	// Business logic resides in token manager; just proxy.
-	return s.tokenManger.RefreshTokens(refreshToken)
+	if _, err := s.tokenManger.ParseRefreshToken(refreshToken); err != nil {
+		return "", "", ErrInvalidRefreshToken
+	}
+	stored, err := s.refreshTokens.GetByHash(ctx, hashToken(refreshToken))
+	if err != nil || stored.RevokedAt != nil {
+		return "", "", ErrInvalidRefreshToken
+	}
+
+	session, err := s.sessions.GetByFamily(ctx, stored.FamilyID)
+	if err != nil || session.RevokedAt != nil {
+		return "", "", ErrInvalidRefreshToken
+	}
+
+	now := time.Now()
+	fresh := stored.UsedAt == nil
+	if fresh {
+		if fresh, err = s.refreshTokens.MarkUsed(ctx, stored.ID, now); err != nil {
+			return "", "", err
+		}
+	}
+	if !fresh {
+		if err := s.revokeSession(ctx, session, now); err != nil {
+			return "", "", err
+		}
+		return "", "", ErrRefreshTokenReused
+	}
+	// Reload the user so role and verification changes take effect and deleted accounts cannot refresh.
+	user, err := s.repo.GetByID(ctx, session.UserID)
+	if err != nil {
+		return "", "", ErrInvalidRefreshToken
+	}
+	if err := s.sessions.Touch(ctx, session.ID, now); err != nil {
+		return "", "", err
+	}
+	return s.issue(ctx, session, user)
+}
+
+// Logout ends the session of the given refresh token. An empty token ends every session of
+// the user, logging out all devices. Tokens of other users are ignored.
+func (s *AuthService) Logout(ctx context.Context, userID uint, refreshToken string) error {
+	now := time.Now()
+	if refreshToken == "" {
+		if err := s.refreshTokens.RevokeByUser(ctx, userID, now); err != nil {
+			return err
+		}
+		return s.sessions.RevokeByUser(ctx, userID, now)
+	}
+	stored, err := s.refreshTokens.GetByHash(ctx, hashToken(refreshToken))
+	if err != nil || stored.UserID != userID {
+		return nil
+	}
+	session, err := s.sessions.GetByFamily(ctx, stored.FamilyID)
+	if err != nil {
+		return s.refreshTokens.RevokeFamily(ctx, stored.FamilyID, now)
+	}
+	return s.revokeSession(ctx, session, now)
+}
+
+// RevokeOtherSessions ends every session of the user except keepSessionID, e.g. after a password change.
+func (s *AuthService) RevokeOtherSessions(ctx context.Context, userID, keepSessionID uint) error {
+	sessions, err := s.Sessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, session := range sessions {
+		if session.ID == keepSessionID {
+			continue
+		}
+		if err := s.revokeSession(ctx, session, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sessions lists the active login sessions of a user, most recently used first.
+func (s *AuthService) Sessions(ctx context.Context, userID uint) ([]*models.LoginSession, error) {
+	return s.sessions.ListActiveByUser(ctx, userID, time.Now().Add(-s.tokenManger.RefreshTTL()))
+}
+
+// RevokeSession signs a device out: its refresh tokens stop working immediately and, when the
+// Auth middleware checks sessions, so do its access tokens.
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID uint) error {
+	session, err := s.sessions.GetByID(ctx, sessionID)
+	if err != nil || session.UserID != userID {
+		return ErrSessionNotFound
+	}
+	return s.revokeSession(ctx, session, time.Now())
+}
+
+// SessionActive reports whether a login session exists, belongs to the user and has not been
+// revoked. It also records the session as used. It matches middleware.SessionCheck.
+func (s *AuthService) SessionActive(ctx context.Context, userID, sessionID uint) bool {
+	session, err := s.sessions.GetByID(ctx, sessionID)
+	if err != nil || session.UserID != userID || session.RevokedAt != nil {
+		return false
+	}
+	if now := time.Now(); now.Sub(session.LastUsedAt) > touchInterval {
+		_ = s.sessions.Touch(ctx, session.ID, now)
+	}
+	return true
 }
 
 // Validate parses the access token and returns the user ID if it is valid.
@@ -81,3 +289,65 @@ func (s *AuthService) Validate(ctx context.Context, accessToken string) (userID
 	}
 	return uint(id), nil
 }
+
+// startSession records a new login session and issues its first token pair. A session only
+// starts once every factor is checked, so it also clears the account's failed logins.
+func (s *AuthService) startSession(ctx context.Context, user *models.User, device Device) (accessToken, refreshToken string, err error) {
+	if s.throttle != nil {
+		s.throttle.Success(ctx, user.Email)
+	}
+	session := &models.LoginSession{
+		UserID:     user.ID,
+		FamilyID:   newFamilyID(),
+		DeviceName: device.Name,
+		UserAgent:  device.UserAgent,
+		IP:         device.IP,
+		LastUsedAt: time.Now(),
+	}
+	if err := s.sessions.Create(ctx, session); err != nil {
+		return "", "", err
+	}
+	return s.issue(ctx, session, user)
+}
+
+// revokeSession revokes a login session together with its refresh token family.
+func (s *AuthService) revokeSession(ctx context.Context, session *models.LoginSession, at time.Time) error {
+	if err := s.refreshTokens.RevokeFamily(ctx, session.FamilyID, at); err != nil {
+		return err
+	}
+	return s.sessions.Revoke(ctx, session.ID, at)
+}
+
+// issue mints a token pair bound to the session and records the refresh token under its family.
+func (s *AuthService) issue(ctx context.Context, session *models.LoginSession, user *models.User) (accessToken, refreshToken string, err error) {
+	accessToken, refreshToken, err = s.tokenManger.NewTokensFor(auth.Subject{
+		UserID:        int32(user.ID),
+		SessionID:     session.ID,
+		Role:          user.Role,
+		EmailVerified: user.EmailVerified(),
+	})
+	if err != nil {
+		return "", "", err
+	}
+	err = s.refreshTokens.Create(ctx, &models.RefreshToken{
+		UserID:    session.UserID,
+		FamilyID:  session.FamilyID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(s.tokenManger.RefreshTTL()),
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return accessToken, refreshToken, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newFamilyID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
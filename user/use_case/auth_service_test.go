@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
 	"github.com/VibeTeam/fitness-tracker-backend/user/auth"
 	"github.com/VibeTeam/fitness-tracker-backend/user/models"
 	"github.com/VibeTeam/fitness-tracker-backend/user/use_case"
@@ -62,7 +63,7 @@ func (r *inMemUserRepo) GetByID(_ context.Context, id uint) (*models.User, error
 	return nil, errors.New("not found")
 }
 
-func (r *inMemUserRepo) List(_ context.Context, _, _ int) ([]*models.User, error) {
+func (r *inMemUserRepo) List(_ context.Context, _ int, _ *pagination.Cursor) ([]*models.User, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	out := make([]*models.User, 0, len(r.store))
@@ -94,12 +95,188 @@ func (r *inMemUserRepo) Count(_ context.Context) (int, error) {
 	return len(r.store), nil
 }
 
+func (r *inMemUserRepo) AdvanceTOTPStep(_ context.Context, id uint, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.store[id]
+	if !ok || u.TOTPLastStep >= step {
+		return false, nil
+	}
+	u.TOTPLastStep = step
+	return true, nil
+}
+
+/* -------------------------------------------------------------------------- */
+/* In‑memory RefreshTokenRepository stub                                      */
+/* -------------------------------------------------------------------------- */
+
+type inMemRefreshRepo struct {
+	mu     sync.Mutex
+	tokens []*models.RefreshToken
+}
+
+func (r *inMemRefreshRepo) Create(_ context.Context, t *models.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t.ID = uint(len(r.tokens) + 1)
+	cp := *t
+	r.tokens = append(r.tokens, &cp)
+	return nil
+}
+
+func (r *inMemRefreshRepo) GetByHash(_ context.Context, hash string) (*models.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.TokenHash == hash {
+			cp := *t
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *inMemRefreshRepo) MarkUsed(_ context.Context, id uint, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t := r.tokens[id-1]
+	if t.UsedAt != nil {
+		return false, nil
+	}
+	t.UsedAt = &at
+	return true, nil
+}
+
+func (r *inMemRefreshRepo) RevokeFamily(_ context.Context, familyID string, at time.Time) error {
+	return r.revoke(func(t *models.RefreshToken) bool { return t.FamilyID == familyID }, at)
+}
+
+func (r *inMemRefreshRepo) RevokeByUser(_ context.Context, userID uint, at time.Time) error {
+	return r.revoke(func(t *models.RefreshToken) bool { return t.UserID == userID }, at)
+}
+
+// DeleteByUser blanks the rows of a user instead of removing them, as IDs index the slice.
+func (r *inMemRefreshRepo) DeleteByUser(_ context.Context, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, t := range r.tokens {
+		if t.UserID == userID {
+			r.tokens[i] = &models.RefreshToken{ID: t.ID}
+		}
+	}
+	return nil
+}
+
+func (r *inMemRefreshRepo) revoke(match func(*models.RefreshToken) bool, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if match(t) && t.RevokedAt == nil {
+			t.RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+/* -------------------------------------------------------------------------- */
+/* In‑memory LoginSessionRepository stub                                      */
+/* -------------------------------------------------------------------------- */
+
+type inMemSessionRepo struct {
+	mu       sync.Mutex
+	sessions []*models.LoginSession
+}
+
+func (r *inMemSessionRepo) Create(_ context.Context, s *models.LoginSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s.ID = uint(len(r.sessions) + 1)
+	s.CreatedAt = time.Now()
+	cp := *s
+	r.sessions = append(r.sessions, &cp)
+	return nil
+}
+
+func (r *inMemSessionRepo) GetByID(_ context.Context, id uint) (*models.LoginSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id == 0 || int(id) > len(r.sessions) {
+		return nil, errors.New("not found")
+	}
+	cp := *r.sessions[id-1]
+	return &cp, nil
+}
+
+func (r *inMemSessionRepo) GetByFamily(_ context.Context, familyID string) (*models.LoginSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.sessions {
+		if s.FamilyID == familyID {
+			cp := *s
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *inMemSessionRepo) ListActiveByUser(_ context.Context, userID uint, since time.Time) ([]*models.LoginSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*models.LoginSession
+	for _, s := range r.sessions {
+		if s.UserID == userID && s.RevokedAt == nil && s.LastUsedAt.After(since) {
+			cp := *s
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
+
+func (r *inMemSessionRepo) Touch(_ context.Context, id uint, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions[id-1].LastUsedAt = at
+	return nil
+}
+
+func (r *inMemSessionRepo) Revoke(_ context.Context, id uint, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.sessions[id-1].RevokedAt == nil {
+		r.sessions[id-1].RevokedAt = &at
+	}
+	return nil
+}
+
+func (r *inMemSessionRepo) RevokeByUser(_ context.Context, userID uint, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.sessions {
+		if s.UserID == userID && s.RevokedAt == nil {
+			s.RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+// DeleteByUser blanks the rows of a user instead of removing them, as IDs index the slice.
+func (r *inMemSessionRepo) DeleteByUser(_ context.Context, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, s := range r.sessions {
+		if s.UserID == userID {
+			r.sessions[i] = &models.LoginSession{ID: s.ID}
+		}
+	}
+	return nil
+}
+
 /* -------------------------------------------------------------------------- */
 
 func makeService() *use_case.AuthService {
 	repo := newRepo()
 	tm := auth.NewManager("access‑key", "refresh‑key", time.Minute, time.Hour)
-	return use_case.NewAuthService(repo, tm)
+	return use_case.NewAuthService(repo, &inMemRefreshRepo{}, &inMemSessionRepo{}, tm)
 }
 
 /* -------------------------------------------------------------------------- */
@@ -117,10 +294,11 @@ func TestRegisterAndLogin(t *testing.T) {
 	require.NotEmpty(t, refresh)
 
 	// Login with same credentials – should succeed and return tokens
-	access2, refresh2, err := svc.Login(ctx, "x@y.com", "pwd")
+	res, err := svc.Login(ctx, "x@y.com", "pwd", use_case.Device{})
 	require.NoError(t, err)
-	require.NotEmpty(t, access2)
-	require.NotEmpty(t, refresh2)
+	require.False(t, res.MFARequired())
+	require.NotEmpty(t, res.AccessToken)
+	require.NotEmpty(t, res.RefreshToken)
 }
 
 func TestRefreshAndValidate(t *testing.T) {
@@ -141,10 +319,114 @@ func TestRefreshAndValidate(t *testing.T) {
 	require.Equal(t, uint(1), uid)
 }
 
+func TestRefreshRotation(t *testing.T) {
+	svc := makeService()
+	ctx := context.Background()
+
+	_, first, err := svc.Register(ctx, "rot@b.c", "pwd")
+	require.NoError(t, err)
+
+	_, second, err := svc.Refresh(ctx, first)
+	require.NoError(t, err)
+	require.NotEqual(t, first, second)
+
+	// Replaying the rotated token revokes the family, including the token issued from it.
+	_, _, err = svc.Refresh(ctx, first)
+	require.ErrorIs(t, err, use_case.ErrRefreshTokenReused)
+	_, _, err = svc.Refresh(ctx, second)
+	require.ErrorIs(t, err, use_case.ErrInvalidRefreshToken)
+
+	// A separate login is a separate family and is unaffected.
+	other, err := svc.Login(ctx, "rot@b.c", "pwd", use_case.Device{})
+	require.NoError(t, err)
+	_, _, err = svc.Refresh(ctx, other.RefreshToken)
+	require.NoError(t, err)
+
+	_, _, err = svc.Refresh(ctx, "not-a-token")
+	require.ErrorIs(t, err, use_case.ErrInvalidRefreshToken)
+}
+
+func TestLogoutRevokesRefreshTokens(t *testing.T) {
+	svc := makeService()
+	ctx := context.Background()
+
+	_, phone, err := svc.Register(ctx, "out@b.c", "pwd")
+	require.NoError(t, err)
+	res, err := svc.Login(ctx, "out@b.c", "pwd", use_case.Device{})
+	require.NoError(t, err)
+	laptop := res.RefreshToken
+
+	// Another user cannot revoke the token.
+	require.NoError(t, svc.Logout(ctx, 99, phone))
+
+	require.NoError(t, svc.Logout(ctx, 1, phone))
+	_, _, err = svc.Refresh(ctx, phone)
+	require.ErrorIs(t, err, use_case.ErrInvalidRefreshToken)
+	_, laptop, err = svc.Refresh(ctx, laptop)
+	require.NoError(t, err)
+
+	// Without a token every login ends.
+	require.NoError(t, svc.Logout(ctx, 1, ""))
+	_, _, err = svc.Refresh(ctx, laptop)
+	require.ErrorIs(t, err, use_case.ErrInvalidRefreshToken)
+}
+
+func TestSessionRevocation(t *testing.T) {
+	svc := makeService()
+	ctx := context.Background()
+
+	_, _, err := svc.Register(ctx, "dev@b.c", "pwd")
+	require.NoError(t, err)
+	res, err := svc.Login(ctx, "dev@b.c", "pwd", use_case.Device{Name: "phone", IP: "10.0.0.1"})
+	require.NoError(t, err)
+	phone := res.RefreshToken
+
+	sessions, err := svc.Sessions(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	require.Equal(t, "phone", sessions[1].DeviceName)
+	phoneID := sessions[1].ID
+	require.True(t, svc.SessionActive(ctx, 1, phoneID))
+	require.False(t, svc.SessionActive(ctx, 2, phoneID))
+
+	require.ErrorIs(t, svc.RevokeSession(ctx, 2, phoneID), use_case.ErrSessionNotFound)
+	require.NoError(t, svc.RevokeSession(ctx, 1, phoneID))
+
+	require.False(t, svc.SessionActive(ctx, 1, phoneID))
+	_, _, err = svc.Refresh(ctx, phone)
+	require.ErrorIs(t, err, use_case.ErrInvalidRefreshToken)
+	sessions, err = svc.Sessions(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+}
+
+func TestRefreshPicksUpRoleChange(t *testing.T) {
+	repo := newRepo()
+	tm := auth.NewManager("a", "b", time.Minute, time.Hour)
+	svc := use_case.NewAuthService(repo, &inMemRefreshRepo{}, &inMemSessionRepo{}, tm)
+	ctx := context.Background()
+
+	access, refresh, err := svc.Register(ctx, "role@b.c", "pwd")
+	require.NoError(t, err)
+	claims, err := tm.ParseAccessToken(access)
+	require.NoError(t, err)
+	require.Equal(t, models.RoleUser, claims.Role)
+
+	u, _ := repo.GetByEmail(ctx, "role@b.c")
+	u.Role = models.RoleAdmin
+	require.NoError(t, repo.Update(ctx, u))
+
+	access, _, err = svc.Refresh(ctx, refresh)
+	require.NoError(t, err)
+	claims, err = tm.ParseAccessToken(access)
+	require.NoError(t, err)
+	require.Equal(t, models.RoleAdmin, claims.Role)
+}
+
 func TestPasswordHashStored(t *testing.T) {
 	repo := newRepo()
 	tm := auth.NewManager("a", "b", time.Minute, time.Hour)
-	svc := use_case.NewAuthService(repo, tm)
+	svc := use_case.NewAuthService(repo, &inMemRefreshRepo{}, &inMemSessionRepo{}, tm)
 
 	_, _, err := svc.Register(context.Background(), "p@q.r", "secret")
 	require.NoError(t, err)
@@ -0,0 +1,164 @@
+package use_case
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/mailer"
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/repository"
+)
+
+const (
+	// EmailVerificationTTL is how long a mailed verification link stays valid.
+	EmailVerificationTTL = 48 * time.Hour
+	// VerificationLinkCooldown is how long after mailing a verification link further requests
+	// for the same account through Resend are ignored, so the endpoint cannot flood a mailbox.
+	VerificationLinkCooldown = time.Minute
+	// maxPendingVerifications caps the links Resend mails at once; requests beyond it are dropped.
+	maxPendingVerifications = 32
+)
+
+// VerificationPolicy decides what users with an unverified e-mail address may do.
+type VerificationPolicy string
+
+const (
+	// VerificationOff does not restrict unverified users.
+	VerificationOff VerificationPolicy = "off"
+	// VerificationRestrict lets unverified users log in; routes guarded by
+	// middleware.WithVerifiedEmail stay closed to them.
+	VerificationRestrict VerificationPolicy = "restrict"
+	// VerificationRequired refuses to log unverified users in.
+	VerificationRequired VerificationPolicy = "required"
+)
+
+// ParseVerificationPolicy maps a configuration value to a VerificationPolicy.
+func ParseVerificationPolicy(s string) (VerificationPolicy, error) {
+	switch p := VerificationPolicy(s); p {
+	case VerificationOff, VerificationRestrict, VerificationRequired:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown e-mail verification policy %q (want off, restrict or required)", s)
+}
+
+var (
+	// ErrInvalidVerificationToken is returned for unknown, expired, used or outdated verification tokens.
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+	// ErrEmailNotVerified is returned by Login under VerificationRequired for unverified users.
+	ErrEmailNotVerified = errors.New("e-mail address is not verified")
+)
+
+// EmailVerificationService mails verification links and confirms addresses.
+type EmailVerificationService struct {
+	users     repository.UserRepository
+	tokens    repository.EmailVerificationRepository
+	mailer    mailer.Mailer
+	verifyURL string
+	pending   sync.WaitGroup
+	slots     chan struct{}
+}
+
+// NewEmailVerificationService wires the verification workflow. verifyURL is the page that
+// receives the token as its "token" query parameter, e.g. the API's own /auth/verify.
+func NewEmailVerificationService(users repository.UserRepository, tokens repository.EmailVerificationRepository, m mailer.Mailer, verifyURL string) *EmailVerificationService {
+	return &EmailVerificationService{users: users, tokens: tokens, mailer: m, verifyURL: verifyURL, slots: make(chan struct{}, maxPendingVerifications)}
+}
+
+// Send mails a verification link for the user's current address and invalidates earlier links.
+func (s *EmailVerificationService) Send(ctx context.Context, user *models.User) error {
+	now := time.Now()
+	if err := s.tokens.InvalidateByUser(ctx, user.ID, now); err != nil {
+		return err
+	}
+	token := newSecret()
+	err := s.tokens.Create(ctx, &models.EmailVerificationToken{
+		UserID:    user.ID,
+		Email:     user.Email,
+		TokenHash: hashToken(token),
+		ExpiresAt: now.Add(EmailVerificationTTL),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	link := s.verifyURL + "?token=" + url.QueryEscape(token)
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Confirm your e-mail address",
+		Body: fmt.Sprintf("Welcome to Fitness Tracker!\n\n"+
+			"Open this link within %d hours to confirm your e-mail address:\n%s\n",
+			int(EmailVerificationTTL.Hours()), link),
+	})
+}
+
+// Resend mails a new link to the unverified account with the given address. Like
+// PasswordResetService.Forgot it works in the background, so neither the response nor its
+// timing reveals accounts; unknown and already verified addresses are ignored and failures
+// are only logged. Requests within VerificationLinkCooldown of the last link, and requests
+// while maxPendingVerifications links are being mailed, are dropped.
+func (s *EmailVerificationService) Resend(ctx context.Context, email string) {
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		log.Printf("verification e-mail for %s: too many pending requests, dropped", email)
+		return
+	}
+	s.pending.Add(1)
+	go func() {
+		defer func() {
+			<-s.slots
+			s.pending.Done()
+		}()
+		if err := s.resend(context.WithoutCancel(ctx), email); err != nil {
+			log.Printf("verification e-mail for %s: %v", email, err)
+		}
+	}()
+}
+
+// Wait blocks until every link requested through Resend so far has been handled.
+func (s *EmailVerificationService) Wait() {
+	s.pending.Wait()
+}
+
+// resend mails a new link to the unverified account with the given address, if there is one
+// and it was not sent a link within VerificationLinkCooldown.
+func (s *EmailVerificationService) resend(ctx context.Context, email string) error {
+	user, err := s.users.GetByEmail(ctx, email)
+	if err != nil || user.EmailVerified() {
+		return nil
+	}
+	if last, err := s.tokens.LatestByUser(ctx, user.ID); err == nil && time.Since(last.CreatedAt) < VerificationLinkCooldown {
+		return nil
+	}
+	return s.Send(ctx, user)
+}
+
+// Verify consumes a verification token and marks the address it was sent to as verified.
+func (s *EmailVerificationService) Verify(ctx context.Context, token string) (*models.User, error) {
+	stored, err := s.tokens.GetByHash(ctx, hashToken(token))
+	now := time.Now()
+	if err != nil || stored.UsedAt != nil || now.After(stored.ExpiresAt) {
+		return nil, ErrInvalidVerificationToken
+	}
+	user, err := s.users.GetByID(ctx, stored.UserID)
+	if err != nil || user.Email != stored.Email {
+		return nil, ErrInvalidVerificationToken
+	}
+	if ok, err := s.tokens.MarkUsed(ctx, stored.ID, now); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	user.EmailVerifiedAt = &now
+	if err := s.users.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
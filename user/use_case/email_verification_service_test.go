@@ -0,0 +1,230 @@
+package use_case_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/auth"
+	"github.com/VibeTeam/fitness-tracker-backend/user/mailer"
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/use_case"
+)
+
+/* -------------------------------------------------------------------------- */
+/* In‑memory EmailVerificationRepository stub                                 */
+/* -------------------------------------------------------------------------- */
+
+type inMemVerificationRepo struct {
+	mu     sync.Mutex
+	tokens []*models.EmailVerificationToken
+}
+
+func (r *inMemVerificationRepo) Create(_ context.Context, t *models.EmailVerificationToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t.ID = uint(len(r.tokens) + 1)
+	cp := *t
+	r.tokens = append(r.tokens, &cp)
+	return nil
+}
+
+func (r *inMemVerificationRepo) GetByHash(_ context.Context, hash string) (*models.EmailVerificationToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.TokenHash == hash {
+			cp := *t
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *inMemVerificationRepo) LatestByUser(_ context.Context, userID uint) (*models.EmailVerificationToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.tokens) - 1; i >= 0; i-- {
+		if r.tokens[i].UserID == userID {
+			cp := *r.tokens[i]
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *inMemVerificationRepo) MarkUsed(_ context.Context, id uint, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.tokens[id-1].UsedAt != nil {
+		return false, nil
+	}
+	r.tokens[id-1].UsedAt = &at
+	return true, nil
+}
+
+func (r *inMemVerificationRepo) InvalidateByUser(_ context.Context, userID uint, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.UsedAt == nil {
+			t.UsedAt = &at
+		}
+	}
+	return nil
+}
+
+// DeleteByUser blanks the rows of a user instead of removing them, as IDs index the slice.
+func (r *inMemVerificationRepo) DeleteByUser(_ context.Context, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, t := range r.tokens {
+		if t.UserID == userID {
+			r.tokens[i] = &models.EmailVerificationToken{ID: t.ID}
+		}
+	}
+	return nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+// resend requests a new link and waits until it has been mailed.
+func resend(verifier *use_case.EmailVerificationService, email string) {
+	verifier.Resend(context.Background(), email)
+	verifier.Wait()
+}
+
+var verifyLink = regexp.MustCompile(`https://app\.example/verify\?token=(\S+)`)
+
+// lastVerifyToken extracts the token of the most recent verification link written to the mail log.
+func lastVerifyToken(t *testing.T, mail *bytes.Buffer) string {
+	m := verifyLink.FindAllStringSubmatch(mail.String(), -1)
+	require.NotEmpty(t, m, "no verification link mailed")
+	return m[len(m)-1][1]
+}
+
+// newVerifyingAuth wires an auth service that mails verification links into mail under policy.
+func newVerifyingAuth(policy use_case.VerificationPolicy, mail *bytes.Buffer) (*use_case.AuthService, *use_case.EmailVerificationService, *inMemUserRepo, *inMemVerificationRepo) {
+	repo := newRepo()
+	tokens := &inMemVerificationRepo{}
+	tm := auth.NewManager("a", "b", time.Minute, time.Hour)
+	authSvc := use_case.NewAuthService(repo, &inMemRefreshRepo{}, &inMemSessionRepo{}, tm)
+	verifier := use_case.NewEmailVerificationService(repo, tokens, mailer.NewLog(mail, "noreply@app.example"), "https://app.example/verify")
+	authSvc.SetEmailVerification(verifier, policy)
+	return authSvc, verifier, repo, tokens
+}
+
+func TestEmailVerification(t *testing.T) {
+	var mail bytes.Buffer
+	authSvc, verifier, repo, tokens := newVerifyingAuth(use_case.VerificationRestrict, &mail)
+	tm := auth.NewManager("a", "b", time.Minute, time.Hour)
+	ctx := context.Background()
+
+	access, refresh, err := authSvc.Register(ctx, "new@b.c", "pwd")
+	require.NoError(t, err, "restrict still logs unverified users in")
+	claims, err := tm.ParseAccessToken(access)
+	require.NoError(t, err)
+	require.False(t, claims.EmailVerified)
+	require.Zero(t, mail.Len(), "Register leaves mailing the link to SignUp")
+	resend(verifier, "new@b.c")
+	first := lastVerifyToken(t, &mail)
+
+	// Resending within the cooldown mails nothing; unknown addresses are ignored silently.
+	mail.Reset()
+	resend(verifier, "nobody@b.c")
+	resend(verifier, "new@b.c")
+	require.Zero(t, mail.Len())
+
+	// After it, resending invalidates the first link.
+	tokens.tokens[0].CreatedAt = time.Now().Add(-use_case.VerificationLinkCooldown)
+	resend(verifier, "new@b.c")
+	second := lastVerifyToken(t, &mail)
+
+	_, err = verifier.Verify(ctx, first)
+	require.ErrorIs(t, err, use_case.ErrInvalidVerificationToken)
+	u, err := verifier.Verify(ctx, second)
+	require.NoError(t, err)
+	require.True(t, u.EmailVerified())
+	_, err = verifier.Verify(ctx, second)
+	require.ErrorIs(t, err, use_case.ErrInvalidVerificationToken)
+
+	stored, _ := repo.GetByEmail(ctx, "new@b.c")
+	require.True(t, stored.EmailVerified())
+
+	// Verified users get no more links, and refreshed tokens carry the new state.
+	mail.Reset()
+	resend(verifier, "new@b.c")
+	require.Zero(t, mail.Len())
+	access, _, err = authSvc.Refresh(ctx, refresh)
+	require.NoError(t, err)
+	claims, err = tm.ParseAccessToken(access)
+	require.NoError(t, err)
+	require.True(t, claims.EmailVerified)
+}
+
+func TestEmailVerificationRejectsChangedAddress(t *testing.T) {
+	var mail bytes.Buffer
+	authSvc, verifier, repo, _ := newVerifyingAuth(use_case.VerificationOff, &mail)
+	ctx := context.Background()
+
+	_, _, err := authSvc.Register(ctx, "old@b.c", "pwd")
+	require.NoError(t, err)
+	resend(verifier, "old@b.c")
+	token := lastVerifyToken(t, &mail)
+
+	u, _ := repo.GetByEmail(ctx, "old@b.c")
+	u.Email = "changed@b.c"
+	require.NoError(t, repo.Update(ctx, u))
+
+	_, err = verifier.Verify(ctx, token)
+	require.ErrorIs(t, err, use_case.ErrInvalidVerificationToken)
+}
+
+func TestEmailVerificationExpired(t *testing.T) {
+	var mail bytes.Buffer
+	authSvc, verifier, _, tokens := newVerifyingAuth(use_case.VerificationOff, &mail)
+	ctx := context.Background()
+
+	_, _, err := authSvc.Register(ctx, "late@b.c", "pwd")
+	require.NoError(t, err)
+	resend(verifier, "late@b.c")
+	tokens.tokens[0].ExpiresAt = time.Now().Add(-time.Minute)
+
+	_, err = verifier.Verify(ctx, lastVerifyToken(t, &mail))
+	require.ErrorIs(t, err, use_case.ErrInvalidVerificationToken)
+}
+
+func TestVerificationRequiredBlocksLogin(t *testing.T) {
+	var mail bytes.Buffer
+	authSvc, verifier, _, _ := newVerifyingAuth(use_case.VerificationRequired, &mail)
+	ctx := context.Background()
+
+	_, _, err := authSvc.Register(ctx, "strict@b.c", "pwd")
+	require.ErrorIs(t, err, use_case.ErrEmailNotVerified)
+	resend(verifier, "strict@b.c")
+	_, err = authSvc.Login(ctx, "strict@b.c", "pwd", use_case.Device{})
+	require.ErrorIs(t, err, use_case.ErrEmailNotVerified)
+	_, err = authSvc.Login(ctx, "strict@b.c", "wrong", use_case.Device{})
+	require.ErrorIs(t, err, use_case.ErrInvalidCredentials, "wrong passwords do not reveal the verification state")
+
+	_, err = verifier.Verify(ctx, lastVerifyToken(t, &mail))
+	require.NoError(t, err)
+	_, err = authSvc.Login(ctx, "strict@b.c", "pwd", use_case.Device{})
+	require.NoError(t, err)
+}
+
+func TestParseVerificationPolicy(t *testing.T) {
+	for _, s := range []string{"off", "restrict", "required"} {
+		p, err := use_case.ParseVerificationPolicy(s)
+		require.NoError(t, err)
+		require.Equal(t, use_case.VerificationPolicy(s), p)
+	}
+	_, err := use_case.ParseVerificationPolicy("sometimes")
+	require.Error(t, err)
+}
@@ -0,0 +1,161 @@
+package use_case
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/audit"
+	"github.com/VibeTeam/fitness-tracker-backend/user/repository"
+)
+
+// ErrTooManyAttempts is returned, wrapped in a *TooManyAttemptsError, for logins refused
+// because of a lockout.
+var ErrTooManyAttempts = errors.New("too many failed login attempts, try again later")
+
+// TooManyAttemptsError tells how long a lockout lasts.
+type TooManyAttemptsError struct {
+	RetryAfter time.Duration
+}
+
+func (e *TooManyAttemptsError) Error() string {
+	return ErrTooManyAttempts.Error()
+}
+
+// Is makes errors.Is(err, ErrTooManyAttempts) hold.
+func (e *TooManyAttemptsError) Is(target error) bool {
+	return target == ErrTooManyAttempts
+}
+
+// ThrottlePolicy decides when repeated failures lock logins out and for how long. Every
+// failure past FreeAttempts doubles the lockout, from BaseLockout up to MaxLockout.
+type ThrottlePolicy struct {
+	FreeAttempts int
+	BaseLockout  time.Duration
+	MaxLockout   time.Duration
+	// Window is how long a failure is remembered; a success also clears the account's failures.
+	Window time.Duration
+}
+
+var (
+	// DefaultAccountThrottle applies to the e-mail address a login is attempted for.
+	DefaultAccountThrottle = ThrottlePolicy{FreeAttempts: 5, BaseLockout: 30 * time.Second, MaxLockout: 15 * time.Minute, Window: time.Hour}
+	// DefaultIPThrottle applies to the client address and allows more failures, as users
+	// behind one NAT share it.
+	DefaultIPThrottle = ThrottlePolicy{FreeAttempts: 20, BaseLockout: 30 * time.Second, MaxLockout: 15 * time.Minute, Window: time.Hour}
+)
+
+// lockout returns the lockout after the given number of failures; zero means none.
+func (p ThrottlePolicy) lockout(failures int) time.Duration {
+	if failures <= p.FreeAttempts {
+		return 0
+	}
+	d := p.BaseLockout
+	for i := p.FreeAttempts + 1; i < failures && d < p.MaxLockout; i++ {
+		d *= 2
+	}
+	return min(d, p.MaxLockout)
+}
+
+// LoginThrottle tracks failed logins per account and per client IP and locks them out with
+// growing delays. Store errors are logged and let the login through, so an outage of the
+// store does not lock every user out.
+type LoginThrottle struct {
+	store   repository.LoginThrottleRepository
+	account ThrottlePolicy
+	ip      ThrottlePolicy
+	audit   audit.Logger
+}
+
+// NewLoginThrottle wires the store, the policies for accounts and client addresses and the
+// audit log lockouts are reported to.
+func NewLoginThrottle(store repository.LoginThrottleRepository, account, ip ThrottlePolicy, auditLog audit.Logger) *LoginThrottle {
+	return &LoginThrottle{store: store, account: account, ip: ip, audit: auditLog}
+}
+
+// Allow returns a *TooManyAttemptsError while the account or the client address is locked out.
+// Besides the stored lock, the failure count itself is checked: a lock that was not written,
+// or not yet, because concurrent guesses raced past it does not let more guesses through.
+func (t *LoginThrottle) Allow(ctx context.Context, email, ip string) error {
+	now := time.Now()
+	var retryAfter time.Duration
+	for i, subject := range t.subjects(email, ip) {
+		throttle, err := t.store.Get(ctx, subject)
+		if err != nil {
+			log.Printf("login throttle %s: %v", subject, err)
+			continue
+		}
+		if throttle == nil {
+			continue
+		}
+		if locked, left := throttle.Locked(now); locked {
+			retryAfter = max(retryAfter, left)
+		}
+		if until := throttle.LastFailureAt.Add(t.policy(i).lockout(throttle.Failures)); now.Before(until) {
+			retryAfter = max(retryAfter, until.Sub(now))
+		}
+	}
+	if retryAfter == 0 {
+		return nil
+	}
+	t.audit.Record(ctx, audit.Event{Type: audit.LoginRejected, Time: now, Email: email, IP: ip, Detail: fmt.Sprintf("locked for another %s", retryAfter.Round(time.Second))})
+	return &TooManyAttemptsError{RetryAfter: retryAfter}
+}
+
+// Failure records a failed attempt of the given event type, e.g. audit.LoginFailed, and locks
+// the account or the client address out once their policy says so. userID is zero when the
+// account is unknown.
+func (t *LoginThrottle) Failure(ctx context.Context, eventType string, userID uint, email, ip string) {
+	now := time.Now()
+	t.audit.Record(ctx, audit.Event{Type: eventType, Time: now, UserID: userID, Email: email, IP: ip})
+	for i, subject := range t.subjects(email, ip) {
+		policy := t.policy(i)
+		throttle, err := t.store.RecordFailure(ctx, subject, now, policy.Window)
+		if err != nil {
+			log.Printf("login throttle %s: %v", subject, err)
+			continue
+		}
+		lockout := policy.lockout(throttle.Failures)
+		if lockout == 0 {
+			continue
+		}
+		if err := t.store.Lock(ctx, subject, now.Add(lockout)); err != nil {
+			log.Printf("login throttle %s: %v", subject, err)
+			continue
+		}
+		t.audit.Record(ctx, audit.Event{Type: audit.LoginLocked, Time: now, UserID: userID, Email: email, IP: ip, Detail: fmt.Sprintf("%s locked for %s after %d failures", subject, lockout, throttle.Failures)})
+	}
+}
+
+// Success clears the failures of the account. Those of the client address stay, so an
+// attacker cannot reset them by logging in to an own account now and then.
+func (t *LoginThrottle) Success(ctx context.Context, email string) {
+	if err := t.Forget(ctx, email); err != nil {
+		log.Printf("login throttle %s: %v", t.subjects(email, "")[0], err)
+	}
+}
+
+// Forget drops the failures and lock of an account, e.g. when it is deleted.
+func (t *LoginThrottle) Forget(ctx context.Context, email string) error {
+	return t.store.Reset(ctx, t.subjects(email, "")[0])
+}
+
+// policy returns the policy of the i-th subject returned by subjects.
+func (t *LoginThrottle) policy(i int) ThrottlePolicy {
+	if i == 1 {
+		return t.ip
+	}
+	return t.account
+}
+
+// subjects returns the throttle subjects of an account and a client address, in that order.
+func (t *LoginThrottle) subjects(email, ip string) []string {
+	subjects := []string{"account:" + strings.ToLower(strings.TrimSpace(email))}
+	if ip != "" {
+		subjects = append(subjects, "ip:"+ip)
+	}
+	return subjects
+}
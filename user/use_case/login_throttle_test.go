@@ -0,0 +1,184 @@
+package use_case_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/audit"
+	"github.com/VibeTeam/fitness-tracker-backend/user/auth"
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/repository"
+	"github.com/VibeTeam/fitness-tracker-backend/user/repository/memrepository"
+	"github.com/VibeTeam/fitness-tracker-backend/user/use_case"
+)
+
+// auditRecorder keeps audit events in memory.
+type auditRecorder struct {
+	mu     sync.Mutex
+	events []audit.Event
+}
+
+func (r *auditRecorder) Record(_ context.Context, e audit.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+func (r *auditRecorder) count(eventType string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, e := range r.events {
+		if e.Type == eventType {
+			n++
+		}
+	}
+	return n
+}
+
+// skewedStore wraps a throttle store to make its failures and locks look age old, and to
+// drop locks like a store that failed to write them.
+type skewedStore struct {
+	repository.LoginThrottleRepository
+	age       time.Duration
+	dropLocks bool
+}
+
+func (s *skewedStore) Get(ctx context.Context, subject string) (*models.LoginThrottle, error) {
+	t, err := s.LoginThrottleRepository.Get(ctx, subject)
+	if t == nil || err != nil {
+		return t, err
+	}
+	t.LastFailureAt = t.LastFailureAt.Add(-s.age)
+	if t.LockedUntil != nil {
+		until := t.LockedUntil.Add(-s.age)
+		t.LockedUntil = &until
+	}
+	return t, nil
+}
+
+func (s *skewedStore) Lock(ctx context.Context, subject string, until time.Time) error {
+	if s.dropLocks {
+		return errors.New("connection reset")
+	}
+	return s.LoginThrottleRepository.Lock(ctx, subject, until)
+}
+
+var testThrottle = use_case.ThrottlePolicy{FreeAttempts: 2, BaseLockout: time.Minute, MaxLockout: 4 * time.Minute, Window: time.Hour}
+
+func TestLoginLockout(t *testing.T) {
+	store := &skewedStore{LoginThrottleRepository: memrepository.NewLoginThrottleRepository()}
+	events := &auditRecorder{}
+	authSvc := use_case.NewAuthService(newRepo(), &inMemRefreshRepo{}, &inMemSessionRepo{}, auth.NewManager("a", "b", time.Minute, time.Hour))
+	authSvc.SetLoginThrottle(use_case.NewLoginThrottle(store, testThrottle, use_case.DefaultIPThrottle, events))
+	ctx := context.Background()
+	_, _, err := authSvc.Register(ctx, "victim@b.c", "right")
+	require.NoError(t, err)
+	device := use_case.Device{IP: "10.0.0.1"}
+
+	// Successful logins clear earlier failures.
+	_, err = authSvc.Login(ctx, "victim@b.c", "wrong", device)
+	require.ErrorIs(t, err, use_case.ErrInvalidCredentials)
+	_, err = authSvc.Login(ctx, "victim@b.c", "right", device)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err = authSvc.Login(ctx, "Victim@b.c", "wrong", device)
+		require.ErrorIs(t, err, use_case.ErrInvalidCredentials)
+	}
+	require.Equal(t, 1, events.count(audit.LoginLocked))
+
+	// Locked out, even with the right password.
+	_, err = authSvc.Login(ctx, "victim@b.c", "right", device)
+	var tooMany *use_case.TooManyAttemptsError
+	require.ErrorAs(t, err, &tooMany)
+	require.ErrorIs(t, err, use_case.ErrTooManyAttempts)
+	require.InDelta(t, time.Minute.Seconds(), tooMany.RetryAfter.Seconds(), 2)
+	require.Equal(t, 1, events.count(audit.LoginRejected))
+
+	// Once the lockout is over, each further failure doubles it.
+	store.age = time.Minute + time.Second
+	_, err = authSvc.Login(ctx, "victim@b.c", "wrong", device)
+	require.ErrorIs(t, err, use_case.ErrInvalidCredentials)
+	store.age = 0
+	_, err = authSvc.Login(ctx, "victim@b.c", "right", device)
+	require.ErrorAs(t, err, &tooMany)
+	require.InDelta(t, (2 * time.Minute).Seconds(), tooMany.RetryAfter.Seconds(), 2)
+	require.Equal(t, 5, events.count(audit.LoginFailed))
+}
+
+func TestLoginLockoutWithoutStoredLock(t *testing.T) {
+	// The store counts the failures but loses the locks, as when concurrent guesses race
+	// past the lock or its write fails.
+	store := &skewedStore{LoginThrottleRepository: memrepository.NewLoginThrottleRepository(), dropLocks: true}
+	authSvc := use_case.NewAuthService(newRepo(), &inMemRefreshRepo{}, &inMemSessionRepo{}, auth.NewManager("a", "b", time.Minute, time.Hour))
+	authSvc.SetLoginThrottle(use_case.NewLoginThrottle(store, testThrottle, use_case.DefaultIPThrottle, &auditRecorder{}))
+	ctx := context.Background()
+	_, _, err := authSvc.Register(ctx, "victim@b.c", "right")
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err = authSvc.Login(ctx, "victim@b.c", "wrong", use_case.Device{})
+		require.ErrorIs(t, err, use_case.ErrInvalidCredentials)
+	}
+	_, err = authSvc.Login(ctx, "victim@b.c", "right", use_case.Device{})
+	var tooMany *use_case.TooManyAttemptsError
+	require.ErrorAs(t, err, &tooMany)
+	require.InDelta(t, time.Minute.Seconds(), tooMany.RetryAfter.Seconds(), 2)
+}
+
+func TestLoginLockoutPerIP(t *testing.T) {
+	store := memrepository.NewLoginThrottleRepository()
+	authSvc := use_case.NewAuthService(newRepo(), &inMemRefreshRepo{}, &inMemSessionRepo{}, auth.NewManager("a", "b", time.Minute, time.Hour))
+	authSvc.SetLoginThrottle(use_case.NewLoginThrottle(store, use_case.DefaultAccountThrottle, testThrottle, &auditRecorder{}))
+	ctx := context.Background()
+	_, _, err := authSvc.Register(ctx, "user@b.c", "right")
+	require.NoError(t, err)
+
+	// Spraying one password over many accounts locks the client address out.
+	for _, email := range []string{"a@b.c", "b@b.c", "c@b.c"} {
+		_, err = authSvc.Login(ctx, email, "guess", use_case.Device{IP: "10.0.0.9"})
+		require.ErrorIs(t, err, use_case.ErrInvalidCredentials)
+	}
+	_, err = authSvc.Login(ctx, "user@b.c", "right", use_case.Device{IP: "10.0.0.9"})
+	require.ErrorIs(t, err, use_case.ErrTooManyAttempts)
+
+	// Other clients are not affected.
+	_, err = authSvc.Login(ctx, "user@b.c", "right", use_case.Device{IP: "10.0.0.10"})
+	require.NoError(t, err)
+}
+
+func TestMFAFailuresCountTowardsLockout(t *testing.T) {
+	repo := newRepo()
+	events := &auditRecorder{}
+	authSvc := use_case.NewAuthService(repo, &inMemRefreshRepo{}, &inMemSessionRepo{}, auth.NewManager("a", "b", time.Minute, time.Hour))
+	authSvc.SetLoginThrottle(use_case.NewLoginThrottle(memrepository.NewLoginThrottleRepository(), testThrottle, use_case.DefaultIPThrottle, events))
+	mfa := use_case.NewMFAService(repo, &inMemRecoveryRepo{}, authSvc, "Fitness Tracker")
+	ctx := context.Background()
+
+	_, _, err := authSvc.Register(ctx, "otp@b.c", "pwd")
+	require.NoError(t, err)
+	enrollment, err := mfa.Enroll(ctx, 1, "pwd")
+	require.NoError(t, err)
+	require.NoError(t, mfa.Confirm(ctx, 1, totpCode(t, enrollment.Secret, time.Now().Add(-30*time.Second))))
+
+	res, err := authSvc.Login(ctx, "otp@b.c", "pwd", use_case.Device{})
+	require.NoError(t, err)
+	require.True(t, res.MFARequired())
+	for i := 0; i < 3; i++ {
+		_, _, err = mfa.Verify(ctx, res.MFAToken, "not-a-code", use_case.Device{})
+		require.ErrorIs(t, err, use_case.ErrInvalidMFACode)
+	}
+	require.Equal(t, 3, events.count(audit.MFAFailed))
+
+	// The valid code is refused now, and so is the password.
+	_, _, err = mfa.Verify(ctx, res.MFAToken, totpCode(t, enrollment.Secret, time.Now()), use_case.Device{})
+	require.ErrorIs(t, err, use_case.ErrTooManyAttempts)
+	_, err = authSvc.Login(ctx, "otp@b.c", "pwd", use_case.Device{})
+	require.ErrorIs(t, err, use_case.ErrTooManyAttempts)
+}
@@ -0,0 +1,209 @@
+package use_case
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/audit"
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/repository"
+	"github.com/VibeTeam/fitness-tracker-backend/user/totp"
+)
+
+// RecoveryCodeCount is the number of recovery codes handed out on enrollment.
+const RecoveryCodeCount = 10
+
+var (
+	// ErrMFAAlreadyEnabled is returned when enrolling a user whose two-factor authentication is on.
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	// ErrMFANotEnrolled is returned when confirming without a pending enrollment.
+	ErrMFANotEnrolled = errors.New("two-factor authentication enrollment has not been started")
+	// ErrInvalidMFACode is returned for wrong, reused or already spent TOTP and recovery codes.
+	ErrInvalidMFACode = errors.New("invalid authentication code")
+	// ErrInvalidMFAToken is returned for unknown or expired MFA challenge tokens.
+	ErrInvalidMFAToken = errors.New("invalid or expired MFA token, please log in again")
+)
+
+// MFAService implements TOTP two-factor authentication: enrollment, confirmation and the
+// second step of a login.
+type MFAService struct {
+	users  repository.UserRepository
+	codes  repository.RecoveryCodeRepository
+	auth   *AuthService
+	issuer string
+}
+
+// NewMFAService wires the repositories and the auth service that starts sessions once the
+// second factor is checked. issuer names the service in authenticator apps.
+func NewMFAService(users repository.UserRepository, codes repository.RecoveryCodeRepository, auth *AuthService, issuer string) *MFAService {
+	return &MFAService{users: users, codes: codes, auth: auth, issuer: issuer}
+}
+
+// Enrollment is what a user needs to set up an authenticator app. RecoveryCodes are shown
+// only once; the server keeps their hashes.
+type Enrollment struct {
+	Secret        string
+	URI           string
+	RecoveryCodes []string
+}
+
+// Enroll starts, or restarts, TOTP enrollment with a new secret and new recovery codes after
+// checking the password, so a stolen access token cannot tie the account to an attacker's app.
+// Two-factor authentication stays off until Confirm succeeds.
+func (s *MFAService) Enroll(ctx context.Context, userID uint, password string) (*Enrollment, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPassword(user, password); err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	user.TOTPSecret = totp.GenerateSecret()
+	user.TOTPLastStep = 0
+	if err := s.users.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	codes := make([]string, RecoveryCodeCount)
+	hashes := make([]string, RecoveryCodeCount)
+	for i := range codes {
+		codes[i] = newRecoveryCode()
+		hashes[i] = hashToken(normalizeRecoveryCode(codes[i]))
+	}
+	if err := s.codes.Replace(ctx, user.ID, hashes); err != nil {
+		return nil, err
+	}
+
+	return &Enrollment{
+		Secret:        user.TOTPSecret,
+		URI:           totp.URI(s.issuer, user.Email, user.TOTPSecret),
+		RecoveryCodes: codes,
+	}, nil
+}
+
+// Confirm enables two-factor authentication once the user proves the app was set up by
+// entering a current code.
+func (s *MFAService) Confirm(ctx context.Context, userID uint, code string) error {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.MFAEnabled() {
+		return ErrMFAAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return ErrMFANotEnrolled
+	}
+	now := time.Now()
+	if ok, err := s.acceptTOTP(ctx, user, code, now); err != nil {
+		return err
+	} else if !ok {
+		return ErrInvalidMFACode
+	}
+	user.MFAEnabledAt = &now
+	return s.users.Update(ctx, user)
+}
+
+// Disable turns two-factor authentication off after checking the password and drops the
+// secret and recovery codes.
+func (s *MFAService) Disable(ctx context.Context, userID uint, password string) error {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkPassword(user, password); err != nil {
+		return err
+	}
+	user.TOTPSecret = ""
+	user.TOTPLastStep = 0
+	user.MFAEnabledAt = nil
+	if err := s.users.Update(ctx, user); err != nil {
+		return err
+	}
+	return s.codes.DeleteByUser(ctx, userID)
+}
+
+// Verify completes a login started by AuthService.Login: it checks code, either a TOTP code
+// or an unused recovery code, and starts the session the challenge was issued for. The device
+// name is taken from the challenge. Wrong codes count as failed logins for the login throttle.
+func (s *MFAService) Verify(ctx context.Context, mfaToken, code string, device Device) (accessToken, refreshToken string, err error) {
+	claims, err := s.auth.tokenManger.ParseMFAToken(mfaToken)
+	if err != nil {
+		return "", "", ErrInvalidMFAToken
+	}
+	user, err := s.users.GetByID(ctx, uint(claims.UserID))
+	if err != nil || !user.MFAEnabled() {
+		return "", "", ErrInvalidMFAToken
+	}
+	if err := s.auth.allowLogin(ctx, user.Email, device.IP); err != nil {
+		return "", "", err
+	}
+
+	now := time.Now()
+	accepted, err := s.acceptTOTP(ctx, user, code, now)
+	if err != nil {
+		return "", "", err
+	}
+	if !accepted {
+		ok, err := s.codes.Use(ctx, user.ID, hashToken(normalizeRecoveryCode(code)), now)
+		if err != nil {
+			return "", "", err
+		}
+		if !ok {
+			s.auth.loginFailed(ctx, audit.MFAFailed, user.ID, user.Email, device.IP)
+			return "", "", ErrInvalidMFACode
+		}
+	}
+
+	device.Name = claims.DeviceName
+	return s.auth.startSession(ctx, user, device)
+}
+
+// Enabled reports whether the user has confirmed two-factor authentication.
+func (s *MFAService) Enabled(ctx context.Context, userID uint) (bool, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return user.MFAEnabled(), nil
+}
+
+// RecoveryCodesLeft returns how many unused recovery codes the user has.
+func (s *MFAService) RecoveryCodesLeft(ctx context.Context, userID uint) (int, error) {
+	return s.codes.CountUnused(ctx, userID)
+}
+
+// acceptTOTP checks a TOTP code and, when it is valid and newer than the last accepted one,
+// records its time step. The step is advanced with a conditional update, so of two requests
+// racing with the same code only one succeeds.
+func (s *MFAService) acceptTOTP(ctx context.Context, user *models.User, code string, now time.Time) (bool, error) {
+	step, ok := totp.Validate(user.TOTPSecret, strings.TrimSpace(code), now)
+	if !ok || step <= user.TOTPLastStep {
+		return false, nil
+	}
+	if ok, err := s.users.AdvanceTOTPStep(ctx, user.ID, step); err != nil || !ok {
+		return false, err
+	}
+	user.TOTPLastStep = step
+	return true, nil
+}
+
+// newRecoveryCode returns 80 random bits as four dash-separated groups, e.g. "k3vq-7xma-p2dn-w9tc".
+func newRecoveryCode() string {
+	b := make([]byte, 10)
+	_, _ = rand.Read(b)
+	s := strings.ToLower(base32.StdEncoding.EncodeToString(b))
+	return s[0:4] + "-" + s[4:8] + "-" + s[8:12] + "-" + s[12:16]
+}
+
+// normalizeRecoveryCode ignores case, spaces and dashes so codes can be typed loosely.
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -0,0 +1,203 @@
+package use_case_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/auth"
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/totp"
+	"github.com/VibeTeam/fitness-tracker-backend/user/use_case"
+)
+
+/* -------------------------------------------------------------------------- */
+/* In‑memory RecoveryCodeRepository stub                                      */
+/* -------------------------------------------------------------------------- */
+
+type inMemRecoveryRepo struct {
+	mu    sync.Mutex
+	codes []*models.RecoveryCode
+}
+
+func (r *inMemRecoveryRepo) Replace(_ context.Context, userID uint, hashes []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := r.codes[:0]
+	for _, c := range r.codes {
+		if c.UserID != userID {
+			kept = append(kept, c)
+		}
+	}
+	r.codes = kept
+	for _, h := range hashes {
+		r.codes = append(r.codes, &models.RecoveryCode{UserID: userID, CodeHash: h})
+	}
+	return nil
+}
+
+func (r *inMemRecoveryRepo) Use(_ context.Context, userID uint, hash string, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.codes {
+		if c.UserID == userID && c.CodeHash == hash && c.UsedAt == nil {
+			c.UsedAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *inMemRecoveryRepo) CountUnused(_ context.Context, userID uint) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, c := range r.codes {
+		if c.UserID == userID && c.UsedAt == nil {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *inMemRecoveryRepo) DeleteByUser(ctx context.Context, userID uint) error {
+	return r.Replace(ctx, userID, nil)
+}
+
+/* -------------------------------------------------------------------------- */
+
+// totpCode returns the code of secret for the period containing t.
+func totpCode(t *testing.T, secret string, at time.Time) string {
+	code, err := totp.Code(secret, totp.Step(at))
+	require.NoError(t, err)
+	return code
+}
+
+func TestMFAEnrollmentAndLogin(t *testing.T) {
+	repo := newRepo()
+	tm := auth.NewManager("a", "b", time.Minute, time.Hour)
+	authSvc := use_case.NewAuthService(repo, &inMemRefreshRepo{}, &inMemSessionRepo{}, tm)
+	mfa := use_case.NewMFAService(repo, &inMemRecoveryRepo{}, authSvc, "Fitness Tracker")
+	ctx := context.Background()
+
+	access, _, err := authSvc.Register(ctx, "mfa@b.c", "pwd")
+	require.NoError(t, err)
+
+	_, err = mfa.Enroll(ctx, 1, "wrong")
+	require.ErrorIs(t, err, use_case.ErrWrongPassword, "enrolling needs the password, not just a token")
+	e, err := mfa.Enroll(ctx, 1, "pwd")
+	require.NoError(t, err)
+	require.Len(t, e.RecoveryCodes, use_case.RecoveryCodeCount)
+	require.True(t, strings.HasPrefix(e.URI, "otpauth://totp/"))
+
+	// Until confirmed, logins keep returning tokens right away.
+	res, err := authSvc.Login(ctx, "mfa@b.c", "pwd", use_case.Device{})
+	require.NoError(t, err)
+	require.False(t, res.MFARequired())
+
+	require.ErrorIs(t, mfa.Confirm(ctx, 1, "000000"), use_case.ErrInvalidMFACode)
+	now := time.Now()
+	require.NoError(t, mfa.Confirm(ctx, 1, totpCode(t, e.Secret, now)))
+	_, err = mfa.Enroll(ctx, 1, "pwd")
+	require.ErrorIs(t, err, use_case.ErrMFAAlreadyEnabled)
+
+	res, err = authSvc.Login(ctx, "mfa@b.c", "pwd", use_case.Device{Name: "tablet"})
+	require.NoError(t, err)
+	require.True(t, res.MFARequired())
+	require.Empty(t, res.AccessToken)
+
+	// The confirmation code cannot be replayed, and access tokens are no challenges.
+	_, _, err = mfa.Verify(ctx, res.MFAToken, totpCode(t, e.Secret, now), use_case.Device{})
+	require.ErrorIs(t, err, use_case.ErrInvalidMFACode)
+	_, _, err = mfa.Verify(ctx, access, totpCode(t, e.Secret, now.Add(totp.Period)), use_case.Device{})
+	require.ErrorIs(t, err, use_case.ErrInvalidMFAToken)
+
+	access, refresh, err := mfa.Verify(ctx, res.MFAToken, totpCode(t, e.Secret, now.Add(totp.Period)), use_case.Device{IP: "10.0.0.2"})
+	require.NoError(t, err)
+	require.NotEmpty(t, access)
+	require.NotEmpty(t, refresh)
+	sessions, err := authSvc.Sessions(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, "tablet", sessions[len(sessions)-1].DeviceName)
+	require.Equal(t, "10.0.0.2", sessions[len(sessions)-1].IP)
+}
+
+func TestMFARecoveryCodes(t *testing.T) {
+	repo := newRepo()
+	tm := auth.NewManager("a", "b", time.Minute, time.Hour)
+	authSvc := use_case.NewAuthService(repo, &inMemRefreshRepo{}, &inMemSessionRepo{}, tm)
+	mfa := use_case.NewMFAService(repo, &inMemRecoveryRepo{}, authSvc, "Fitness Tracker")
+	ctx := context.Background()
+
+	_, _, err := authSvc.Register(ctx, "lost@b.c", "pwd")
+	require.NoError(t, err)
+	e, err := mfa.Enroll(ctx, 1, "pwd")
+	require.NoError(t, err)
+	require.NoError(t, mfa.Confirm(ctx, 1, totpCode(t, e.Secret, time.Now())))
+
+	res, err := authSvc.Login(ctx, "lost@b.c", "pwd", use_case.Device{})
+	require.NoError(t, err)
+
+	_, _, err = mfa.Verify(ctx, res.MFAToken, e.RecoveryCodes[0], use_case.Device{})
+	require.NoError(t, err)
+	_, _, err = mfa.Verify(ctx, res.MFAToken, e.RecoveryCodes[0], use_case.Device{})
+	require.ErrorIs(t, err, use_case.ErrInvalidMFACode, "recovery codes are single-use")
+
+	// Codes may be typed without dashes and in upper case.
+	loose := strings.ToUpper(strings.ReplaceAll(e.RecoveryCodes[1], "-", ""))
+	_, _, err = mfa.Verify(ctx, res.MFAToken, loose, use_case.Device{})
+	require.NoError(t, err)
+
+	left, err := mfa.RecoveryCodesLeft(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, use_case.RecoveryCodeCount-2, left)
+
+	require.ErrorIs(t, mfa.Disable(ctx, 1, "wrong"), use_case.ErrWrongPassword)
+	require.NoError(t, mfa.Disable(ctx, 1, "pwd"))
+	res, err = authSvc.Login(ctx, "lost@b.c", "pwd", use_case.Device{})
+	require.NoError(t, err)
+	require.False(t, res.MFARequired())
+	left, err = mfa.RecoveryCodesLeft(ctx, 1)
+	require.NoError(t, err)
+	require.Zero(t, left)
+}
+
+func TestMFACodeAcceptedOnceUnderConcurrency(t *testing.T) {
+	repo := newRepo()
+	tm := auth.NewManager("a", "b", time.Minute, time.Hour)
+	authSvc := use_case.NewAuthService(repo, &inMemRefreshRepo{}, &inMemSessionRepo{}, tm)
+	mfa := use_case.NewMFAService(repo, &inMemRecoveryRepo{}, authSvc, "Fitness Tracker")
+	ctx := context.Background()
+
+	_, _, err := authSvc.Register(ctx, "race@b.c", "pwd")
+	require.NoError(t, err)
+	e, err := mfa.Enroll(ctx, 1, "pwd")
+	require.NoError(t, err)
+	now := time.Now()
+	require.NoError(t, mfa.Confirm(ctx, 1, totpCode(t, e.Secret, now.Add(-totp.Period))))
+	res, err := authSvc.Login(ctx, "race@b.c", "pwd", use_case.Device{})
+	require.NoError(t, err)
+
+	// Every request reads the user before any of them records the step.
+	code := totpCode(t, e.Secret, now)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	accepted := 0
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := mfa.Verify(ctx, res.MFAToken, code, use_case.Device{}); err == nil {
+				mu.Lock()
+				accepted++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 1, accepted)
+}
@@ -0,0 +1,165 @@
+package use_case
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/mailer"
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/repository"
+)
+
+const (
+	// PasswordResetTTL is how long a mailed reset link stays valid.
+	PasswordResetTTL = time.Hour
+	// ResetLinkCooldown is how long after mailing a reset link further requests for the same
+	// account through Forgot are ignored, so the endpoint cannot flood a mailbox.
+	ResetLinkCooldown = time.Minute
+	// maxPendingResets caps the reset links Forgot mails at once; requests beyond it are dropped.
+	maxPendingResets = 32
+)
+
+// ErrInvalidResetToken is returned for unknown, expired or already used reset tokens.
+var ErrInvalidResetToken = errors.New("invalid or expired reset token")
+
+// PasswordResetService lets users who forgot their password set a new one through a mailed link.
+type PasswordResetService struct {
+	users    repository.UserRepository
+	resets   repository.PasswordResetRepository
+	auth     *AuthService
+	mailer   mailer.Mailer
+	resetURL string
+	pending  sync.WaitGroup
+	slots    chan struct{}
+}
+
+// NewPasswordResetService wires the reset workflow. resetURL is the frontend page that receives
+// the token as its "token" query parameter.
+func NewPasswordResetService(users repository.UserRepository, resets repository.PasswordResetRepository, auth *AuthService, m mailer.Mailer, resetURL string) *PasswordResetService {
+	return &PasswordResetService{users: users, resets: resets, auth: auth, mailer: m, resetURL: resetURL, slots: make(chan struct{}, maxPendingResets)}
+}
+
+// Forgot mails a reset link to the account with the given e-mail address and invalidates
+// earlier links. The work happens in the background so neither the response nor its timing
+// reveals whether the address belongs to an account; unknown addresses are ignored and
+// failures are only logged. Requests within ResetLinkCooldown of the last link, and requests
+// while maxPendingResets links are being mailed, are dropped.
+func (s *PasswordResetService) Forgot(ctx context.Context, email string) {
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		log.Printf("password reset for %s: too many pending requests, dropped", email)
+		return
+	}
+	s.pending.Add(1)
+	go func() {
+		defer func() {
+			<-s.slots
+			s.pending.Done()
+		}()
+		if err := s.sendResetLink(context.WithoutCancel(ctx), email); err != nil {
+			log.Printf("password reset for %s: %v", email, err)
+		}
+	}()
+}
+
+// Wait blocks until every reset link requested so far has been handled.
+func (s *PasswordResetService) Wait() {
+	s.pending.Wait()
+}
+
+// SendLink mails a reset link to the signed-in user. Accounts created through social login
+// have no password, and this is how they set one before making changes that ask for it.
+func (s *PasswordResetService) SendLink(ctx context.Context, userID uint) error {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.mailResetLink(ctx, user)
+}
+
+// sendResetLink mails a reset link to the account with the given address, if there is one
+// and it was not sent a link within ResetLinkCooldown.
+func (s *PasswordResetService) sendResetLink(ctx context.Context, email string) error {
+	user, err := s.users.GetByEmail(ctx, email)
+	if err != nil {
+		return nil
+	}
+	if last, err := s.resets.LatestByUser(ctx, user.ID); err == nil && time.Since(last.CreatedAt) < ResetLinkCooldown {
+		return nil
+	}
+	return s.mailResetLink(ctx, user)
+}
+
+// mailResetLink issues a new reset token for user, invalidating earlier ones, and mails it.
+func (s *PasswordResetService) mailResetLink(ctx context.Context, user *models.User) error {
+	now := time.Now()
+	if err := s.resets.InvalidateByUser(ctx, user.ID, now); err != nil {
+		return err
+	}
+	token := newSecret()
+	err := s.resets.Create(ctx, &models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: now.Add(PasswordResetTTL),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	link := s.resetURL + "?token=" + url.QueryEscape(token)
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Someone asked to set a new password for your Fitness Tracker account.\n\n"+
+			"Open this link within %d minutes to choose a password:\n%s\n\n"+
+			"If this wasn't you, ignore this e-mail; your password stays unchanged.\n",
+			int(PasswordResetTTL.Minutes()), link),
+	})
+}
+
+// Reset consumes a reset token and sets the new password. All sessions of the user end,
+// so a device that knew the old password is signed out.
+func (s *PasswordResetService) Reset(ctx context.Context, token, newPassword string) error {
+	stored, err := s.resets.GetByHash(ctx, hashToken(token))
+	now := time.Now()
+	if err != nil || stored.UsedAt != nil || now.After(stored.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+	if ok, err := s.resets.MarkUsed(ctx, stored.ID, now); err != nil {
+		return err
+	} else if !ok {
+		return ErrInvalidResetToken
+	}
+
+	user, err := s.users.GetByID(ctx, stored.UserID)
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = string(hash)
+	if err := s.users.Update(ctx, user); err != nil {
+		return err
+	}
+	return s.auth.Logout(ctx, user.ID, "")
+}
+
+// newSecret returns a random URL-safe token for links sent by e-mail.
+func newSecret() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
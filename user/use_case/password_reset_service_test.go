@@ -0,0 +1,167 @@
+package use_case_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/auth"
+	"github.com/VibeTeam/fitness-tracker-backend/user/mailer"
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/use_case"
+)
+
+/* -------------------------------------------------------------------------- */
+/* In‑memory PasswordResetRepository stub                                     */
+/* -------------------------------------------------------------------------- */
+
+type inMemResetRepo struct {
+	mu     sync.Mutex
+	tokens []*models.PasswordResetToken
+}
+
+func (r *inMemResetRepo) Create(_ context.Context, t *models.PasswordResetToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t.ID = uint(len(r.tokens) + 1)
+	cp := *t
+	r.tokens = append(r.tokens, &cp)
+	return nil
+}
+
+func (r *inMemResetRepo) GetByHash(_ context.Context, hash string) (*models.PasswordResetToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.TokenHash == hash {
+			cp := *t
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *inMemResetRepo) LatestByUser(_ context.Context, userID uint) (*models.PasswordResetToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.tokens) - 1; i >= 0; i-- {
+		if r.tokens[i].UserID == userID {
+			cp := *r.tokens[i]
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *inMemResetRepo) MarkUsed(_ context.Context, id uint, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.tokens[id-1].UsedAt != nil {
+		return false, nil
+	}
+	r.tokens[id-1].UsedAt = &at
+	return true, nil
+}
+
+func (r *inMemResetRepo) InvalidateByUser(_ context.Context, userID uint, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.UsedAt == nil {
+			t.UsedAt = &at
+		}
+	}
+	return nil
+}
+
+// DeleteByUser blanks the rows of a user instead of removing them, as IDs index the slice.
+func (r *inMemResetRepo) DeleteByUser(_ context.Context, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, t := range r.tokens {
+		if t.UserID == userID {
+			r.tokens[i] = &models.PasswordResetToken{ID: t.ID}
+		}
+	}
+	return nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+var resetLink = regexp.MustCompile(`https://app\.example/reset\?token=(\S+)`)
+
+// lastResetToken extracts the token of the most recent reset link written to the mail log.
+func lastResetToken(t *testing.T, mail *bytes.Buffer) string {
+	m := resetLink.FindAllStringSubmatch(mail.String(), -1)
+	require.NotEmpty(t, m, "no reset link mailed")
+	return m[len(m)-1][1]
+}
+
+func TestPasswordReset(t *testing.T) {
+	repo := newRepo()
+	resets := &inMemResetRepo{}
+	tm := auth.NewManager("a", "b", time.Minute, time.Hour)
+	authSvc := use_case.NewAuthService(repo, &inMemRefreshRepo{}, &inMemSessionRepo{}, tm)
+	var mail bytes.Buffer
+	svc := use_case.NewPasswordResetService(repo, resets, authSvc, mailer.NewLog(&mail, "noreply@app.example"), "https://app.example/reset")
+	ctx := context.Background()
+
+	_, refresh, err := authSvc.Register(ctx, "forgot@b.c", "old")
+	require.NoError(t, err)
+
+	// Unknown addresses are ignored without mail.
+	svc.Forgot(ctx, "nobody@b.c")
+	svc.Wait()
+	require.Zero(t, mail.Len())
+
+	svc.Forgot(ctx, "forgot@b.c")
+	svc.Wait()
+	first := lastResetToken(t, &mail)
+
+	// Repeated requests within the cooldown mail nothing.
+	sent := mail.Len()
+	svc.Forgot(ctx, "forgot@b.c")
+	svc.Wait()
+	require.Equal(t, sent, mail.Len())
+
+	resets.tokens[0].CreatedAt = time.Now().Add(-use_case.ResetLinkCooldown)
+	svc.Forgot(ctx, "forgot@b.c")
+	svc.Wait()
+	second := lastResetToken(t, &mail)
+
+	// Requesting a new link invalidates the previous one.
+	require.ErrorIs(t, svc.Reset(ctx, first, "new"), use_case.ErrInvalidResetToken)
+	require.NoError(t, svc.Reset(ctx, second, "new"))
+	require.ErrorIs(t, svc.Reset(ctx, second, "newer"), use_case.ErrInvalidResetToken)
+
+	u, _ := repo.GetByEmail(ctx, "forgot@b.c")
+	require.NoError(t, bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte("new")))
+	require.Nil(t, u.EmailVerifiedAt, "a reset does not verify the address")
+	_, _, err = authSvc.Refresh(ctx, refresh)
+	require.ErrorIs(t, err, use_case.ErrInvalidRefreshToken, "reset signs out existing sessions")
+}
+
+func TestPasswordResetExpired(t *testing.T) {
+	repo := newRepo()
+	resets := &inMemResetRepo{}
+	tm := auth.NewManager("a", "b", time.Minute, time.Hour)
+	authSvc := use_case.NewAuthService(repo, &inMemRefreshRepo{}, &inMemSessionRepo{}, tm)
+	var mail bytes.Buffer
+	svc := use_case.NewPasswordResetService(repo, resets, authSvc, mailer.NewLog(&mail, "noreply@app.example"), "https://app.example/reset")
+	ctx := context.Background()
+
+	_, _, err := authSvc.Register(ctx, "late@b.c", "old")
+	require.NoError(t, err)
+	svc.Forgot(ctx, "late@b.c")
+	svc.Wait()
+	resets.tokens[0].ExpiresAt = time.Now().Add(-time.Minute)
+
+	require.ErrorIs(t, svc.Reset(ctx, lastResetToken(t, &mail), "new"), use_case.ErrInvalidResetToken)
+}
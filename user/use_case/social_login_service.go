@@ -0,0 +1,173 @@
+package use_case
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/oidc"
+	"github.com/VibeTeam/fitness-tracker-backend/user/repository"
+)
+
+// SocialLoginStateTTL is how long a user has to sign in at the identity provider.
+const SocialLoginStateTTL = 10 * time.Minute
+
+var (
+	// ErrUnknownProvider is returned for identity providers that are not configured.
+	ErrUnknownProvider = errors.New("unknown identity provider")
+	// ErrInvalidLoginState is returned for unknown, expired, used or mismatched state parameters.
+	ErrInvalidLoginState = errors.New("invalid or expired login state, please start again")
+	// ErrSocialLoginFailed is returned when the provider rejects the code or its ID token is invalid.
+	ErrSocialLoginFailed = errors.New("sign-in with the identity provider failed")
+	// ErrIdentityEmailMissing is returned when a new identity comes without an e-mail address.
+	ErrIdentityEmailMissing = errors.New("the identity provider did not share an e-mail address")
+	// ErrIdentityEmailUnverified is returned when an identity claims the address of an existing
+	// account but the provider has not verified it, so the account cannot be linked safely.
+	ErrIdentityEmailUnverified = errors.New("an account with this e-mail address exists; the identity provider has not verified the address")
+	// ErrAccountEmailUnverified is returned when an identity claims the address of an existing
+	// account whose owner never verified it. Whoever registered that account may not own the
+	// address, so it is not linked; signing in with the password and verifying it comes first.
+	ErrAccountEmailUnverified = errors.New("an account with this e-mail address exists but its address is not verified; sign in with the password and verify it first")
+)
+
+// SocialLoginService signs users in through external identity providers. The first sign-in
+// with a provider account links it to the user with the same e-mail address, when both the
+// provider and the account have verified it, or creates a new user.
+type SocialLoginService struct {
+	users      repository.UserRepository
+	identities repository.UserIdentityRepository
+	states     repository.OIDCLoginStateRepository
+	auth       *AuthService
+	providers  map[string]oidc.Provider
+}
+
+// NewSocialLoginService wires the repositories, the auth service that finishes logins and the
+// configured identity providers.
+func NewSocialLoginService(users repository.UserRepository, identities repository.UserIdentityRepository, states repository.OIDCLoginStateRepository, auth *AuthService, providers ...oidc.Provider) *SocialLoginService {
+	s := &SocialLoginService{users: users, identities: identities, states: states, auth: auth, providers: make(map[string]oidc.Provider, len(providers))}
+	for _, p := range providers {
+		s.providers[p.Name()] = p
+	}
+	return s
+}
+
+// Providers returns the names of the configured identity providers in alphabetical order.
+func (s *SocialLoginService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Start begins a sign-in with the provider and returns the URL to send the user to. The
+// state, nonce and PKCE code verifier are kept server-side until Finish.
+func (s *SocialLoginService) Start(ctx context.Context, provider string) (authURL string, err error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return "", ErrUnknownProvider
+	}
+	state, nonce, verifier := newSecret(), newSecret(), newSecret()
+	err = s.states.Create(ctx, &models.OIDCLoginState{
+		StateHash:    hashToken(state),
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(SocialLoginStateTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+	return p.AuthCodeURL(ctx, state, nonce, oidc.PKCEChallenge(verifier))
+}
+
+// Finish completes a sign-in with the authorization code and state the provider redirected
+// back with. Like a password login, the result is a token pair or an MFA challenge.
+func (s *SocialLoginService) Finish(ctx context.Context, provider, code, state string, device Device) (*LoginResult, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	stored, err := s.states.GetByHash(ctx, hashToken(state))
+	now := time.Now()
+	if err != nil || stored.Provider != provider || stored.UsedAt != nil || now.After(stored.ExpiresAt) {
+		return nil, ErrInvalidLoginState
+	}
+	if ok, err := s.states.MarkUsed(ctx, stored.ID, now); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, ErrInvalidLoginState
+	}
+
+	identity, err := p.Exchange(ctx, code, stored.CodeVerifier, stored.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSocialLoginFailed, err)
+	}
+	user, err := s.userFor(ctx, provider, identity)
+	if err != nil {
+		return nil, err
+	}
+	return s.auth.completeLogin(ctx, user, device)
+}
+
+// userFor returns the user an identity belongs to, linking or creating one on first sign-in.
+func (s *SocialLoginService) userFor(ctx context.Context, provider string, identity *oidc.Identity) (*models.User, error) {
+	if link, err := s.identities.GetByProviderSubject(ctx, provider, identity.Subject); err == nil {
+		return s.users.GetByID(ctx, link.UserID)
+	}
+	if identity.Email == "" {
+		return nil, ErrIdentityEmailMissing
+	}
+
+	user, err := s.users.GetByEmail(ctx, identity.Email)
+	if err == nil {
+		if !identity.EmailVerified {
+			return nil, ErrIdentityEmailUnverified
+		}
+		if !user.EmailVerified() {
+			return nil, ErrAccountEmailUnverified
+		}
+	} else if user, err = s.createUser(ctx, identity); err != nil {
+		return nil, err
+	}
+
+	err = s.identities.Create(ctx, &models.UserIdentity{
+		UserID:   user.ID,
+		Provider: provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// createUser creates a user without a password for a new identity. Changes that ask for the
+// password fail with ErrPasswordNotSet until one is set through PasswordResetService.SendLink.
+func (s *SocialLoginService) createUser(ctx context.Context, identity *oidc.Identity) (*models.User, error) {
+	name := identity.Name
+	if name == "" {
+		name, _, _ = strings.Cut(identity.Email, "@")
+	}
+	user := &models.User{Name: name, Email: identity.Email, Role: models.RoleUser}
+	if identity.EmailVerified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	if err := s.users.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	if !user.EmailVerified() && s.auth.verifier != nil {
+		if err := s.auth.verifier.Send(ctx, user); err != nil {
+			log.Printf("sending verification e-mail to user %d: %v", user.ID, err)
+		}
+	}
+	return user, nil
+}
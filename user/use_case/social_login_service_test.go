@@ -0,0 +1,236 @@
+package use_case_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/VibeTeam/fitness-tracker-backend/user/auth"
+	"github.com/VibeTeam/fitness-tracker-backend/user/mailer"
+	"github.com/VibeTeam/fitness-tracker-backend/user/models"
+	"github.com/VibeTeam/fitness-tracker-backend/user/oidc"
+	"github.com/VibeTeam/fitness-tracker-backend/user/use_case"
+)
+
+/* -------------------------------------------------------------------------- */
+/* In‑memory identity and login state repositories                            */
+/* -------------------------------------------------------------------------- */
+
+type inMemIdentityRepo struct {
+	mu         sync.Mutex
+	identities []*models.UserIdentity
+}
+
+func (r *inMemIdentityRepo) Create(_ context.Context, i *models.UserIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i.ID = uint(len(r.identities) + 1)
+	cp := *i
+	r.identities = append(r.identities, &cp)
+	return nil
+}
+
+func (r *inMemIdentityRepo) GetByProviderSubject(_ context.Context, provider, subject string) (*models.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, i := range r.identities {
+		if i.Provider == provider && i.Subject == subject {
+			cp := *i
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *inMemIdentityRepo) DeleteByUser(_ context.Context, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := r.identities[:0]
+	for _, i := range r.identities {
+		if i.UserID != userID {
+			kept = append(kept, i)
+		}
+	}
+	r.identities = kept
+	return nil
+}
+
+type inMemLoginStateRepo struct {
+	mu     sync.Mutex
+	states []*models.OIDCLoginState
+}
+
+func (r *inMemLoginStateRepo) Create(_ context.Context, s *models.OIDCLoginState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s.ID = uint(len(r.states) + 1)
+	cp := *s
+	r.states = append(r.states, &cp)
+	return nil
+}
+
+func (r *inMemLoginStateRepo) GetByHash(_ context.Context, hash string) (*models.OIDCLoginState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.states {
+		if s.StateHash == hash {
+			cp := *s
+			return &cp, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *inMemLoginStateRepo) MarkUsed(_ context.Context, id uint, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.states[id-1].UsedAt != nil {
+		return false, nil
+	}
+	r.states[id-1].UsedAt = &at
+	return true, nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+// fakeProvider hands out the configured identity when the code matches and the PKCE verifier
+// and nonce are the ones bound to the authorization URL.
+type fakeProvider struct {
+	name      string
+	identity  oidc.Identity
+	challenge string
+	nonce     string
+}
+
+func (p *fakeProvider) Name() string { return p.name }
+
+func (p *fakeProvider) AuthCodeURL(_ context.Context, state, nonce, challenge string) (string, error) {
+	p.challenge, p.nonce = challenge, nonce
+	return "https://idp.example/authorize?state=" + url.QueryEscape(state), nil
+}
+
+func (p *fakeProvider) Exchange(_ context.Context, code, verifier, nonce string) (*oidc.Identity, error) {
+	if code != "good-code" || oidc.PKCEChallenge(verifier) != p.challenge || nonce != p.nonce {
+		return nil, errors.New("invalid_grant")
+	}
+	id := p.identity
+	return &id, nil
+}
+
+// startState begins a social login and returns the state parameter from the authorization URL.
+func startState(t *testing.T, svc *use_case.SocialLoginService, provider string) string {
+	authURL, err := svc.Start(context.Background(), provider)
+	require.NoError(t, err)
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	return u.Query().Get("state")
+}
+
+func newSocialLogin(p *fakeProvider) (*use_case.SocialLoginService, *use_case.AuthService, *inMemUserRepo) {
+	repo := newRepo()
+	tm := auth.NewManager("a", "b", time.Minute, time.Hour)
+	authSvc := use_case.NewAuthService(repo, &inMemRefreshRepo{}, &inMemSessionRepo{}, tm)
+	return use_case.NewSocialLoginService(repo, &inMemIdentityRepo{}, &inMemLoginStateRepo{}, authSvc, p), authSvc, repo
+}
+
+func TestSocialLoginCreatesAndReusesUser(t *testing.T) {
+	p := &fakeProvider{name: "google", identity: oidc.Identity{Subject: "g-1", Email: "sam@b.c", EmailVerified: true, Name: "Sam"}}
+	svc, _, repo := newSocialLogin(p)
+	ctx := context.Background()
+
+	require.Equal(t, []string{"google"}, svc.Providers())
+	_, err := svc.Start(ctx, "myspace")
+	require.ErrorIs(t, err, use_case.ErrUnknownProvider)
+
+	state := startState(t, svc, "google")
+	_, err = svc.Finish(ctx, "google", "bad-code", state, use_case.Device{})
+	require.ErrorIs(t, err, use_case.ErrSocialLoginFailed)
+	_, err = svc.Finish(ctx, "google", "good-code", state, use_case.Device{})
+	require.ErrorIs(t, err, use_case.ErrInvalidLoginState, "a state is single-use")
+
+	res, err := svc.Finish(ctx, "google", "good-code", startState(t, svc, "google"), use_case.Device{})
+	require.NoError(t, err)
+	require.NotEmpty(t, res.AccessToken)
+	u, err := repo.GetByEmail(ctx, "sam@b.c")
+	require.NoError(t, err)
+	require.Equal(t, "Sam", u.Name)
+	require.True(t, u.EmailVerified())
+
+	// The provider account stays linked even if the address changes at the provider.
+	p.identity.Email = "sam@new.example"
+	_, err = svc.Finish(ctx, "google", "good-code", startState(t, svc, "google"), use_case.Device{})
+	require.NoError(t, err)
+	n, _ := repo.Count(ctx)
+	require.Equal(t, 1, n)
+}
+
+func TestSocialLoginLinksExistingAccount(t *testing.T) {
+	p := &fakeProvider{name: "apple", identity: oidc.Identity{Subject: "a-1", Email: "kim@b.c"}}
+	svc, authSvc, repo := newSocialLogin(p)
+	ctx := context.Background()
+
+	// The unverified local account was possibly registered by someone else.
+	_, squatter, err := authSvc.Register(ctx, "kim@b.c", "pwd")
+	require.NoError(t, err)
+
+	_, err = svc.Finish(ctx, "apple", "good-code", startState(t, svc, "apple"), use_case.Device{})
+	require.ErrorIs(t, err, use_case.ErrIdentityEmailUnverified)
+
+	// Even a verified identity does not take over an account whose address was never verified.
+	p.identity.EmailVerified = true
+	_, err = svc.Finish(ctx, "apple", "good-code", startState(t, svc, "apple"), use_case.Device{})
+	require.ErrorIs(t, err, use_case.ErrAccountEmailUnverified)
+	u, _ := repo.GetByEmail(ctx, "kim@b.c")
+	require.NoError(t, bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte("pwd")), "the account is left alone")
+	_, _, err = authSvc.Refresh(ctx, squatter)
+	require.NoError(t, err)
+
+	// Once the owner has verified the address, the identity is linked.
+	now := time.Now()
+	u.EmailVerifiedAt = &now
+	require.NoError(t, repo.Update(ctx, u))
+	_, err = svc.Finish(ctx, "apple", "good-code", startState(t, svc, "apple"), use_case.Device{})
+	require.NoError(t, err)
+	n, _ := repo.Count(ctx)
+	require.Equal(t, 1, n)
+}
+
+func TestSocialLoginUserSetsPasswordThroughLink(t *testing.T) {
+	p := &fakeProvider{name: "google", identity: oidc.Identity{Subject: "g-2", Email: "pat@b.c", EmailVerified: true}}
+	svc, authSvc, repo := newSocialLogin(p)
+	accounts := use_case.NewAccountService(repo, authSvc, nil)
+	var mail bytes.Buffer
+	resets := use_case.NewPasswordResetService(repo, &inMemResetRepo{}, authSvc, mailer.NewLog(&mail, "noreply@app.example"), "https://app.example/reset")
+	ctx := context.Background()
+
+	_, err := svc.Finish(ctx, "google", "good-code", startState(t, svc, "google"), use_case.Device{})
+	require.NoError(t, err)
+	u, _ := repo.GetByEmail(ctx, "pat@b.c")
+
+	// Without a password there is nothing to confirm sensitive changes with, not even an empty one.
+	require.ErrorIs(t, accounts.DeleteSelf(ctx, u.ID, ""), use_case.ErrPasswordNotSet)
+
+	require.NoError(t, resets.SendLink(ctx, u.ID))
+	require.NoError(t, resets.Reset(ctx, lastResetToken(t, &mail), "chosen"))
+	require.NoError(t, accounts.DeleteSelf(ctx, u.ID, "chosen"))
+}
+
+func TestSocialLoginStateIsBoundToProvider(t *testing.T) {
+	p := &fakeProvider{name: "google", identity: oidc.Identity{Subject: "g-1", Email: "x@b.c", EmailVerified: true}}
+	other := &fakeProvider{name: "corp"}
+	repo := newRepo()
+	tm := auth.NewManager("a", "b", time.Minute, time.Hour)
+	authSvc := use_case.NewAuthService(repo, &inMemRefreshRepo{}, &inMemSessionRepo{}, tm)
+	svc := use_case.NewSocialLoginService(repo, &inMemIdentityRepo{}, &inMemLoginStateRepo{}, authSvc, p, other)
+
+	state := startState(t, svc, "google")
+	_, err := svc.Finish(context.Background(), "corp", "good-code", state, use_case.Device{})
+	require.ErrorIs(t, err, use_case.ErrInvalidLoginState)
+}
@@ -0,0 +1,120 @@
+// Package analytics exposes aggregate training statistics over the workout log.
+package analytics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/middleware"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
+)
+
+// dateLayout is the calendar date format of the from/to query parameters.
+const dateLayout = "2006-01-02"
+
+// defaultWindow is the range reported when from is omitted.
+const defaultWindow = 12 * 7 * 24 * time.Hour
+
+// Handler serves the analytics endpoints.
+type Handler struct {
+	repo repository.AnalyticsRepository
+}
+
+func NewHandler(repo repository.AnalyticsRepository) *Handler {
+	return &Handler{repo: repo}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.Engine, auth gin.HandlerFunc) {
+	a := r.Group("/analytics")
+	a.Use(auth, middleware.RequireScope(middleware.ScopeSessionsRead))
+	{
+		a.GET("/volume", h.volume)
+	}
+}
+
+type volumeResponse struct {
+	From    string                 `json:"from"`
+	To      string                 `json:"to"`
+	GroupBy string                 `json:"group_by"`
+	By      string                 `json:"by"`
+	Items   []repository.VolumeRow `json:"items"`
+}
+
+// training volume
+// @Summary      Training volume per period
+// @Description  Sums completed sets, reps and tonnage (kg) per week or month, split by muscle group or workout type
+// @Tags         analytics
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        from      query     string  false  "Start date YYYY-MM-DD, inclusive (defaults to 12 weeks ago)"
+// @Param        to        query     string  false  "End date YYYY-MM-DD, inclusive (defaults to today)"
+// @Param        group_by  query     string  false  "Period: week (default) or month"
+// @Param        by        query     string  false  "Grouping: muscle_group (default) or workout_type"
+// @Success      200       {object}  volumeResponse
+// @Failure      400       {object}  gin.H
+// @Failure      500       {object}  gin.H
+// @Router       /analytics/volume [get]
+func (h *Handler) volume(c *gin.Context) {
+	uid, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+
+	period := c.DefaultQuery("group_by", repository.PeriodWeek)
+	if period != repository.PeriodWeek && period != repository.PeriodMonth {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "group_by must be week or month"})
+		return
+	}
+	by := c.DefaultQuery("by", repository.GroupByMuscleGroup)
+	if by != repository.GroupByMuscleGroup && by != repository.GroupByWorkoutType {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "by must be muscle_group or workout_type"})
+		return
+	}
+
+	y, m, d := time.Now().Date()
+	to := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse(dateLayout, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be YYYY-MM-DD"})
+			return
+		}
+		to = t
+	}
+	from := to.Add(-defaultWindow)
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse(dateLayout, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be YYYY-MM-DD"})
+			return
+		}
+		from = t
+	}
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must not be before from"})
+		return
+	}
+
+	rows, err := h.repo.Volume(c.Request.Context(), repository.VolumeQuery{
+		UserID:  uid,
+		From:    from,
+		To:      to.AddDate(0, 0, 1),
+		Period:  period,
+		GroupBy: by,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, volumeResponse{
+		From:    from.Format(dateLayout),
+		To:      to.Format(dateLayout),
+		GroupBy: period,
+		By:      by,
+		Items:   rows,
+	})
+}
@@ -0,0 +1,144 @@
+package analytics_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/VibeTeam/fitness-tracker-backend/workout/analytics"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/repository/gormrepository"
+)
+
+// seedOnce guards the fixture: the shared in-memory DB outlives a single test.
+var seedOnce sync.Once
+
+// testRouter serves the analytics routes over two weeks of training for user 1.
+func testRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	seedOnce.Do(func() { seed(t, db) })
+
+	h := analytics.NewHandler(gormrepository.NewAnalyticsRepository(db))
+	r := gin.New()
+	h.RegisterRoutes(r, func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Next()
+	})
+	return r
+}
+
+func seed(t *testing.T, db *gorm.DB) {
+	require.NoError(t, db.AutoMigrate(&models.MuscleGroup{}, &models.WorkoutType{},
+		&models.WorkoutSession{}, &models.WorkoutExercise{}, &models.WorkoutDetail{}, &models.WorkoutSet{}))
+
+	chest := models.MuscleGroup{Name: "Chest"}
+	legs := models.MuscleGroup{Name: "Legs"}
+	require.NoError(t, db.Create(&chest).Error)
+	require.NoError(t, db.Create(&legs).Error)
+	bench := models.WorkoutType{Name: "Bench", MuscleGroupID: chest.ID}
+	squat := models.WorkoutType{Name: "Squat", MuscleGroupID: legs.ID}
+	require.NoError(t, db.Create(&bench).Error)
+	require.NoError(t, db.Create(&squat).Error)
+
+	sets := func(n, reps int, weight float64, unit string) []models.WorkoutSet {
+		out := make([]models.WorkoutSet, n)
+		for i := range out {
+			out[i] = models.WorkoutSet{SetIndex: i + 1, Reps: reps, Weight: weight, Unit: unit, Completed: true}
+		}
+		return out
+	}
+	sessions := gormrepository.NewWorkoutSessionRepository(db)
+	ctx := context.Background()
+	for _, s := range []*models.WorkoutSession{
+		// Wednesday 2024-03-06: bench and squat in one session
+		{UserID: 1, WorkoutTypeID: bench.ID, Datetime: time.Date(2024, 3, 6, 18, 0, 0, 0, time.UTC), Exercises: []models.WorkoutExercise{
+			{WorkoutTypeID: bench.ID, Position: 1, Sets: sets(3, 5, 100, models.UnitKilograms)},
+			{WorkoutTypeID: squat.ID, Position: 2, Sets: sets(2, 5, 220.462262, models.UnitPounds)},
+		}},
+		// Sunday 2024-03-10 still belongs to the week of the 4th
+		{UserID: 1, WorkoutTypeID: bench.ID, Datetime: time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC), Sets: sets(1, 10, 60, models.UnitKilograms)},
+		// Monday 2024-03-11 opens the next week
+		{UserID: 1, WorkoutTypeID: squat.ID, Datetime: time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC), Sets: sets(1, 3, 120, models.UnitKilograms)},
+		// another user is never counted
+		{UserID: 2, WorkoutTypeID: squat.ID, Datetime: time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC), Sets: sets(5, 5, 200, models.UnitKilograms)},
+	} {
+		require.NoError(t, sessions.Create(ctx, s))
+	}
+	// planned sets are not volume
+	planned := &models.WorkoutSession{UserID: 1, WorkoutTypeID: bench.ID, Datetime: time.Date(2024, 3, 12, 9, 0, 0, 0, time.UTC),
+		Sets: []models.WorkoutSet{{SetIndex: 1, Reps: 5, Weight: 100, Unit: models.UnitKilograms}}}
+	require.NoError(t, sessions.Create(ctx, planned))
+}
+
+type volumeResponse struct {
+	Items []repository.VolumeRow `json:"items"`
+}
+
+func get(t *testing.T, r *gin.Engine, target string) (int, volumeResponse) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, target, nil)
+	r.ServeHTTP(w, req)
+	var resp volumeResponse
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	}
+	return w.Code, resp
+}
+
+func TestVolumeByMuscleGroupPerWeek(t *testing.T) {
+	r := testRouter(t)
+
+	code, resp := get(t, r, "/analytics/volume?from=2024-03-01&to=2024-03-31")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, resp.Items, 3)
+
+	chest := resp.Items[0]
+	require.Equal(t, "2024-03-04", chest.Period)
+	require.Equal(t, "Chest", chest.GroupName)
+	require.Equal(t, 4, chest.Sets)
+	require.Equal(t, 25, chest.Reps)
+	require.InDelta(t, 2100, chest.Tonnage, 1e-6)
+
+	legs := resp.Items[1]
+	require.Equal(t, "2024-03-04", legs.Period)
+	require.Equal(t, "Legs", legs.GroupName)
+	require.InDelta(t, 1000, legs.Tonnage, 1e-3) // 2 x 5 x 100 kg logged in pounds
+
+	require.Equal(t, "2024-03-11", resp.Items[2].Period)
+	require.InDelta(t, 360, resp.Items[2].Tonnage, 1e-6)
+}
+
+func TestVolumeByWorkoutTypePerMonth(t *testing.T) {
+	r := testRouter(t)
+
+	code, resp := get(t, r, "/analytics/volume?from=2024-03-01&to=2024-03-31&group_by=month&by=workout_type")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, resp.Items, 2)
+	require.Equal(t, "2024-03-01", resp.Items[0].Period)
+	require.Equal(t, "Bench", resp.Items[0].GroupName)
+	require.Equal(t, "Squat", resp.Items[1].GroupName)
+	require.Equal(t, 3, resp.Items[1].Sets)
+
+	// the range is inclusive of the to date
+	code, resp = get(t, r, "/analytics/volume?from=2024-03-11&to=2024-03-11")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, resp.Items, 1)
+
+	code, _ = get(t, r, "/analytics/volume?group_by=day")
+	require.Equal(t, http.StatusBadRequest, code)
+	code, _ = get(t, r, "/analytics/volume?from=2024-03-11&to=2024-03-01")
+	require.Equal(t, http.StatusBadRequest, code)
+}
@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/VibeTeam/fitness-tracker-backend/llm/coach"
+	"github.com/VibeTeam/fitness-tracker-backend/llm/provider"
+	"github.com/VibeTeam/fitness-tracker-backend/shared/middleware"
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/use_case"
+)
+
+// maxThreadTitle is the length, in characters, of titles taken from a thread's first message.
+const maxThreadTitle = 60
+
+// CoachHandler handles conversations with the AI coach.
+type CoachHandler struct {
+	repo        repository.CoachRepository
+	sessionRepo repository.WorkoutSessionRepository
+	coach       *coach.Coach
+	oneRM       *use_case.OneRepMaxService
+}
+
+func NewCoachHandler(repo repository.CoachRepository, sessionRepo repository.WorkoutSessionRepository, c *coach.Coach, oneRM *use_case.OneRepMaxService) *CoachHandler {
+	return &CoachHandler{repo: repo, sessionRepo: sessionRepo, coach: c, oneRM: oneRM}
+}
+
+func (h *CoachHandler) RegisterRoutes(r *gin.Engine, auth gin.HandlerFunc) {
+	g := r.Group("/coach/threads")
+	g.Use(auth, middleware.RequireAccess(middleware.ScopeCoachRead, middleware.ScopeCoachWrite))
+	{
+		g.POST("", h.createThread)
+		g.GET("", h.listThreads)
+		g.GET("/:id", h.getThread)
+		g.DELETE("/:id", h.deleteThread)
+		g.POST("/:id/messages", h.postMessage)
+	}
+}
+
+type coachThreadRequest struct {
+	// Title defaults to the start of the first message.
+	Title string `json:"title" binding:"max=200"`
+}
+
+type coachMessageRequest struct {
+	Content string `json:"content" binding:"required,max=4000"`
+}
+
+// create thread
+// @Summary      Start a conversation with the AI coach
+// @Tags         coach
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      coachThreadRequest  false  "Title"
+// @Success      201      {object}  models.CoachThread
+// @Failure      400      {object}  gin.H
+// @Failure      500      {object}  gin.H
+// @Router       /coach/threads [post]
+func (h *CoachHandler) createThread(c *gin.Context) {
+	var req coachThreadRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	uid, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+	thread := &models.CoachThread{UserID: uid, Title: strings.TrimSpace(req.Title)}
+	if err := h.repo.CreateThread(c.Request.Context(), thread); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, thread)
+}
+
+// list threads
+// @Summary      List coach conversations of the user
+// @Description  Most recently active first, without messages
+// @Tags         coach
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        limit   query     int     false  "Page size (default 100, max 500)"
+// @Param        cursor  query     string  false  "next_cursor of the previous page"
+// @Success      200     {object}  pagination.Page[models.CoachThread]
+// @Failure      400     {object}  gin.H
+// @Router       /coach/threads [get]
+func (h *CoachHandler) listThreads(c *gin.Context) {
+	uid, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+	p, err := pagination.Parse(c.Query("limit"), c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	threads, err := h.repo.ListThreadsByUser(c.Request.Context(), uid, p.Limit+1, p.After)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pagination.NewPage(threads, p, func(t *models.CoachThread) pagination.Cursor {
+		return pagination.Cursor{Time: t.UpdatedAt, ID: t.ID}
+	}))
+}
+
+// get thread
+// @Summary      Get coach conversation with its messages
+// @Tags         coach
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        id   path      int  true  "CoachThread ID"
+// @Success      200  {object}  models.CoachThread
+// @Failure      400  {object}  gin.H
+// @Failure      404  {object}  gin.H
+// @Router       /coach/threads/{id} [get]
+func (h *CoachHandler) getThread(c *gin.Context) {
+	thread, ok := h.ownedThread(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, thread)
+}
+
+// delete thread
+// @Summary      Delete coach conversation
+// @Tags         coach
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id   path      int  true  "CoachThread ID"
+// @Success      204  {string}  string  "No Content"
+// @Failure      400  {object}  gin.H
+// @Failure      404  {object}  gin.H
+// @Router       /coach/threads/{id} [delete]
+func (h *CoachHandler) deleteThread(c *gin.Context) {
+	thread, ok := h.ownedThread(c)
+	if !ok {
+		return
+	}
+	if err := h.repo.DeleteThread(c.Request.Context(), thread.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// post message
+// @Summary      Ask the AI coach
+// @Description  Answers with the coach's reply, generated from the conversation and the user's recent sessions. The oldest
+// @Description  messages are left out once the conversation outgrows the model's context window. The message and the reply
+// @Description  are stored together once the reply is complete; nothing is stored when it fails or the client disconnects.
+// @Description  With Accept: text/event-stream the reply is streamed as "chunk" events with {"text"}, followed by a "done"
+// @Description  event with the stored reply or an "error" event with {"error"}.
+// @Tags         coach
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Produce      text/event-stream
+// @Param        id       path      int                  true  "CoachThread ID"
+// @Param        payload  body      coachMessageRequest  true  "Message"
+// @Success      201      {object}  models.CoachMessage
+// @Failure      400      {object}  gin.H
+// @Failure      404      {object}  gin.H
+// @Failure      500      {object}  gin.H
+// @Router       /coach/threads/{id}/messages [post]
+func (h *CoachHandler) postMessage(c *gin.Context) {
+	thread, ok := h.ownedThread(c)
+	if !ok {
+		return
+	}
+	var req coachMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+	training, err := trainingHistory(ctx, h.sessionRepo, h.oneRM, thread.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if training == "" {
+		training = "no workouts logged yet"
+	}
+
+	// the question is stored together with the answer, so a failed reply leaves no trace
+	question := &models.CoachMessage{ThreadID: thread.ID, Role: models.CoachRoleUser, Content: req.Content, CreatedAt: time.Now()}
+	conversation := make([]provider.Message, 0, len(thread.Messages)+1)
+	for _, m := range append(thread.Messages, *question) {
+		conversation = append(conversation, provider.Message{Role: m.Role, Content: m.Content})
+	}
+
+	stream := acceptsEventStream(c)
+	var onChunk func(string) error
+	if stream {
+		startEventStream(c)
+		onChunk = func(text string) error { return sendChunk(c, text) }
+	}
+	reply, err := h.coach.Reply(ctx, training, conversation, onChunk)
+	var answer *models.CoachMessage
+	if err == nil {
+		answer = &models.CoachMessage{ThreadID: thread.ID, Role: models.CoachRoleAssistant, Content: reply}
+		err = h.repo.AddMessages(ctx, question, answer)
+	}
+	if err == nil && thread.Title == "" {
+		thread.Title = threadTitle(req.Content)
+		err = h.repo.UpdateThread(ctx, thread)
+	}
+	switch {
+	case err != nil && stream:
+		sendError(c, err)
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	case stream:
+		sendEvent(c, "done", answer)
+	default:
+		c.JSON(http.StatusCreated, answer)
+	}
+}
+
+// ownedThread loads the thread from the :id path parameter and verifies it belongs to the caller.
+func (h *CoachHandler) ownedThread(c *gin.Context) (*models.CoachThread, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return nil, false
+	}
+	thread, err := h.repo.GetThread(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	uid, _ := middleware.UserID(c)
+	if thread.UserID != uid {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return nil, false
+	}
+	return thread, true
+}
+
+// threadTitle shortens a thread's first message to a title.
+func threadTitle(content string) string {
+	title := strings.Join(strings.Fields(content), " ")
+	if utf8.RuneCountInString(title) <= maxThreadTitle {
+		return title
+	}
+	return string([]rune(title)[:maxThreadTitle-1]) + "…"
+}
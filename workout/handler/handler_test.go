@@ -2,10 +2,13 @@ package handler_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,9 +17,15 @@ import (
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 
+	"github.com/VibeTeam/fitness-tracker-backend/llm/coach"
+	"github.com/VibeTeam/fitness-tracker-backend/llm/provider"
+	"github.com/VibeTeam/fitness-tracker-backend/llm/suggester"
+	"github.com/VibeTeam/fitness-tracker-backend/shared/middleware"
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
 	"github.com/VibeTeam/fitness-tracker-backend/workout/handler"
 	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
 	"github.com/VibeTeam/fitness-tracker-backend/workout/repository/gormrepository"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/use_case"
 )
 
 // -----------------------------------------------------------------------------
@@ -33,18 +42,32 @@ func testRouter(t *testing.T) (*gin.Engine, *gorm.DB) {
 
 	// migrate the minimal set of tables we touch
 	require.NoError(t, db.AutoMigrate(&models.MuscleGroup{}, &models.WorkoutType{},
-		&models.WorkoutSession{}, &models.WorkoutDetail{}))
+		&models.WorkoutSession{}, &models.WorkoutExercise{}, &models.WorkoutDetail{}, &models.WorkoutSet{},
+		&models.WorkoutTemplate{}, &models.WorkoutTemplateExercise{},
+		&models.Program{}, &models.ProgramWeek{}, &models.ProgramDay{}, &models.ProgramPrescription{}, &models.ProgramEnrollment{},
+		&models.PersonalRecord{}, &models.CoachThread{}, &models.CoachMessage{}))
 
 	// repositories
 	mgRepo := gormrepository.NewMuscleGroupRepository(db)
 	wtRepo := gormrepository.NewWorkoutTypeRepository(db)
 	wsRepo := gormrepository.NewWorkoutSessionRepository(db)
 	wdRepo := gormrepository.NewWorkoutDetailRepository(db)
+	setRepo := gormrepository.NewWorkoutSetRepository(db)
+	tplRepo := gormrepository.NewWorkoutTemplateRepository(db)
+	programRepo := gormrepository.NewProgramRepository(db)
+	enrollmentRepo := gormrepository.NewProgramEnrollmentRepository(db)
+	recordService := use_case.NewRecordService(gormrepository.NewPersonalRecordRepository(db))
+	oneRMService := use_case.NewOneRepMaxService(wsRepo, use_case.FormulaEpley)
 
 	// handlers
 	mgHandler := handler.NewMuscleGroupHandler(mgRepo)
 	wtHandler := handler.NewWorkoutTypeHandler(wtRepo)
-	wsHandler := handler.NewWorkoutSessionHandler(wsRepo, wdRepo)
+	wsHandler := handler.NewWorkoutSessionHandler(wsRepo, wdRepo, recordService)
+	setHandler := handler.NewWorkoutSetHandler(wsRepo, setRepo, recordService)
+	tplHandler := handler.NewWorkoutTemplateHandler(tplRepo, wsRepo)
+	programHandler := handler.NewProgramHandler(programRepo, enrollmentRepo, wsRepo, oneRMService)
+	recordHandler := handler.NewRecordHandler(recordService)
+	oneRMHandler := handler.NewOneRepMaxHandler(oneRMService)
 
 	// stub auth: inject a fixed authenticated user ID for all requests so that
 	// endpoints requiring authorization (e.g., workout-session CRUD) succeed.
@@ -58,6 +81,11 @@ func testRouter(t *testing.T) (*gin.Engine, *gorm.DB) {
 	mgHandler.RegisterRoutes(r, noAuth)
 	wtHandler.RegisterRoutes(r, noAuth)
 	wsHandler.RegisterRoutes(r, noAuth)
+	setHandler.RegisterRoutes(r, noAuth)
+	tplHandler.RegisterRoutes(r, noAuth)
+	programHandler.RegisterRoutes(r, noAuth)
+	recordHandler.RegisterRoutes(r, noAuth)
+	oneRMHandler.RegisterRoutes(r, noAuth)
 
 	return r, db
 }
@@ -68,6 +96,51 @@ func asJSON(t *testing.T, v any) *bytes.Buffer {
 	return bytes.NewBuffer(b)
 }
 
+// do performs a request against r, sending body as JSON when it is not nil.
+func do(t *testing.T, r *gin.Engine, method, target string, body any) *httptest.ResponseRecorder {
+	var req *http.Request
+	if body != nil {
+		req, _ = http.NewRequest(method, target, asJSON(t, body))
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		req, _ = http.NewRequest(method, target, nil)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// decode unmarshals a recorded JSON response into v.
+func decode(t *testing.T, w *httptest.ResponseRecorder, v any) {
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), v))
+}
+
+// newSession creates a muscle group, workout type and session through the API.
+func newSession(t *testing.T, r *gin.Engine, typeName string) models.WorkoutSession {
+	var mg models.MuscleGroup
+	w := do(t, r, http.MethodPost, "/muscle-groups", map[string]any{"name": typeName + " group"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	decode(t, w, &mg)
+
+	var wt models.WorkoutType
+	w = do(t, r, http.MethodPost, "/workout-types", map[string]any{"name": typeName, "muscle_group_id": mg.ID})
+	require.Equal(t, http.StatusCreated, w.Code)
+	decode(t, w, &wt)
+
+	var ws models.WorkoutSession
+	w = do(t, r, http.MethodPost, "/workout-sessions", map[string]any{"workout_type_id": wt.ID})
+	require.Equal(t, http.StatusCreated, w.Code)
+	decode(t, w, &ws)
+	return ws
+}
+
+// chatFunc adapts a function to provider.Provider, for models that fail or are cut off.
+type chatFunc func(ctx context.Context, messages []provider.Message, onChunk func(text string) error) (string, error)
+
+func (f chatFunc) Chat(ctx context.Context, messages []provider.Message, onChunk func(text string) error) (string, error) {
+	return f(ctx, messages, onChunk)
+}
+
 // -----------------------------------------------------------------------------
 // Muscle‑group happy‑path CRUD
 // -----------------------------------------------------------------------------
@@ -97,10 +170,11 @@ func TestMuscleGroupLifecycle(t *testing.T) {
 
 		require.Equal(t, http.StatusOK, w.Code)
 
-		var list []models.MuscleGroup
-		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
-		require.Len(t, list, 1)
-		require.Equal(t, mgResp.ID, list[0].ID)
+		var page pagination.Page[models.MuscleGroup]
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		require.Len(t, page.Items, 1)
+		require.Equal(t, mgResp.ID, page.Items[0].ID)
+		require.Empty(t, page.NextCursor)
 	}
 
 	// DELETE
@@ -235,3 +309,1067 @@ func TestWorkoutSessionWithDetails(t *testing.T) {
 		require.Equal(t, "Reps", stored.Details[0].DetailName)
 	}
 }
+
+// -----------------------------------------------------------------------------
+// Typed sets inside a workout‑session
+// -----------------------------------------------------------------------------
+
+func TestWorkoutSessionSets(t *testing.T) {
+	r, _ := testRouter(t)
+	ws := newSession(t, r, "Bench Press")
+	base := fmt.Sprintf("/workout-sessions/%d/sets", ws.ID)
+
+	// log two sets; indexes are assigned automatically
+	var first models.WorkoutSet
+	w := do(t, r, http.MethodPost, base, map[string]any{"reps": 8, "weight": 80, "rpe": 7.5})
+	require.Equal(t, http.StatusCreated, w.Code)
+	decode(t, w, &first)
+	require.Equal(t, 1, first.SetIndex)
+	require.Equal(t, models.UnitKilograms, first.Unit)
+	require.True(t, first.Completed)
+
+	w = do(t, r, http.MethodPost, base, map[string]any{"reps": 6, "weight": 185, "unit": "lb"})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	// invalid unit is rejected
+	w = do(t, r, http.MethodPost, base, map[string]any{"reps": 6, "unit": "stone"})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	// update the first set
+	w = do(t, r, http.MethodPut, fmt.Sprintf("%s/%d", base, first.ID), map[string]any{"reps": 10, "weight": 80, "completed": false})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var page pagination.Page[models.WorkoutSet]
+	w = do(t, r, http.MethodGet, base, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	decode(t, w, &page)
+	sets := page.Items
+	require.Len(t, sets, 2)
+	require.Equal(t, 10, sets[0].Reps)
+	require.False(t, sets[0].Completed)
+	require.Equal(t, 2, sets[1].SetIndex)
+
+	// delete
+	w = do(t, r, http.MethodDelete, fmt.Sprintf("%s/%d", base, first.ID), nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+	w = do(t, r, http.MethodDelete, fmt.Sprintf("%s/%d", base, first.ID), nil)
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	// a set logged after a deletion does not reuse an index
+	var third models.WorkoutSet
+	w = do(t, r, http.MethodPost, base, map[string]any{"reps": 5, "weight": 85})
+	require.Equal(t, http.StatusCreated, w.Code)
+	decode(t, w, &third)
+	require.Equal(t, 3, third.SetIndex)
+}
+
+// -----------------------------------------------------------------------------
+// Multi‑exercise session: create, reorder, remove, finish
+// -----------------------------------------------------------------------------
+
+func TestMultiExerciseSession(t *testing.T) {
+	r, _ := testRouter(t)
+	bench := newSession(t, r, "Bench")
+	rows := newSession(t, r, "Rows")
+	start := time.Date(2025, 3, 1, 18, 0, 0, 0, time.UTC)
+
+	// one session holding two exercises with their sets
+	var ws models.WorkoutSession
+	w := do(t, r, http.MethodPost, "/workout-sessions", map[string]any{
+		"datetime": start,
+		"exercises": []map[string]any{
+			{"workout_type_id": bench.WorkoutTypeID, "sets": []map[string]any{{"reps": 5, "weight": 100}, {"reps": 5, "weight": 100}}},
+			{"workout_type_id": rows.WorkoutTypeID, "notes": "strict form"},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	decode(t, w, &ws)
+	require.Equal(t, bench.WorkoutTypeID, ws.WorkoutTypeID)
+	require.Len(t, ws.Exercises, 2)
+	require.Equal(t, 2, ws.Exercises[0].Sets[1].SetIndex)
+
+	// neither a type nor exercises
+	w = do(t, r, http.MethodPost, "/workout-sessions", map[string]any{})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	// append a third exercise and move it to the front
+	var extra models.WorkoutExercise
+	w = do(t, r, http.MethodPost, fmt.Sprintf("/workout-sessions/%d/exercises", ws.ID),
+		map[string]any{"workout_type_id": rows.WorkoutTypeID})
+	require.Equal(t, http.StatusCreated, w.Code)
+	decode(t, w, &extra)
+	require.Equal(t, 3, extra.Position)
+
+	w = do(t, r, http.MethodPut, fmt.Sprintf("/workout-sessions/%d/exercises/order", ws.ID),
+		map[string]any{"exercise_ids": []uint{extra.ID, ws.Exercises[0].ID, ws.Exercises[1].ID}})
+	require.Equal(t, http.StatusOK, w.Code)
+	var reordered models.WorkoutSession
+	decode(t, w, &reordered)
+	require.Equal(t, extra.ID, reordered.Exercises[0].ID)
+	require.Equal(t, rows.WorkoutTypeID, reordered.WorkoutTypeID)
+
+	w = do(t, r, http.MethodPut, fmt.Sprintf("/workout-sessions/%d/exercises/order", ws.ID),
+		map[string]any{"exercise_ids": []uint{extra.ID}})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	// remove the bench exercise with its sets
+	w = do(t, r, http.MethodDelete, fmt.Sprintf("/workout-sessions/%d/exercises/%d", ws.ID, ws.Exercises[0].ID), nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+	w = do(t, r, http.MethodDelete, fmt.Sprintf("/workout-sessions/%d/exercises/%d", ws.ID, ws.Exercises[0].ID), nil)
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	// finish the session
+	w = do(t, r, http.MethodPut, fmt.Sprintf("/workout-sessions/%d", ws.ID),
+		map[string]any{"ended_at": start.Add(75 * time.Minute)})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var stored models.WorkoutSession
+	w = do(t, r, http.MethodGet, fmt.Sprintf("/workout-sessions/%d", ws.ID), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	decode(t, w, &stored)
+	require.Len(t, stored.Exercises, 2)
+	require.Empty(t, stored.Sets)
+	require.Equal(t, 75*60, stored.DurationSeconds)
+}
+
+// -----------------------------------------------------------------------------
+// Workout templates: CRUD and starting a session from a template
+// -----------------------------------------------------------------------------
+
+func TestWorkoutTemplateStart(t *testing.T) {
+	r, db := testRouter(t)
+	bench := newSession(t, r, "Incline Bench")
+	dips := newSession(t, r, "Dips")
+
+	var tpl models.WorkoutTemplate
+	w := do(t, r, http.MethodPost, "/workout-templates", map[string]any{
+		"name": "Push Day",
+		"exercises": []map[string]any{
+			{"workout_type_id": bench.WorkoutTypeID, "target_sets": 3, "target_reps": 8, "target_weight": 60},
+			{"workout_type_id": dips.WorkoutTypeID, "target_sets": 2, "target_reps": 12},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	decode(t, w, &tpl)
+	require.Len(t, tpl.Exercises, 2)
+	require.Equal(t, "Incline Bench", tpl.Exercises[0].WorkoutType.Name)
+
+	// a template needs at least one exercise
+	w = do(t, r, http.MethodPost, "/workout-templates", map[string]any{"name": "Empty"})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	// and a sane number of sets, since starting it creates them all
+	w = do(t, r, http.MethodPost, "/workout-templates", map[string]any{
+		"name":      "Endless",
+		"exercises": []map[string]any{{"workout_type_id": dips.WorkoutTypeID, "target_sets": 2000000000}},
+	})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	// replace the exercise list: dips first, single set
+	w = do(t, r, http.MethodPut, fmt.Sprintf("/workout-templates/%d", tpl.ID), map[string]any{
+		"name": "Push Day",
+		"exercises": []map[string]any{
+			{"workout_type_id": dips.WorkoutTypeID, "target_sets": 1, "target_reps": 15},
+			{"workout_type_id": bench.WorkoutTypeID, "target_sets": 3, "target_reps": 8, "target_weight": 62.5},
+		},
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	decode(t, w, &tpl)
+	require.Len(t, tpl.Exercises, 2)
+	require.Equal(t, dips.WorkoutTypeID, tpl.Exercises[0].WorkoutTypeID)
+
+	// start a session from the template
+	var ws models.WorkoutSession
+	w = do(t, r, http.MethodPost, fmt.Sprintf("/workout-templates/%d/start", tpl.ID), nil)
+	require.Equal(t, http.StatusCreated, w.Code)
+	decode(t, w, &ws)
+	require.Equal(t, uint(1), ws.UserID)
+	require.Equal(t, dips.WorkoutTypeID, ws.WorkoutTypeID)
+	require.Len(t, ws.Exercises, 2)
+	require.Len(t, ws.Exercises[1].Sets, 3)
+	require.Equal(t, 62.5, ws.Exercises[1].Sets[0].Weight)
+	require.False(t, ws.Exercises[1].Sets[0].Completed)
+
+	// an API key that may only manage templates cannot create sessions through them
+	keyed := gin.New()
+	handler.NewWorkoutTemplateHandler(gormrepository.NewWorkoutTemplateRepository(db), gormrepository.NewWorkoutSessionRepository(db)).RegisterRoutes(keyed, func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Set("api_key_scopes", []string{middleware.ScopeTemplatesRead, middleware.ScopeTemplatesWrite})
+	})
+	w = do(t, keyed, http.MethodPost, fmt.Sprintf("/workout-templates/%d/start", tpl.ID), nil)
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	// delete
+	w = do(t, r, http.MethodDelete, fmt.Sprintf("/workout-templates/%d", tpl.ID), nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+	w = do(t, r, http.MethodGet, fmt.Sprintf("/workout-templates/%d", tpl.ID), nil)
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestProgramEnrollmentSchedule(t *testing.T) {
+	r, _ := testRouter(t)
+	squat := newSession(t, r, "Back Squat")
+
+	// two weeks, training on days 1 and 3
+	day := func(n int) map[string]any {
+		return map[string]any{"day_number": n, "name": fmt.Sprintf("Day %d", n), "prescriptions": []map[string]any{
+			{"workout_type_id": squat.WorkoutTypeID, "sets": 5, "reps": 5, "percent_1rm": 75},
+		}}
+	}
+	var program models.Program
+	w := do(t, r, http.MethodPost, "/programs", map[string]any{
+		"name": "5x5",
+		"weeks": []map[string]any{
+			{"week_number": 1, "days": []map[string]any{day(1), day(3)}},
+			{"week_number": 2, "days": []map[string]any{day(1), day(3)}},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	decode(t, w, &program)
+	require.Len(t, program.Weeks, 2)
+	require.Len(t, program.Weeks[0].Days[0].Prescriptions, 1)
+
+	var enrollment models.ProgramEnrollment
+	w = do(t, r, http.MethodPost, fmt.Sprintf("/programs/%d/enroll", program.ID), map[string]any{"start_date": "2024-03-04"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	decode(t, w, &enrollment)
+
+	var today struct {
+		Week       int                `json:"week"`
+		Day        int                `json:"day"`
+		RestDay    bool               `json:"rest_day"`
+		Finished   bool               `json:"finished"`
+		Completed  bool               `json:"completed"`
+		ProgramDay *models.ProgramDay `json:"program_day"`
+	}
+	// second day of the program is a rest day
+	w = do(t, r, http.MethodGet, fmt.Sprintf("/programs/enrollments/%d/today?date=2024-03-05", enrollment.ID), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	decode(t, w, &today)
+	require.True(t, today.RestDay)
+
+	// week 2 day 3
+	w = do(t, r, http.MethodGet, fmt.Sprintf("/programs/enrollments/%d/today?date=2024-03-13", enrollment.ID), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	decode(t, w, &today)
+	require.Equal(t, 2, today.Week)
+	require.Equal(t, 3, today.Day)
+	require.NotNil(t, today.ProgramDay)
+	require.False(t, today.Completed)
+
+	// start the scheduled workout and check it is linked
+	var ws models.WorkoutSession
+	w = do(t, r, http.MethodPost, fmt.Sprintf("/programs/enrollments/%d/today/start?date=2024-03-13", enrollment.ID), nil)
+	require.Equal(t, http.StatusCreated, w.Code)
+	decode(t, w, &ws)
+	require.Equal(t, today.ProgramDay.ID, *ws.ProgramDayID)
+	require.Equal(t, "2024-03-13", ws.Datetime.UTC().Format(time.DateOnly))
+	require.Len(t, ws.Exercises, 1)
+	require.Len(t, ws.Exercises[0].Sets, 5)
+
+	// editing the program keeps the day, and the session linked to it
+	w = do(t, r, http.MethodPut, fmt.Sprintf("/programs/%d", program.ID), map[string]any{
+		"name": "5x5 v2",
+		"weeks": []map[string]any{
+			{"week_number": 1, "days": []map[string]any{day(1), day(3)}},
+			{"week_number": 2, "days": []map[string]any{day(1), day(3), day(5)}},
+		},
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	w = do(t, r, http.MethodPut, fmt.Sprintf("/programs/%d", program.ID), map[string]any{
+		"name": "5x5 v3",
+		"weeks": []map[string]any{
+			{"week_number": 1, "days": []map[string]any{day(1), day(1)}},
+		},
+	})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = do(t, r, http.MethodGet, fmt.Sprintf("/programs/enrollments/%d/today?date=2024-03-13", enrollment.ID), nil)
+	decode(t, w, &today)
+	require.True(t, today.Completed)
+
+	// link an existing session to week 1 day 1
+	w = do(t, r, http.MethodPost, fmt.Sprintf("/programs/enrollments/%d/complete", enrollment.ID),
+		map[string]any{"session_id": squat.ID, "program_day_id": program.Weeks[0].Days[0].ID})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// on the 13th all four days count (today because it is done); two are done
+	var adherence struct {
+		Scheduled int     `json:"scheduled"`
+		Completed int     `json:"completed"`
+		Rate      float64 `json:"rate"`
+	}
+	w = do(t, r, http.MethodGet, fmt.Sprintf("/programs/enrollments/%d/adherence?date=2024-03-13", enrollment.ID), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	decode(t, w, &adherence)
+	require.Equal(t, 4, adherence.Scheduled)
+	require.Equal(t, 2, adherence.Completed)
+	require.InDelta(t, 0.5, adherence.Rate, 1e-9)
+
+	// after the last week the program is finished
+	w = do(t, r, http.MethodGet, fmt.Sprintf("/programs/enrollments/%d/today?date=2024-03-25", enrollment.ID), nil)
+	decode(t, w, &today)
+	require.True(t, today.Finished)
+
+	w = do(t, r, http.MethodDelete, fmt.Sprintf("/programs/enrollments/%d", enrollment.ID), nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+	w = do(t, r, http.MethodDelete, fmt.Sprintf("/programs/%d", program.ID), nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestProgramDeleteEndsEveryEnrollment(t *testing.T) {
+	r, db := testRouter(t)
+	press := newSession(t, r, "Push Press")
+
+	// another user enrolls in the program through their own router
+	other := gin.New()
+	wsRepo := gormrepository.NewWorkoutSessionRepository(db)
+	handler.NewProgramHandler(gormrepository.NewProgramRepository(db), gormrepository.NewProgramEnrollmentRepository(db), wsRepo,
+		use_case.NewOneRepMaxService(wsRepo, use_case.FormulaEpley)).RegisterRoutes(other, func(c *gin.Context) {
+		c.Set("user_id", uint(2))
+		c.Next()
+	})
+
+	week := []map[string]any{{"week_number": 1, "days": []map[string]any{{"day_number": 1, "prescriptions": []map[string]any{
+		{"workout_type_id": press.WorkoutTypeID, "sets": 3, "reps": 5},
+	}}}}}
+	// a prescription needs a sane number of sets, since starting the day creates them all
+	w := do(t, r, http.MethodPost, "/programs", map[string]any{"name": "Endless", "weeks": []map[string]any{
+		{"week_number": 1, "days": []map[string]any{{"day_number": 1, "prescriptions": []map[string]any{
+			{"workout_type_id": press.WorkoutTypeID, "sets": 2000000000, "reps": 5},
+		}}}},
+	}})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	var program models.Program
+	w = do(t, r, http.MethodPost, "/programs", map[string]any{"name": "Overhead", "weeks": week})
+	require.Equal(t, http.StatusCreated, w.Code)
+	decode(t, w, &program)
+
+	enrollments := make([]models.ProgramEnrollment, 2)
+	for i, router := range []*gin.Engine{r, other} {
+		w = do(t, router, http.MethodPost, fmt.Sprintf("/programs/%d/enroll", program.ID), nil)
+		require.Equal(t, http.StatusCreated, w.Code)
+		decode(t, w, &enrollments[i])
+	}
+	var started models.WorkoutSession
+	w = do(t, other, http.MethodPost, fmt.Sprintf("/programs/enrollments/%d/today/start", enrollments[1].ID), nil)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	decode(t, w, &started)
+
+	// only the author may delete it
+	w = do(t, other, http.MethodDelete, fmt.Sprintf("/programs/%d", program.ID), nil)
+	require.Equal(t, http.StatusNotFound, w.Code)
+	w = do(t, r, http.MethodDelete, fmt.Sprintf("/programs/%d", program.ID), nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	// and both users' enrollments end with it
+	for i, router := range []*gin.Engine{r, other} {
+		var page pagination.Page[models.ProgramEnrollment]
+		w = do(t, router, http.MethodGet, "/programs/enrollments", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		decode(t, w, &page)
+		for _, e := range page.Items {
+			require.NotEqual(t, enrollments[i].ID, e.ID)
+		}
+		w = do(t, router, http.MethodGet, fmt.Sprintf("/programs/enrollments/%d/today", enrollments[i].ID), nil)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	}
+
+	// the session logged for it stays, unlinked
+	kept, err := wsRepo.GetByID(context.Background(), started.ID)
+	require.NoError(t, err)
+	require.Nil(t, kept.ProgramEnrollmentID)
+	require.Nil(t, kept.ProgramDayID)
+}
+
+func TestPersonalRecordsFlaggedOnSave(t *testing.T) {
+	r, _ := testRouter(t)
+	deadlift := newSession(t, r, "Deadlift")
+
+	type savedSet struct {
+		NewRecords []models.PersonalRecord `json:"new_records"`
+	}
+
+	// first set is a record in every category
+	var res savedSet
+	w := do(t, r, http.MethodPost, fmt.Sprintf("/workout-sessions/%d/sets", deadlift.ID), map[string]any{"reps": 3, "weight": 180})
+	require.Equal(t, http.StatusCreated, w.Code)
+	decode(t, w, &res)
+	require.Len(t, res.NewRecords, 4)
+
+	// a lighter set only beats session volume and reps at its own weight
+	res = savedSet{}
+	w = do(t, r, http.MethodPost, fmt.Sprintf("/workout-sessions/%d/sets", deadlift.ID), map[string]any{"reps": 5, "weight": 150})
+	require.Equal(t, http.StatusCreated, w.Code)
+	decode(t, w, &res)
+	require.Len(t, res.NewRecords, 2)
+
+	// a new session with a heavier single flags the record on create
+	var created struct {
+		ID         uint
+		NewRecords []models.PersonalRecord `json:"new_records"`
+	}
+	w = do(t, r, http.MethodPost, "/workout-sessions", map[string]any{"exercises": []map[string]any{
+		{"workout_type_id": deadlift.WorkoutTypeID, "sets": []map[string]any{{"reps": 1, "weight": 200}}},
+	}})
+	require.Equal(t, http.StatusCreated, w.Code)
+	decode(t, w, &created)
+	require.NotZero(t, created.ID)
+	heaviest := false
+	for _, rec := range created.NewRecords {
+		heaviest = heaviest || (rec.Kind == models.RecordHeaviestWeight && rec.Value == 200)
+	}
+	require.True(t, heaviest)
+
+	var history pagination.Page[models.PersonalRecord]
+	w = do(t, r, http.MethodGet, fmt.Sprintf("/workout-types/%d/records", deadlift.WorkoutTypeID), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	decode(t, w, &history)
+	// the first session raised its volume record in place instead of storing a second one
+	require.Len(t, history.Items, 5+len(created.NewRecords))
+
+	var current pagination.Page[models.PersonalRecord]
+	w = do(t, r, http.MethodGet, "/users/me/records", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	decode(t, w, &current)
+	weights := 0
+	for _, rec := range current.Items {
+		if rec.WorkoutTypeID == deadlift.WorkoutTypeID && rec.Kind == models.RecordHeaviestWeight {
+			weights++
+			require.Equal(t, 200.0, rec.Value)
+		}
+	}
+	require.Equal(t, 1, weights)
+}
+
+func TestDeletingSessionDropsItsRecords(t *testing.T) {
+	r, db := testRouter(t)
+	squat := newSession(t, r, "Box Squat")
+	w := do(t, r, http.MethodPost, fmt.Sprintf("/workout-sessions/%d/sets", squat.ID), map[string]any{"reps": 5, "weight": 120})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var heavier models.WorkoutSession
+	w = do(t, r, http.MethodPost, "/workout-sessions", map[string]any{"exercises": []map[string]any{
+		{"workout_type_id": squat.WorkoutTypeID, "sets": []map[string]any{{"reps": 1, "weight": 140}}},
+	}})
+	require.Equal(t, http.StatusCreated, w.Code)
+	decode(t, w, &heavier)
+
+	heaviest := func() []float64 {
+		var current pagination.Page[models.PersonalRecord]
+		w := do(t, r, http.MethodGet, "/users/me/records", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		decode(t, w, &current)
+		var values []float64
+		for _, rec := range current.Items {
+			if rec.WorkoutTypeID == squat.WorkoutTypeID && rec.Kind == models.RecordHeaviestWeight {
+				values = append(values, rec.Value)
+			}
+		}
+		return values
+	}
+	require.Equal(t, []float64{140}, heaviest())
+
+	// the deleted session's records, exercises and sets go with it
+	w = do(t, r, http.MethodDelete, fmt.Sprintf("/workout-sessions/%d", heavier.ID), nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.Equal(t, []float64{120}, heaviest())
+	for _, child := range []any{&models.PersonalRecord{}, &models.WorkoutExercise{}, &models.WorkoutSet{}} {
+		var n int64
+		require.NoError(t, db.Model(child).Where("workout_session_id = ?", heavier.ID).Count(&n).Error)
+		require.Zero(t, n)
+	}
+
+	w = do(t, r, http.MethodDelete, fmt.Sprintf("/workout-sessions/%d", squat.ID), nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.Empty(t, heaviest())
+}
+
+func TestOneRepMaxSeriesAndProgramPercentages(t *testing.T) {
+	r, _ := testRouter(t)
+	bench := newSession(t, r, "Flat Bench")
+
+	w := do(t, r, http.MethodPost, fmt.Sprintf("/workout-sessions/%d/sets", bench.ID), map[string]any{"reps": 10, "weight": 75})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var series struct {
+		Formula string `json:"formula"`
+		Points  []struct {
+			SessionID uint    `json:"session_id"`
+			Estimate  float64 `json:"estimate"`
+		} `json:"points"`
+	}
+	w = do(t, r, http.MethodGet, fmt.Sprintf("/workout-types/%d/e1rm", bench.WorkoutTypeID), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	decode(t, w, &series)
+	require.Equal(t, "epley", series.Formula)
+	require.Len(t, series.Points, 1)
+	require.Equal(t, bench.ID, series.Points[0].SessionID)
+	require.InDelta(t, 100, series.Points[0].Estimate, 1e-9)
+
+	w = do(t, r, http.MethodGet, fmt.Sprintf("/workout-types/%d/e1rm?formula=lombardi", bench.WorkoutTypeID), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	decode(t, w, &series)
+	require.InDelta(t, 94.42, series.Points[0].Estimate, 0.01)
+
+	w = do(t, r, http.MethodGet, fmt.Sprintf("/workout-types/%d/e1rm?formula=unknown", bench.WorkoutTypeID), nil)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	w = do(t, r, http.MethodGet, fmt.Sprintf("/workout-types/%d/e1rm?from=2000-01-01&to=2000-01-31", bench.WorkoutTypeID), nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	decode(t, w, &series)
+	require.Empty(t, series.Points)
+
+	// a 75% prescription becomes 75 kg from the 100 kg estimate
+	var program models.Program
+	w = do(t, r, http.MethodPost, "/programs", map[string]any{
+		"name": "Bench specialisation",
+		"weeks": []map[string]any{{"week_number": 1, "days": []map[string]any{{"day_number": 1, "prescriptions": []map[string]any{
+			{"workout_type_id": bench.WorkoutTypeID, "sets": 3, "reps": 5, "percent_1rm": 75},
+		}}}}},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	decode(t, w, &program)
+
+	var enrollment models.ProgramEnrollment
+	w = do(t, r, http.MethodPost, fmt.Sprintf("/programs/%d/enroll", program.ID), nil)
+	require.Equal(t, http.StatusCreated, w.Code)
+	decode(t, w, &enrollment)
+
+	var ws models.WorkoutSession
+	w = do(t, r, http.MethodPost, fmt.Sprintf("/programs/enrollments/%d/today/start", enrollment.ID), nil)
+	require.Equal(t, http.StatusCreated, w.Code)
+	decode(t, w, &ws)
+	require.Len(t, ws.Exercises[0].Sets, 3)
+	require.Equal(t, 75.0, ws.Exercises[0].Sets[0].Weight)
+}
+
+func TestWorkoutSessionListFilters(t *testing.T) {
+	r, _ := testRouter(t)
+	row := newSession(t, r, "Barbell Row")
+	curl := newSession(t, r, "Biceps Curl")
+
+	// three sessions in January 2019; the second mixes both types, the third has a detail
+	var ids []uint
+	for i, exercises := range [][]uint{{row.WorkoutTypeID}, {curl.WorkoutTypeID, row.WorkoutTypeID}, {curl.WorkoutTypeID}} {
+		var list []map[string]any
+		for _, id := range exercises {
+			list = append(list, map[string]any{"workout_type_id": id})
+		}
+		var ws models.WorkoutSession
+		w := do(t, r, http.MethodPost, "/workout-sessions", map[string]any{
+			"datetime":  time.Date(2019, 1, 10+i, 12, 0, 0, 0, time.UTC),
+			"exercises": list,
+		})
+		require.Equal(t, http.StatusCreated, w.Code)
+		decode(t, w, &ws)
+		ids = append(ids, ws.ID)
+	}
+	w := do(t, r, http.MethodPost, fmt.Sprintf("/workout-sessions/%d/details", ids[2]), map[string]any{"name": "notes", "value": "pump"})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	type page struct {
+		Items []models.WorkoutSession `json:"items"`
+		Total int                     `json:"total"`
+		Limit int                     `json:"limit"`
+	}
+	list := func(query string) page {
+		var p page
+		w := do(t, r, http.MethodGet, "/workout-sessions?from=2019-01-01&to=2019-01-31&"+query, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		decode(t, w, &p)
+		return p
+	}
+
+	p := list("")
+	require.Equal(t, 3, p.Total)
+	require.Equal(t, ids[2], p.Items[0].ID) // newest first
+
+	p = list("sort=asc&limit=1")
+	require.Equal(t, 3, p.Total)
+	require.Len(t, p.Items, 1)
+	require.Equal(t, ids[0], p.Items[0].ID)
+
+	// the mixed session matches both types through its exercises
+	p = list(fmt.Sprintf("workout_type_id=%d", row.WorkoutTypeID))
+	require.Equal(t, 2, p.Total)
+	p = list(fmt.Sprintf("workout_type_id=%d&sort=asc", curl.WorkoutTypeID))
+	require.Equal(t, []uint{ids[1], ids[2]}, []uint{p.Items[0].ID, p.Items[1].ID})
+
+	var curlType models.WorkoutType
+	w = do(t, r, http.MethodGet, fmt.Sprintf("/workout-types/%d", curl.WorkoutTypeID), nil)
+	decode(t, w, &curlType)
+	p = list(fmt.Sprintf("muscle_group_id=%d", curlType.MuscleGroupID))
+	require.Equal(t, 2, p.Total)
+
+	p = list("has_details=true")
+	require.Equal(t, 1, p.Total)
+	require.Equal(t, ids[2], p.Items[0].ID)
+	p = list("has_details=false")
+	require.Equal(t, 2, p.Total)
+
+	// date-only "to" includes the whole day
+	w = do(t, r, http.MethodGet, "/workout-sessions?from=2019-01-01&to=2019-01-11", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	decode(t, w, &p)
+	require.Equal(t, 2, p.Total)
+
+	w = do(t, r, http.MethodGet, "/workout-sessions?sort=sideways", nil)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	w = do(t, r, http.MethodGet, "/workout-sessions?from=yesterday", nil)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCursorPagination(t *testing.T) {
+	r, _ := testRouter(t)
+	press := newSession(t, r, "Overhead Press")
+
+	// sessions sharing one timestamp are still paged without gaps or duplicates
+	at := time.Date(2018, 6, 1, 7, 0, 0, 0, time.UTC)
+	want := map[uint]bool{}
+	for i := 0; i < 5; i++ {
+		var ws models.WorkoutSession
+		w := do(t, r, http.MethodPost, "/workout-sessions", map[string]any{"workout_type_id": press.WorkoutTypeID, "datetime": at.Add(time.Duration(i%2) * time.Hour)})
+		require.Equal(t, http.StatusCreated, w.Code)
+		decode(t, w, &ws)
+		want[ws.ID] = true
+	}
+
+	seen := map[uint]bool{}
+	cursor, pages := "", 0
+	for {
+		var page struct {
+			Items      []models.WorkoutSession `json:"items"`
+			NextCursor string                  `json:"next_cursor"`
+			Total      int                     `json:"total"`
+		}
+		w := do(t, r, http.MethodGet, "/workout-sessions?from=2018-06-01&to=2018-06-01&limit=2&cursor="+cursor, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		decode(t, w, &page)
+		if pages == 0 {
+			require.Equal(t, 5, page.Total)
+		}
+		for _, s := range page.Items {
+			require.False(t, seen[s.ID], "session %d returned twice", s.ID)
+			seen[s.ID] = true
+		}
+		pages++
+		// a session logged mid-scroll must not shift the remaining pages
+		if pages == 1 {
+			w = do(t, r, http.MethodPost, "/workout-sessions", map[string]any{"workout_type_id": press.WorkoutTypeID, "datetime": at.Add(2 * time.Hour)})
+			require.Equal(t, http.StatusCreated, w.Code)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	require.Equal(t, 3, pages)
+	require.Equal(t, want, seen)
+
+	// ID-ordered lists walk every workout type exactly once
+	var ids []uint
+	cursor = ""
+	for {
+		var page pagination.Page[models.WorkoutType]
+		w := do(t, r, http.MethodGet, "/workout-types?limit=3&cursor="+cursor, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		decode(t, w, &page)
+		for _, wt := range page.Items {
+			ids = append(ids, wt.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	require.Contains(t, ids, press.WorkoutTypeID)
+	for i := 1; i < len(ids); i++ {
+		require.Less(t, ids[i-1], ids[i])
+	}
+
+	// templates are paged in name order
+	for _, name := range []string{"Pull", "Legs", "Push"} {
+		w := do(t, r, http.MethodPost, "/workout-templates", map[string]any{"name": name, "exercises": []map[string]any{
+			{"workout_type_id": press.WorkoutTypeID, "target_sets": 3},
+		}})
+		require.Equal(t, http.StatusCreated, w.Code)
+	}
+	var names []string
+	cursor = ""
+	for {
+		var page pagination.Page[models.WorkoutTemplate]
+		w := do(t, r, http.MethodGet, "/workout-templates?limit=2&cursor="+cursor, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		decode(t, w, &page)
+		for _, tpl := range page.Items {
+			names = append(names, tpl.Name)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	require.IsNonDecreasing(t, names)
+	require.Subset(t, names, []string{"Legs", "Pull", "Push"})
+
+	w := do(t, r, http.MethodGet, "/workout-types?cursor=not-a-cursor", nil)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	w = do(t, r, http.MethodGet, fmt.Sprintf("/workout-sessions/%d/sets?cursor=%s", press.ID, pagination.Cursor{ID: 1}.Encode()), nil)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	w = do(t, r, http.MethodGet, "/muscle-groups?limit=0", nil)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSuggestWorkoutStream(t *testing.T) {
+	r, db := testRouter(t)
+	newSession(t, r, "Squat")
+
+	llm := provider.NewFake("Leg day: 5x5 squats")
+	wsRepo := gormrepository.NewWorkoutSessionRepository(db)
+	sg := handler.NewSuggestHandler(wsRepo, gormrepository.NewWorkoutTypeRepository(db), suggester.New(llm), use_case.NewOneRepMaxService(wsRepo, use_case.FormulaEpley))
+	sg.RegisterRoutes(r, func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Next()
+	})
+
+	assertStream := func(w *httptest.ResponseRecorder) {
+		require.Equal(t, http.StatusOK, w.Code)
+		require.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/event-stream"))
+		body := w.Body.String()
+		require.Equal(t, 4, strings.Count(body, "event:chunk\n"))
+		require.Contains(t, body, `data:{"text":"day: "}`)
+		require.Contains(t, body, "event:done\n")
+		require.Contains(t, body, `"suggestion":"Leg day: 5x5 squats","engine":"llm","chunks":4`)
+		require.Less(t, strings.Index(body, `"squats"}`), strings.Index(body, "event:done"), "done comes last")
+	}
+	assertStream(do(t, r, http.MethodGet, "/suggest-workout/stream", nil))
+
+	// the plain endpoint streams too when the client asks for events
+	req, _ := http.NewRequest(http.MethodGet, "/suggest-workout", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assertStream(w)
+
+	// and otherwise answers with the whole suggestion at once
+	var res struct{ Suggestion, Engine string }
+	w = do(t, r, http.MethodGet, "/suggest-workout", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	decode(t, w, &res)
+	require.Equal(t, "Leg day: 5x5 squats", res.Suggestion)
+	require.Equal(t, "llm", res.Engine)
+
+	// the prompt carries the training history
+	chats := llm.Chats()
+	require.Len(t, chats, 3)
+	require.Contains(t, chats[0][0].Content, "Session 1: Squat")
+}
+
+func TestSuggestWorkoutStreamInterrupted(t *testing.T) {
+	r, db := testRouter(t)
+	newSession(t, r, "Squat")
+
+	var llm chatFunc
+	wsRepo := gormrepository.NewWorkoutSessionRepository(db)
+	sg := handler.NewSuggestHandler(wsRepo, gormrepository.NewWorkoutTypeRepository(db), suggester.New(chatFunc(func(ctx context.Context, messages []provider.Message, onChunk func(string) error) (string, error) {
+		return llm(ctx, messages, onChunk)
+	})), use_case.NewOneRepMaxService(wsRepo, use_case.FormulaEpley))
+	sg.RegisterRoutes(r, func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Next()
+	})
+
+	// a model failing midway ends the stream with an error event and no rule suggestion
+	llm = func(ctx context.Context, _ []provider.Message, onChunk func(string) error) (string, error) {
+		if err := onChunk("Leg "); err != nil {
+			return "", err
+		}
+		return "", errors.New("model crashed")
+	}
+	w := do(t, r, http.MethodGet, "/suggest-workout/stream", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	require.Equal(t, 1, strings.Count(body, "event:chunk\n"))
+	require.Contains(t, body, "event:error\n")
+	require.Contains(t, body, `"error":"model crashed"`)
+	require.NotContains(t, body, "event:done")
+
+	// a client going away stops the generation without an error event or a fallback
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	llm = func(ctx context.Context, _ []provider.Message, onChunk func(string) error) (string, error) {
+		if err := onChunk("Leg "); err != nil {
+			return "", err
+		}
+		cancel()
+		return "", onChunk("day")
+	}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/suggest-workout/stream", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	body = w.Body.String()
+	require.Equal(t, 1, strings.Count(body, "event:chunk\n"))
+	require.NotContains(t, body, "event:error")
+	require.NotContains(t, body, "event:done")
+
+	// the plain endpoint runs the model under the request's context and timeout
+	llm = func(ctx context.Context, _ []provider.Message, _ func(string) error) (string, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			return "", errors.New("no deadline")
+		}
+		return "Rest day.", nil
+	}
+	var res struct{ Suggestion, Engine string }
+	w = do(t, r, http.MethodGet, "/suggest-workout", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	decode(t, w, &res)
+	require.Equal(t, "Rest day.", res.Suggestion)
+	require.Equal(t, "llm", res.Engine)
+}
+
+func TestSuggestWorkoutPlan(t *testing.T) {
+	r, db := testRouter(t)
+	ws := newSession(t, r, "Snatch Grip Deadlift")
+
+	llm := provider.NewFake(`{"title": "Pull day", "rationale": "Back is rested.", "exercises": [
+		{"workout_type": "snatch-grip deadlift", "sets": 3, "reps": "5", "target_weight": 140, "unit": "kg", "rationale": "Heavy triples."},
+		{"workout_type": "Nordic Curl", "sets": 3, "reps": 6, "target_weight": 0, "unit": "kg"}]}`)
+	wsRepo := gormrepository.NewWorkoutSessionRepository(db)
+	sg := handler.NewSuggestHandler(wsRepo, gormrepository.NewWorkoutTypeRepository(db), suggester.New(llm), use_case.NewOneRepMaxService(wsRepo, use_case.FormulaEpley))
+	sg.RegisterRoutes(r, func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Next()
+	})
+
+	// the plan names known workout types only, with their IDs
+	var plan struct {
+		Title     string
+		Exercises []map[string]any
+	}
+	w := do(t, r, http.MethodGet, "/suggest-workout/plan", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	decode(t, w, &plan)
+	require.Equal(t, "Pull day", plan.Title)
+	require.Len(t, plan.Exercises, 1)
+	require.EqualValues(t, ws.WorkoutTypeID, plan.Exercises[0]["workout_type_id"])
+	require.Equal(t, "Snatch Grip Deadlift", plan.Exercises[0]["workout_type"])
+	require.EqualValues(t, 5, plan.Exercises[0]["target_reps"])
+	require.Equal(t, "Heavy triples.", plan.Exercises[0]["notes"])
+
+	// posting the plan back creates a draft session with planned sets
+	var session models.WorkoutSession
+	w = do(t, r, http.MethodPost, "/suggest-workout/plan/start", plan)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	decode(t, w, &session)
+	require.Equal(t, uint(1), session.UserID)
+	require.Len(t, session.Exercises, 1)
+	require.Len(t, session.Exercises[0].Sets, 3)
+	require.Equal(t, 140.0, session.Exercises[0].Sets[0].Weight)
+	require.False(t, session.Exercises[0].Sets[0].Completed)
+
+	w = do(t, r, http.MethodPost, "/suggest-workout/plan/start", map[string]any{
+		"exercises": []map[string]any{{"workout_type_id": 99999, "target_sets": 1}},
+	})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCoachThreads(t *testing.T) {
+	r, db := testRouter(t)
+	newSession(t, r, "Overhead Press")
+
+	llm := provider.NewFake("Swap it for push-ups.")
+	wsRepo := gormrepository.NewWorkoutSessionRepository(db)
+	coachRepo := gormrepository.NewCoachRepository(db)
+	ch := handler.NewCoachHandler(coachRepo, wsRepo, coach.New(llm, 0), use_case.NewOneRepMaxService(wsRepo, use_case.FormulaEpley))
+	ch.RegisterRoutes(r, func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Next()
+	})
+
+	var thread models.CoachThread
+	w := do(t, r, http.MethodPost, "/coach/threads", nil)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	decode(t, w, &thread)
+	target := fmt.Sprintf("/coach/threads/%d", thread.ID)
+
+	var reply models.CoachMessage
+	w = do(t, r, http.MethodPost, target+"/messages", map[string]any{"content": "My shoulder hurts,  swap the bench?"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	decode(t, w, &reply)
+	require.Equal(t, models.CoachRoleAssistant, reply.Role)
+	require.Equal(t, "Swap it for push-ups.", reply.Content)
+	require.Contains(t, llm.Chats()[0][0].Content, "Overhead Press", "the coach sees recent sessions")
+
+	// follow-ups stream and carry the whole conversation
+	req, _ := http.NewRequest(http.MethodPost, target+"/messages", asJSON(t, map[string]any{"content": "And for triceps?"}))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, 4, strings.Count(w.Body.String(), "event:chunk\n"))
+	require.Contains(t, w.Body.String(), "event:done\n")
+	sent := llm.Chats()[1]
+	require.Len(t, sent, 4)
+	require.Equal(t, provider.RoleAssistant, sent[2].Role)
+	require.Equal(t, "And for triceps?", sent[3].Content)
+
+	w = do(t, r, http.MethodGet, target, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	decode(t, w, &thread)
+	require.Equal(t, "My shoulder hurts, swap the bench?", thread.Title)
+	require.Len(t, thread.Messages, 4)
+
+	var page struct{ Items []models.CoachThread }
+	w = do(t, r, http.MethodGet, "/coach/threads", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	decode(t, w, &page)
+	require.NotEmpty(t, page.Items)
+	require.Equal(t, thread.ID, page.Items[0].ID)
+
+	// other users' threads stay hidden
+	other := &models.CoachThread{UserID: 2, Title: "Private"}
+	require.NoError(t, coachRepo.CreateThread(context.Background(), other))
+	w = do(t, r, http.MethodGet, fmt.Sprintf("/coach/threads/%d", other.ID), nil)
+	require.Equal(t, http.StatusNotFound, w.Code)
+	w = do(t, r, http.MethodPost, fmt.Sprintf("/coach/threads/%d/messages", other.ID), map[string]any{"content": "hi"})
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	w = do(t, r, http.MethodDelete, target, nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+	w = do(t, r, http.MethodGet, target, nil)
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCoachReplyInterrupted(t *testing.T) {
+	r, db := testRouter(t)
+	newSession(t, r, "Overhead Press")
+
+	var llm chatFunc
+	wsRepo := gormrepository.NewWorkoutSessionRepository(db)
+	coachRepo := gormrepository.NewCoachRepository(db)
+	ch := handler.NewCoachHandler(coachRepo, wsRepo, coach.New(chatFunc(func(ctx context.Context, messages []provider.Message, onChunk func(string) error) (string, error) {
+		return llm(ctx, messages, onChunk)
+	}), 0), use_case.NewOneRepMaxService(wsRepo, use_case.FormulaEpley))
+	ch.RegisterRoutes(r, func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Next()
+	})
+
+	thread := &models.CoachThread{UserID: 1}
+	require.NoError(t, coachRepo.CreateThread(context.Background(), thread))
+	target := fmt.Sprintf("/coach/threads/%d/messages", thread.ID)
+	post := func(ctx context.Context) *httptest.ResponseRecorder {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, target, asJSON(t, map[string]any{"content": "Swap the bench?"}))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "text/event-stream")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	requireEmpty := func() {
+		stored, err := coachRepo.GetThread(context.Background(), thread.ID)
+		require.NoError(t, err)
+		require.Empty(t, stored.Messages, "an unanswered question is not stored")
+		require.Empty(t, stored.Title)
+	}
+
+	// a failing model leaves the thread untouched
+	llm = func(context.Context, []provider.Message, func(string) error) (string, error) {
+		return "", errors.New("model crashed")
+	}
+	w := do(t, r, http.MethodPost, target, map[string]any{"content": "Swap the bench?"})
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	requireEmpty()
+
+	// and so does one failing midway through a stream
+	llm = func(ctx context.Context, _ []provider.Message, onChunk func(string) error) (string, error) {
+		if err := onChunk("Swap "); err != nil {
+			return "", err
+		}
+		return "", errors.New("model crashed")
+	}
+	w = post(context.Background())
+	require.Contains(t, w.Body.String(), "event:error\n")
+	require.NotContains(t, w.Body.String(), "event:done")
+	requireEmpty()
+
+	// as does a client disconnecting during the reply
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	llm = func(ctx context.Context, _ []provider.Message, onChunk func(string) error) (string, error) {
+		if err := onChunk("Swap "); err != nil {
+			return "", err
+		}
+		cancel()
+		return "", onChunk("it")
+	}
+	w = post(ctx)
+	require.Equal(t, 1, strings.Count(w.Body.String(), "event:chunk\n"))
+	require.NotContains(t, w.Body.String(), "event:error")
+	requireEmpty()
+
+	// once the model answers, question and answer are stored together
+	llm = provider.NewFake("Try push-ups.").Chat
+	w = post(context.Background())
+	require.Contains(t, w.Body.String(), "event:done\n")
+	stored, err := coachRepo.GetThread(context.Background(), thread.ID)
+	require.NoError(t, err)
+	require.Len(t, stored.Messages, 2)
+	require.Equal(t, models.CoachRoleUser, stored.Messages[0].Role)
+	require.Equal(t, "Try push-ups.", stored.Messages[1].Content)
+	require.Equal(t, "Swap the bench?", stored.Title)
+}
+
+func TestSuggestWorkoutFallsBackToRules(t *testing.T) {
+	r, db := testRouter(t)
+	newSession(t, r, "Hip Thrust")
+
+	// a model server that is down
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	wsRepo := gormrepository.NewWorkoutSessionRepository(db)
+	sg := handler.NewSuggestHandler(wsRepo, gormrepository.NewWorkoutTypeRepository(db), suggester.New(provider.NewOllama(down.URL, "test")), use_case.NewOneRepMaxService(wsRepo, use_case.FormulaEpley))
+	sg.RegisterRoutes(r, func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Next()
+	})
+
+	var res struct{ Suggestion, Engine string }
+	w := do(t, r, http.MethodGet, "/suggest-workout", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	decode(t, w, &res)
+	require.Equal(t, "rules", res.Engine)
+	require.NotEmpty(t, res.Suggestion)
+
+	w = do(t, r, http.MethodGet, "/suggest-workout/stream", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, 1, strings.Count(w.Body.String(), "event:chunk\n"))
+	require.Contains(t, w.Body.String(), `"engine":"rules","chunks":1`)
+	require.NotContains(t, w.Body.String(), "event:error")
+
+	var plan struct {
+		Engine    string
+		Exercises []struct {
+			WorkoutTypeID uint `json:"workout_type_id"`
+		}
+	}
+	w = do(t, r, http.MethodGet, "/suggest-workout/plan", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	decode(t, w, &plan)
+	require.Equal(t, "rules", plan.Engine)
+	for _, e := range plan.Exercises {
+		require.NotZero(t, e.WorkoutTypeID)
+	}
+
+	// with the catalog emptied the rules suggest a beginner routine
+	require.NoError(t, db.Exec("DELETE FROM workout_types").Error)
+	w = do(t, r, http.MethodGet, "/suggest-workout", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	decode(t, w, &res)
+	require.Equal(t, "rules", res.Engine)
+	require.Contains(t, res.Suggestion, "beginner routine")
+	w = do(t, r, http.MethodGet, "/suggest-workout/stream", nil)
+	require.Contains(t, w.Body.String(), "beginner routine")
+	require.NotContains(t, w.Body.String(), "event:error")
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/VibeTeam/fitness-tracker-backend/shared/middleware"
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
 	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
 	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
 )
@@ -21,7 +23,7 @@ func NewMuscleGroupHandler(repo repository.MuscleGroupRepository) *MuscleGroupHa
 
 func (h *MuscleGroupHandler) RegisterRoutes(r *gin.Engine, auth gin.HandlerFunc) {
 	mg := r.Group("/muscle-groups")
-	mg.Use(auth)
+	mg.Use(auth, middleware.RequireAccess(middleware.ScopeCatalogRead, middleware.ScopeCatalogWrite))
 	{
 		mg.POST("", h.create)
 		mg.GET("", h.list)
@@ -39,6 +41,7 @@ type muscleGroupRequest struct {
 // @Summary      Create muscle group
 // @Tags         muscle-groups
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        payload  body      muscleGroupRequest  true  "Muscle group"
@@ -64,22 +67,34 @@ func (h *MuscleGroupHandler) create(c *gin.Context) {
 // @Summary      List muscle groups
 // @Tags         muscle-groups
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Produce      json
-// @Success      200  {array}   models.MuscleGroup
+// @Param        limit   query     int     false  "Page size (default 100, max 500)"
+// @Param        cursor  query     string  false  "next_cursor of the previous page"
+// @Success      200     {object}  pagination.Page[models.MuscleGroup]
+// @Failure      400     {object}  gin.H
 // @Router       /muscle-groups [get]
 func (h *MuscleGroupHandler) list(c *gin.Context) {
-	groups, err := h.repo.List(c.Request.Context(), 100, 0)
+	p, err := pagination.Parse(c.Query("limit"), c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	groups, err := h.repo.List(c.Request.Context(), p.Limit+1, p.After)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, groups)
+	c.JSON(http.StatusOK, pagination.NewPage(groups, p, func(mg *models.MuscleGroup) pagination.Cursor {
+		return pagination.Cursor{ID: mg.ID}
+	}))
 }
 
 // get muscle group
 // @Summary      Get muscle group by ID
 // @Tags         muscle-groups
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Produce      json
 // @Param        id   path      int  true  "MuscleGroup ID"
 // @Success      200  {object}  models.MuscleGroup
@@ -104,6 +119,7 @@ func (h *MuscleGroupHandler) getByID(c *gin.Context) {
 // @Summary      Update muscle group
 // @Tags         muscle-groups
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id       path      int                true  "MuscleGroup ID"
@@ -140,6 +156,7 @@ func (h *MuscleGroupHandler) update(c *gin.Context) {
 // @Summary      Delete muscle group
 // @Tags         muscle-groups
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Param        id   path      int  true  "MuscleGroup ID"
 // @Success      204  {string}  string  "No Content"
 // @Failure      400  {object}  gin.H
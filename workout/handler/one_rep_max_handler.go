@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/middleware"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/use_case"
+)
+
+// OneRepMaxHandler exposes estimated one-rep-max history of the authenticated user.
+type OneRepMaxHandler struct {
+	oneRM *use_case.OneRepMaxService
+}
+
+func NewOneRepMaxHandler(oneRM *use_case.OneRepMaxService) *OneRepMaxHandler {
+	return &OneRepMaxHandler{oneRM: oneRM}
+}
+
+func (h *OneRepMaxHandler) RegisterRoutes(r *gin.Engine, auth gin.HandlerFunc) {
+	r.GET("/workout-types/:id/e1rm", auth, middleware.RequireScope(middleware.ScopeSessionsRead), h.series)
+}
+
+type oneRepMaxResponse struct {
+	WorkoutTypeID uint                      `json:"workout_type_id"`
+	Formula       use_case.Formula          `json:"formula"`
+	Points        []use_case.OneRepMaxPoint `json:"points"`
+}
+
+// estimated 1RM series
+// @Summary      Estimated one-rep max over time
+// @Description  One point per session: the best estimate among its completed sets of the workout type, in kg
+// @Tags         records
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        id       path      int     true   "WorkoutType ID"
+// @Param        formula  query     string  false  "epley, brzycki or lombardi (defaults to the server setting)"
+// @Param        from     query     string  false  "Start date YYYY-MM-DD (inclusive)"
+// @Param        to       query     string  false  "End date YYYY-MM-DD (inclusive)"
+// @Success      200      {object}  oneRepMaxResponse
+// @Failure      400      {object}  gin.H
+// @Failure      500      {object}  gin.H
+// @Router       /workout-types/{id}/e1rm [get]
+func (h *OneRepMaxHandler) series(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	uid, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+	formula := h.oneRM.Default()
+	if name := c.Query("formula"); name != "" {
+		if formula, err = use_case.ParseFormula(name); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	from, to, ok := dateRange(c)
+	if !ok {
+		return
+	}
+
+	points, err := h.oneRM.Series(c.Request.Context(), uid, uint(id), from, to, formula)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, oneRepMaxResponse{WorkoutTypeID: uint(id), Formula: formula, Points: points})
+}
+
+// dateRange reads the optional from/to calendar dates of a request as a half-open range
+// [from, to+1 day). Missing bounds leave the range open on that side.
+func dateRange(c *gin.Context) (time.Time, time.Time, bool) {
+	from, to := time.Time{}, time.Now().AddDate(100, 0, 0)
+	if v := c.Query("from"); v != "" {
+		d, err := time.Parse(dateLayout, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be YYYY-MM-DD"})
+			return from, to, false
+		}
+		from = d
+	}
+	if v := c.Query("to"); v != "" {
+		d, err := time.Parse(dateLayout, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be YYYY-MM-DD"})
+			return from, to, false
+		}
+		to = d.AddDate(0, 0, 1)
+	}
+	if !to.After(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must not be before from"})
+		return from, to, false
+	}
+	return from, to, true
+}
@@ -0,0 +1,717 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/middleware"
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/use_case"
+)
+
+// dateLayout is the calendar date format used by program endpoints.
+const dateLayout = "2006-01-02"
+
+// ProgramHandler handles multi-week training programs, enrollments and their daily schedule.
+type ProgramHandler struct {
+	repo           repository.ProgramRepository
+	enrollmentRepo repository.ProgramEnrollmentRepository
+	sessionRepo    repository.WorkoutSessionRepository
+	oneRM          *use_case.OneRepMaxService
+}
+
+func NewProgramHandler(repo repository.ProgramRepository, enrollmentRepo repository.ProgramEnrollmentRepository, sessionRepo repository.WorkoutSessionRepository, oneRM *use_case.OneRepMaxService) *ProgramHandler {
+	return &ProgramHandler{repo: repo, enrollmentRepo: enrollmentRepo, sessionRepo: sessionRepo, oneRM: oneRM}
+}
+
+func (h *ProgramHandler) RegisterRoutes(r *gin.Engine, auth gin.HandlerFunc) {
+	p := r.Group("/programs")
+	p.Use(auth, middleware.RequireAccess(middleware.ScopeProgramsRead, middleware.ScopeProgramsWrite))
+	{
+		p.POST("", h.create)
+		p.GET("", h.list)
+		p.GET("/:id", h.getByID)
+		p.PUT("/:id", h.update)
+		p.DELETE("/:id", h.delete)
+		p.POST("/:id/enroll", h.enroll)
+
+		p.GET("/enrollments", h.listEnrollments)
+		p.DELETE("/enrollments/:id", h.unenroll)
+		p.GET("/enrollments/:id/today", h.today)
+		p.POST("/enrollments/:id/today/start", middleware.RequireScope(middleware.ScopeSessionsWrite), h.startToday)
+		p.POST("/enrollments/:id/complete", h.complete)
+		p.GET("/enrollments/:id/adherence", h.adherence)
+	}
+}
+
+type programRequest struct {
+	Name        string               `json:"name" binding:"required"`
+	Description string               `json:"description"`
+	Weeks       []programWeekRequest `json:"weeks" binding:"required,min=1,dive"`
+}
+
+type programWeekRequest struct {
+	WeekNumber int                 `json:"week_number" binding:"required,min=1"`
+	Days       []programDayRequest `json:"days" binding:"dive"`
+}
+
+type programDayRequest struct {
+	DayNumber     int                   `json:"day_number" binding:"required,min=1,max=7"`
+	Name          string                `json:"name"`
+	Prescriptions []prescriptionRequest `json:"prescriptions" binding:"required,min=1,dive"`
+}
+
+type prescriptionRequest struct {
+	WorkoutTypeID uint     `json:"workout_type_id" binding:"required"`
+	Sets          int      `json:"sets" binding:"required,min=1,max=50"`
+	Reps          int      `json:"reps" binding:"min=0"`
+	PercentOneRM  *float64 `json:"percent_1rm" binding:"omitempty,gt=0,lte=150"`
+	Weight        *float64 `json:"weight" binding:"omitempty,min=0"`
+	Unit          string   `json:"unit" binding:"omitempty,oneof=kg lb"`
+	Notes         string   `json:"notes"`
+}
+
+// validate rejects week numbers used twice, and day numbers used twice within a week, since
+// updates match weeks and days by number.
+func (req programRequest) validate() error {
+	weeks := make(map[int]bool, len(req.Weeks))
+	for _, w := range req.Weeks {
+		if weeks[w.WeekNumber] {
+			return fmt.Errorf("week %d is listed twice", w.WeekNumber)
+		}
+		weeks[w.WeekNumber] = true
+		days := make(map[int]bool, len(w.Days))
+		for _, d := range w.Days {
+			if days[d.DayNumber] {
+				return fmt.Errorf("day %d of week %d is listed twice", d.DayNumber, w.WeekNumber)
+			}
+			days[d.DayNumber] = true
+		}
+	}
+	return nil
+}
+
+// weeks converts the request tree into program weeks.
+func (req programRequest) weeks() []models.ProgramWeek {
+	weeks := make([]models.ProgramWeek, len(req.Weeks))
+	for wi, w := range req.Weeks {
+		weeks[wi] = models.ProgramWeek{WeekNumber: w.WeekNumber}
+		for _, d := range w.Days {
+			day := models.ProgramDay{DayNumber: d.DayNumber, Name: d.Name}
+			for pi, p := range d.Prescriptions {
+				unit := p.Unit
+				if unit == "" {
+					unit = models.UnitKilograms
+				}
+				day.Prescriptions = append(day.Prescriptions, models.ProgramPrescription{
+					WorkoutTypeID: p.WorkoutTypeID,
+					Position:      pi + 1,
+					Sets:          p.Sets,
+					Reps:          p.Reps,
+					PercentOneRM:  p.PercentOneRM,
+					Weight:        p.Weight,
+					Unit:          unit,
+					Notes:         p.Notes,
+				})
+			}
+			weeks[wi].Days = append(weeks[wi].Days, day)
+		}
+	}
+	return weeks
+}
+
+type enrollRequest struct {
+	// StartDate is a calendar date (YYYY-MM-DD); defaults to today.
+	StartDate string `json:"start_date"`
+}
+
+type completeDayRequest struct {
+	SessionID uint `json:"session_id" binding:"required"`
+	// ProgramDayID defaults to the day scheduled on the session's date.
+	ProgramDayID *uint `json:"program_day_id"`
+}
+
+type todayResponse struct {
+	EnrollmentID uint               `json:"enrollment_id"`
+	Date         string             `json:"date"`
+	Week         int                `json:"week"`
+	Day          int                `json:"day"`
+	RestDay      bool               `json:"rest_day"`
+	Finished     bool               `json:"finished"`
+	Completed    bool               `json:"completed"`
+	ProgramDay   *models.ProgramDay `json:"program_day,omitempty"`
+}
+
+type adherenceResponse struct {
+	EnrollmentID uint    `json:"enrollment_id"`
+	Scheduled    int     `json:"scheduled"`
+	Completed    int     `json:"completed"`
+	Rate         float64 `json:"rate"`
+}
+
+// create program
+// @Summary      Create training program
+// @Tags         programs
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      programRequest  true  "Program with weeks, days and prescriptions"
+// @Success      201      {object}  models.Program
+// @Failure      400      {object}  gin.H
+// @Failure      500      {object}  gin.H
+// @Router       /programs [post]
+func (h *ProgramHandler) create(c *gin.Context) {
+	var req programRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := req.validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uid, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+	program := &models.Program{UserID: uid, Name: req.Name, Description: req.Description, Weeks: req.weeks()}
+	if err := h.repo.Create(c.Request.Context(), program); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if loaded, err := h.repo.GetByID(c.Request.Context(), program.ID); err == nil {
+		program = loaded
+	}
+	c.JSON(http.StatusCreated, program)
+}
+
+// list programs
+// @Summary      List training programs
+// @Tags         programs
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        limit   query     int     false  "Page size (default 100, max 500)"
+// @Param        cursor  query     string  false  "next_cursor of the previous page"
+// @Success      200     {object}  pagination.Page[models.Program]
+// @Failure      400     {object}  gin.H
+// @Router       /programs [get]
+func (h *ProgramHandler) list(c *gin.Context) {
+	p, err := pagination.Parse(c.Query("limit"), c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	programs, err := h.repo.List(c.Request.Context(), p.Limit+1, p.After)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pagination.NewPage(programs, p, func(program *models.Program) pagination.Cursor {
+		return pagination.Cursor{ID: program.ID}
+	}))
+}
+
+// get program
+// @Summary      Get training program by ID
+// @Tags         programs
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        id   path      int  true  "Program ID"
+// @Success      200  {object}  models.Program
+// @Failure      400  {object}  gin.H
+// @Failure      404  {object}  gin.H
+// @Router       /programs/{id} [get]
+func (h *ProgramHandler) getByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	program, err := h.repo.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, program)
+}
+
+// update program
+// @Summary      Update training program
+// @Description  Replaces the week/day/prescription tree; only the author may update. Weeks and days are matched by
+// @Description  number and keep their IDs, so sessions linked to a day stay linked; days left out are removed.
+// @Tags         programs
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int             true  "Program ID"
+// @Param        payload  body      programRequest  true  "Program"
+// @Success      200      {object}  models.Program
+// @Failure      400      {object}  gin.H
+// @Failure      404      {object}  gin.H
+// @Failure      500      {object}  gin.H
+// @Router       /programs/{id} [put]
+func (h *ProgramHandler) update(c *gin.Context) {
+	program, ok := h.authoredProgram(c)
+	if !ok {
+		return
+	}
+	var req programRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := req.validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	program.Name = req.Name
+	program.Description = req.Description
+	program.Weeks = req.weeks()
+	if err := h.repo.Update(c.Request.Context(), program); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if loaded, err := h.repo.GetByID(c.Request.Context(), program.ID); err == nil {
+		program = loaded
+	}
+	c.JSON(http.StatusOK, program)
+}
+
+// delete program
+// @Summary      Delete training program
+// @Description  Only the author can delete a program. Every enrollment in it ends, including other users'; sessions logged
+// @Description  for it are kept.
+// @Tags         programs
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id   path      int  true  "Program ID"
+// @Success      204  {string}  string  "No Content"
+// @Failure      400  {object}  gin.H
+// @Failure      404  {object}  gin.H
+// @Router       /programs/{id} [delete]
+func (h *ProgramHandler) delete(c *gin.Context) {
+	program, ok := h.authoredProgram(c)
+	if !ok {
+		return
+	}
+	if err := h.repo.Delete(c.Request.Context(), program.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// enroll in program
+// @Summary      Enroll in a training program
+// @Tags         programs
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int            true   "Program ID"
+// @Param        payload  body      enrollRequest  false  "Start date"
+// @Success      201      {object}  models.ProgramEnrollment
+// @Failure      400      {object}  gin.H
+// @Failure      404      {object}  gin.H
+// @Failure      500      {object}  gin.H
+// @Router       /programs/{id}/enroll [post]
+func (h *ProgramHandler) enroll(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	if _, err := h.repo.GetByID(c.Request.Context(), uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	var req enrollRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	start, err := parseDate(req.StartDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_date must be YYYY-MM-DD"})
+		return
+	}
+	uid, _ := middleware.UserID(c)
+	enrollment := &models.ProgramEnrollment{ProgramID: uint(id), UserID: uid, StartDate: start}
+	if err := h.enrollmentRepo.Create(c.Request.Context(), enrollment); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, enrollment)
+}
+
+// list enrollments
+// @Summary      List program enrollments of the user
+// @Tags         programs
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        limit   query     int     false  "Page size (default 100, max 500)"
+// @Param        cursor  query     string  false  "next_cursor of the previous page"
+// @Success      200     {object}  pagination.Page[models.ProgramEnrollment]
+// @Failure      400     {object}  gin.H
+// @Router       /programs/enrollments [get]
+func (h *ProgramHandler) listEnrollments(c *gin.Context) {
+	uid, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+	p, err := pagination.Parse(c.Query("limit"), c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	enrollments, err := h.enrollmentRepo.ListByUser(c.Request.Context(), uid, p.Limit+1, p.After)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pagination.NewPage(enrollments, p, func(e *models.ProgramEnrollment) pagination.Cursor {
+		return pagination.Cursor{Time: e.StartDate, ID: e.ID}
+	}))
+}
+
+// unenroll
+// @Summary      Leave a training program
+// @Tags         programs
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id   path      int  true  "ProgramEnrollment ID"
+// @Success      204  {string}  string  "No Content"
+// @Failure      400  {object}  gin.H
+// @Failure      404  {object}  gin.H
+// @Router       /programs/enrollments/{id} [delete]
+func (h *ProgramHandler) unenroll(c *gin.Context) {
+	enrollment, ok := h.ownedEnrollment(c)
+	if !ok {
+		return
+	}
+	if err := h.enrollmentRepo.Delete(c.Request.Context(), enrollment.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// today's workout
+// @Summary      Get today's scheduled workout of an enrollment
+// @Tags         programs
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        id    path      int     true   "ProgramEnrollment ID"
+// @Param        date  query     string  false  "Calendar date YYYY-MM-DD (defaults to today)"
+// @Success      200   {object}  todayResponse
+// @Failure      400   {object}  gin.H
+// @Failure      404   {object}  gin.H
+// @Router       /programs/enrollments/{id}/today [get]
+func (h *ProgramHandler) today(c *gin.Context) {
+	enrollment, ok := h.ownedEnrollment(c)
+	if !ok {
+		return
+	}
+	date, err := parseDate(c.Query("date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date must be YYYY-MM-DD"})
+		return
+	}
+	resp, err := h.todayFor(c, enrollment, date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// start today's workout
+// @Summary      Start today's scheduled workout
+// @Description  Creates a session pre-filled from today's prescriptions and links it to the scheduled day.
+// @Description  Percentage prescriptions are turned into weights from the user's estimated one-rep max.
+// @Description  API keys need the sessions:write scope besides programs:write.
+// @Tags         programs
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        id    path      int     true   "ProgramEnrollment ID"
+// @Param        date  query     string  false  "Calendar date YYYY-MM-DD (defaults to today)"
+// @Success      201   {object}  models.WorkoutSession
+// @Failure      400   {object}  gin.H
+// @Failure      403   {object}  gin.H
+// @Failure      404   {object}  gin.H
+// @Failure      409   {object}  gin.H
+// @Router       /programs/enrollments/{id}/today/start [post]
+func (h *ProgramHandler) startToday(c *gin.Context) {
+	enrollment, ok := h.ownedEnrollment(c)
+	if !ok {
+		return
+	}
+	date, err := parseDate(c.Query("date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date must be YYYY-MM-DD"})
+		return
+	}
+	week, day, _ := enrollment.Schedule(date)
+	programDay := enrollment.Program.Day(week, day)
+	if programDay == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "nothing scheduled for this date"})
+		return
+	}
+
+	session := programDay.NewSession(enrollment.UserID, startOn(date))
+	if err := h.prescribeWeights(c, programDay, session); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	session.ProgramEnrollmentID = &enrollment.ID
+	session.ProgramDayID = &programDay.ID
+	if err := h.sessionRepo.Create(c.Request.Context(), session); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if loaded, err := h.sessionRepo.GetByID(c.Request.Context(), session.ID); err == nil {
+		session = loaded
+	}
+	c.JSON(http.StatusCreated, session)
+}
+
+// complete a scheduled day
+// @Summary      Link a logged session to a scheduled program day
+// @Tags         programs
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                 true  "ProgramEnrollment ID"
+// @Param        payload  body      completeDayRequest  true  "Session to link"
+// @Success      200      {object}  models.WorkoutSession
+// @Failure      400      {object}  gin.H
+// @Failure      404      {object}  gin.H
+// @Failure      409      {object}  gin.H
+// @Router       /programs/enrollments/{id}/complete [post]
+func (h *ProgramHandler) complete(c *gin.Context) {
+	enrollment, ok := h.ownedEnrollment(c)
+	if !ok {
+		return
+	}
+	var req completeDayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	session, err := h.sessionRepo.GetByID(c.Request.Context(), req.SessionID)
+	if err != nil || session.UserID != enrollment.UserID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		return
+	}
+
+	var programDay *models.ProgramDay
+	if req.ProgramDayID != nil {
+		programDay = findDay(enrollment.Program, *req.ProgramDayID)
+		if programDay == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "day does not belong to program"})
+			return
+		}
+	} else {
+		week, day, _ := enrollment.Schedule(session.Datetime)
+		if programDay = enrollment.Program.Day(week, day); programDay == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "nothing scheduled on the session date"})
+			return
+		}
+	}
+
+	session.ProgramEnrollmentID = &enrollment.ID
+	session.ProgramDayID = &programDay.ID
+	if err := h.sessionRepo.Update(c.Request.Context(), session); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, session)
+}
+
+// adherence
+// @Summary      Program adherence of an enrollment
+// @Description  Counts scheduled days up to the given date and how many have a linked session
+// @Tags         programs
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        id    path      int     true   "ProgramEnrollment ID"
+// @Param        date  query     string  false  "Calendar date YYYY-MM-DD (defaults to today)"
+// @Success      200   {object}  adherenceResponse
+// @Failure      400   {object}  gin.H
+// @Failure      404   {object}  gin.H
+// @Router       /programs/enrollments/{id}/adherence [get]
+func (h *ProgramHandler) adherence(c *gin.Context) {
+	enrollment, ok := h.ownedEnrollment(c)
+	if !ok {
+		return
+	}
+	date, err := parseDate(c.Query("date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date must be YYYY-MM-DD"})
+		return
+	}
+	doneIDs, err := h.enrollmentRepo.CompletedDayIDs(c.Request.Context(), enrollment.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	done := make(map[uint]bool, len(doneIDs))
+	for _, id := range doneIDs {
+		done[id] = true
+	}
+
+	resp := adherenceResponse{EnrollmentID: enrollment.ID}
+	for _, w := range enrollment.Program.Weeks {
+		for _, d := range w.Days {
+			scheduled := enrollment.DateOf(w.WeekNumber, d.DayNumber)
+			// today only counts once it is done, so the rate does not drop in the morning
+			if scheduled.Before(date) || (scheduled.Equal(date) && done[d.ID]) {
+				resp.Scheduled++
+				if done[d.ID] {
+					resp.Completed++
+				}
+			}
+		}
+	}
+	if resp.Scheduled > 0 {
+		resp.Rate = float64(resp.Completed) / float64(resp.Scheduled)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// todayFor assembles the schedule of an enrollment on date.
+func (h *ProgramHandler) todayFor(c *gin.Context, enrollment *models.ProgramEnrollment, date time.Time) (*todayResponse, error) {
+	resp := &todayResponse{EnrollmentID: enrollment.ID, Date: date.Format(dateLayout)}
+	week, day, ok := enrollment.Schedule(date)
+	if !ok {
+		resp.RestDay = true
+		return resp, nil
+	}
+	resp.Week, resp.Day = week, day
+	resp.Finished = week > enrollment.Program.LastWeek()
+	resp.ProgramDay = enrollment.Program.Day(week, day)
+	resp.RestDay = resp.ProgramDay == nil
+	if resp.ProgramDay == nil {
+		return resp, nil
+	}
+
+	doneIDs, err := h.enrollmentRepo.CompletedDayIDs(c.Request.Context(), enrollment.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range doneIDs {
+		if id == resp.ProgramDay.ID {
+			resp.Completed = true
+		}
+	}
+	return resp, nil
+}
+
+// prescribeWeights fills in the planned weight of percentage-based prescriptions. Exercises
+// without a recent estimated one-rep max keep a weight of zero.
+func (h *ProgramHandler) prescribeWeights(c *gin.Context, day *models.ProgramDay, session *models.WorkoutSession) error {
+	for i, p := range day.Prescriptions {
+		if p.PercentOneRM == nil || p.Weight != nil || i >= len(session.Exercises) {
+			continue
+		}
+		kg, ok, err := h.oneRM.Prescribe(c.Request.Context(), session.UserID, p.WorkoutTypeID, *p.PercentOneRM)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		for si := range session.Exercises[i].Sets {
+			session.Exercises[i].Sets[si].Weight = kg
+			session.Exercises[i].Sets[si].Unit = models.UnitKilograms
+		}
+	}
+	return nil
+}
+
+// authoredProgram loads the program from the :id path parameter and verifies the caller authored it.
+func (h *ProgramHandler) authoredProgram(c *gin.Context) (*models.Program, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return nil, false
+	}
+	program, err := h.repo.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	uid, _ := middleware.UserID(c)
+	if program.UserID != uid {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return nil, false
+	}
+	return program, true
+}
+
+// ownedEnrollment loads the enrollment from the :id path parameter and verifies it belongs to the caller.
+func (h *ProgramHandler) ownedEnrollment(c *gin.Context) (*models.ProgramEnrollment, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return nil, false
+	}
+	enrollment, err := h.enrollmentRepo.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	uid, _ := middleware.UserID(c)
+	if enrollment.UserID != uid || enrollment.Program == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return nil, false
+	}
+	return enrollment, true
+}
+
+// findDay looks a day up by ID anywhere in the program.
+func findDay(p *models.Program, id uint) *models.ProgramDay {
+	for wi := range p.Weeks {
+		for di := range p.Weeks[wi].Days {
+			if p.Weeks[wi].Days[di].ID == id {
+				return &p.Weeks[wi].Days[di]
+			}
+		}
+	}
+	return nil
+}
+
+// startOn returns the current time of day on date, so a session started for a scheduled day
+// is dated on that day.
+func startOn(date time.Time) time.Time {
+	now := time.Now().UTC()
+	return date.Add(now.Sub(now.Truncate(24 * time.Hour)))
+}
+
+// parseDate parses a YYYY-MM-DD calendar date as UTC midnight; empty means today.
+func parseDate(v string) (time.Time, error) {
+	if v == "" {
+		y, m, d := time.Now().Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC), nil
+	}
+	return time.Parse(dateLayout, v)
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/middleware"
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/use_case"
+)
+
+// RecordHandler exposes the personal records of the authenticated user.
+type RecordHandler struct {
+	records *use_case.RecordService
+}
+
+func NewRecordHandler(records *use_case.RecordService) *RecordHandler {
+	return &RecordHandler{records: records}
+}
+
+func (h *RecordHandler) RegisterRoutes(r *gin.Engine, auth gin.HandlerFunc) {
+	read := middleware.RequireScope(middleware.ScopeSessionsRead)
+	r.GET("/users/me/records", auth, read, h.listMine)
+	r.GET("/workout-types/:id/records", auth, read, h.listByType)
+}
+
+// list current records
+// @Summary      List current personal records of the user
+// @Description  One record per workout type and kind (heaviest_weight, most_reps per weight, estimated_1rm, volume)
+// @Tags         records
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        limit   query     int     false  "Page size (default 100, max 500)"
+// @Param        cursor  query     string  false  "next_cursor of the previous page"
+// @Success      200     {object}  pagination.Page[models.PersonalRecord]
+// @Failure      400     {object}  gin.H
+// @Failure      500     {object}  gin.H
+// @Router       /users/me/records [get]
+func (h *RecordHandler) listMine(c *gin.Context) {
+	uid, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+	p, err := pagination.Parse(c.Query("limit"), c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	records, err := h.records.Current(c.Request.Context(), uid, p.Limit+1, p.After)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pagination.NewPage(records, p, func(r *models.PersonalRecord) pagination.Cursor {
+		return pagination.Cursor{Key: r.Key(), ID: r.WorkoutTypeID}
+	}))
+}
+
+// record history for a workout type
+// @Summary      Personal record history of the user for a workout type
+// @Tags         records
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        id      path      int     true   "WorkoutType ID"
+// @Param        limit   query     int     false  "Page size (default 100, max 500)"
+// @Param        cursor  query     string  false  "next_cursor of the previous page"
+// @Success      200     {object}  pagination.Page[models.PersonalRecord]
+// @Failure      400     {object}  gin.H
+// @Failure      500     {object}  gin.H
+// @Router       /workout-types/{id}/records [get]
+func (h *RecordHandler) listByType(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	uid, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+	p, err := pagination.Parse(c.Query("limit"), c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	records, err := h.records.History(c.Request.Context(), uid, uint(id), p.Limit+1, p.After)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pagination.NewPage(records, p, func(r *models.PersonalRecord) pagination.Cursor {
+		return pagination.Cursor{Time: r.AchievedAt, ID: r.ID}
+	}))
+}
+
+// detectRecords reloads the stored session and checks it for new personal records. The session
+// is already saved at this point, so failures are logged rather than failing the request.
+func detectRecords(c *gin.Context, records *use_case.RecordService, repo repository.WorkoutSessionRepository, sessionID uint) []*models.PersonalRecord {
+	session, err := repo.GetByID(c.Request.Context(), sessionID)
+	if err != nil {
+		log.Printf("warning: personal records: load session %d: %v", sessionID, err)
+		return nil
+	}
+	found, err := records.Detect(c.Request.Context(), session)
+	if err != nil {
+		log.Printf("warning: personal records: session %d: %v", sessionID, err)
+		return nil
+	}
+	return found
+}
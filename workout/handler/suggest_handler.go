@@ -1,25 +1,84 @@
 package handler
 
 import (
+	"context"
+	"errors"
+	"log"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/VibeTeam/fitness-tracker-backend/llm/suggester"
 	"github.com/VibeTeam/fitness-tracker-backend/shared/middleware"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
 	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/use_case"
 )
 
-// SuggestHandler returns AI-based workout suggestions.
+// DefaultLLMTimeout bounds a language model call before the rule engine answers instead.
+const DefaultLLMTimeout = time.Minute
+
+// SuggestHandler returns AI-based workout suggestions. When the language model fails or
+// times out, the rule engine suggests instead.
 type SuggestHandler struct {
 	sessionRepo repository.WorkoutSessionRepository
+	typeRepo    repository.WorkoutTypeRepository
 	suggester   *suggester.Suggester
+	oneRM       *use_case.OneRepMaxService
+	rules       *use_case.RuleSuggester
+	llmTimeout  time.Duration
 }
 
 type suggestionResponse struct {
 	Suggestion string `json:"suggestion"`
+	// Engine is llm or, when the language model was unavailable, rules.
+	Engine string `json:"engine"`
+}
+
+// suggestionChunk is the payload of a "chunk" event of the suggestion stream.
+type suggestionChunk struct {
+	Text string `json:"text"`
+}
+
+// suggestionSummary is the payload of the final "done" event of the suggestion stream.
+type suggestionSummary struct {
+	// Suggestion is the whole text, for clients that missed or dropped chunks.
+	Suggestion string `json:"suggestion"`
+	Engine     string `json:"engine"`
+	Chunks     int    `json:"chunks"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// workoutPlanResponse is a structured suggestion. Posting it to /suggest-workout/plan/start,
+// as is or edited, turns it into a draft session.
+type workoutPlanResponse struct {
+	Title     string                `json:"title"`
+	Rationale string                `json:"rationale"`
+	Exercises []workoutPlanExercise `json:"exercises"`
+	// Engine is llm or rules; a rules plan for a rest day has no exercises.
+	Engine string `json:"engine"`
+}
+
+// workoutPlanExercise uses the fields of a template exercise, so a plan can also be saved as a template.
+type workoutPlanExercise struct {
+	WorkoutTypeID uint    `json:"workout_type_id"`
+	WorkoutType   string  `json:"workout_type"`
+	TargetSets    int     `json:"target_sets"`
+	TargetReps    int     `json:"target_reps"`
+	TargetWeight  float64 `json:"target_weight"`
+	Unit          string  `json:"unit"`
+	RestSeconds   int     `json:"rest_seconds"`
+	// Notes holds the model's reason for the exercise; rule engine plans leave it empty.
+	Notes string `json:"notes"`
+}
+
+type startPlanRequest struct {
+	Datetime  time.Time                        `json:"datetime"`
+	Exercises []workoutTemplateExerciseRequest `json:"exercises" binding:"required,min=1,dive"`
 }
 
 // errorResponse is used for Swagger documentation of error payloads.
@@ -27,43 +86,316 @@ type errorResponse struct {
 	Error string `json:"error"`
 }
 
-func NewSuggestHandler(repo repository.WorkoutSessionRepository, sg *suggester.Suggester) *SuggestHandler {
-	return &SuggestHandler{sessionRepo: repo, suggester: sg}
+func NewSuggestHandler(repo repository.WorkoutSessionRepository, typeRepo repository.WorkoutTypeRepository, sg *suggester.Suggester, oneRM *use_case.OneRepMaxService) *SuggestHandler {
+	return &SuggestHandler{
+		sessionRepo: repo,
+		typeRepo:    typeRepo,
+		suggester:   sg,
+		oneRM:       oneRM,
+		rules:       use_case.NewRuleSuggester(repo, typeRepo),
+		llmTimeout:  DefaultLLMTimeout,
+	}
+}
+
+// SetLLMTimeout changes how long the language model may take before the rule engine answers.
+func (h *SuggestHandler) SetLLMTimeout(d time.Duration) {
+	h.llmTimeout = d
 }
 
 func (h *SuggestHandler) RegisterRoutes(r *gin.Engine, auth gin.HandlerFunc) {
 	g := r.Group("/suggest-workout")
-	g.Use(auth)
+	g.Use(auth, middleware.RequireScope(middleware.ScopeSuggestionsRead))
 	g.GET("", h.suggest)
+	g.GET("/stream", h.stream)
+	g.GET("/plan", h.plan)
+	g.POST("/plan/start", middleware.RequireScope(middleware.ScopeSessionsWrite), h.startPlan)
 }
 
 // Suggest workout
 // @Summary      Suggest next workout
+// @Description  Answers with JSON once the whole suggestion is generated. Clients sending Accept: text/event-stream get the
+// @Description  stream of /suggest-workout/stream instead. When the language model fails or times out, the rule engine
+// @Description  suggests training the muscle group that has rested longest, and engine is "rules".
 // @Tags         workout-suggestions
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Produce      json
 // @Success      200  {object}  suggestionResponse
 // @Failure      500  {object}  errorResponse
 // @Router       /suggest-workout [get]
 func (h *SuggestHandler) suggest(c *gin.Context) {
+	if acceptsEventStream(c) {
+		h.stream(c)
+		return
+	}
+	uid, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+	history, err := trainingHistory(c.Request.Context(), h.sessionRepo, h.oneRM, uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if history == "" {
+		c.JSON(http.StatusOK, suggestionResponse{Suggestion: noHistorySuggestion, Engine: use_case.EngineRules})
+		return
+	}
+	llmCtx, cancel := context.WithTimeout(c.Request.Context(), h.llmTimeout)
+	defer cancel()
+	suggestion, err := h.suggester.Suggest(llmCtx, history)
+	if err == nil {
+		c.JSON(http.StatusOK, suggestionResponse{Suggestion: suggestion, Engine: use_case.EngineLLM})
+		return
+	}
+	suggestion, err = h.fallbackText(c.Request.Context(), uid, err)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, suggestionResponse{Suggestion: suggestion, Engine: use_case.EngineRules})
+}
+
+// Stream workout suggestion
+// @Summary      Stream next workout suggestion
+// @Description  Server-sent events: a "chunk" event with {"text"} for every piece of the suggestion as it is generated, then
+// @Description  a "done" event with the summary below, or an "error" event with {"error"} if generation fails midway. The rule
+// @Description  engine takes over when the language model fails before sending any text.
+// @Tags         workout-suggestions
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      text/event-stream
+// @Success      200  {object}  suggestionSummary
+// @Failure      500  {object}  errorResponse
+// @Router       /suggest-workout/stream [get]
+func (h *SuggestHandler) stream(c *gin.Context) {
 	uid, ok := middleware.UserID(c)
 	if !ok {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
 		return
 	}
-	// fetch last 10 sessions
-	sessions, err := h.sessionRepo.ListByUser(c.Request.Context(), uid, 10, 0)
+	ctx := c.Request.Context()
+	history, err := trainingHistory(ctx, h.sessionRepo, h.oneRM, uid)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	if len(sessions) == 0 {
-		c.JSON(http.StatusOK, suggestionResponse{Suggestion: "No history yet. Start with a full-body beginner routine."})
+
+	startEventStream(c)
+
+	started := time.Now()
+	chunks := 0
+	send := func(text string) error {
+		chunks++
+		return sendChunk(c, text)
+	}
+
+	llmCtx, cancel := context.WithTimeout(ctx, h.llmTimeout)
+	defer cancel()
+	engine, suggestion := use_case.EngineRules, noHistorySuggestion
+	if history == "" {
+		err = send(suggestion)
+	} else {
+		engine = use_case.EngineLLM
+		suggestion, err = h.suggester.SuggestStream(llmCtx, history, send)
+		// once text has been streamed the rules cannot take over without mixing two suggestions
+		if err != nil && chunks == 0 && ctx.Err() == nil {
+			if suggestion, err = h.fallbackText(ctx, uid, err); err == nil {
+				engine = use_case.EngineRules
+				err = send(suggestion)
+			}
+		}
+	}
+	if err != nil {
+		sendError(c, err)
 		return
 	}
+	sendEvent(c, "done", suggestionSummary{Suggestion: suggestion, Engine: engine, Chunks: chunks, DurationMS: time.Since(started).Milliseconds()})
+}
+
+// Suggest workout plan
+// @Summary      Suggest next workout as a structured plan
+// @Description  Asks the model for exercises from the workout type catalog with sets, reps, target weights and a rationale.
+// @Description  The answer is validated and repaired server-side and every exercise carries its workout_type_id. When the
+// @Description  language model fails, times out or produces no valid plan, the rule engine plans instead.
+// @Tags         workout-suggestions
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Success      200  {object}  workoutPlanResponse
+// @Failure      409  {object}  errorResponse  "No workout types exist yet"
+// @Failure      500  {object}  errorResponse
+// @Router       /suggest-workout/plan [get]
+func (h *SuggestHandler) plan(c *gin.Context) {
+	uid, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+	ctx := c.Request.Context()
+	types, err := h.typeRepo.List(ctx, maxPlanCatalog, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(types) == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "no workout types to plan with"})
+		return
+	}
+	history, err := trainingHistory(ctx, h.sessionRepo, h.oneRM, uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if history == "" {
+		history = "no workouts logged yet, plan a full-body beginner routine"
+	}
+
+	catalog := make([]string, len(types))
+	ids := make(map[string]uint, len(types))
+	for i, wt := range types {
+		catalog[i] = wt.Name
+		if _, dup := ids[wt.Name]; !dup {
+			ids[wt.Name] = wt.ID
+		}
+	}
+	llmCtx, cancel := context.WithTimeout(ctx, h.llmTimeout)
+	defer cancel()
+	plan, err := h.suggester.SuggestPlan(llmCtx, history, catalog)
+	if err != nil {
+		rules, err := h.fallback(ctx, uid, err)
+		if errors.Is(err, use_case.ErrNoWorkoutTypes) {
+			c.JSON(http.StatusConflict, gin.H{"error": "no workout types to plan with"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, rulesPlan(rules))
+		return
+	}
+
+	res := workoutPlanResponse{Title: plan.Title, Rationale: plan.Rationale, Exercises: make([]workoutPlanExercise, len(plan.Exercises)), Engine: use_case.EngineLLM}
+	for i, e := range plan.Exercises {
+		res.Exercises[i] = workoutPlanExercise{
+			WorkoutTypeID: ids[e.WorkoutType],
+			WorkoutType:   e.WorkoutType,
+			TargetSets:    e.Sets,
+			TargetReps:    e.Reps,
+			TargetWeight:  e.TargetWeight,
+			Unit:          e.Unit,
+			RestSeconds:   e.RestSeconds,
+			Notes:         e.Rationale,
+		}
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// Start workout plan
+// @Summary      Start a workout session from a suggested plan
+// @Description  Creates a draft session with the plan's exercises and planned sets, like starting a template. Needs the
+// @Description  sessions:write scope in addition to suggestions:read when called with an API key.
+// @Tags         workout-suggestions
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      startPlanRequest  true  "Plan from /suggest-workout/plan; datetime defaults to now"
+// @Success      201      {object}  models.WorkoutSession
+// @Failure      400      {object}  errorResponse
+// @Failure      500      {object}  errorResponse
+// @Router       /suggest-workout/plan/start [post]
+func (h *SuggestHandler) startPlan(c *gin.Context) {
+	var req startPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uid, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+	ctx := c.Request.Context()
+	for _, e := range req.Exercises {
+		if _, err := h.typeRepo.GetByID(ctx, e.WorkoutTypeID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown workout_type_id " + strconv.FormatUint(uint64(e.WorkoutTypeID), 10)})
+			return
+		}
+	}
+	if req.Datetime.IsZero() {
+		req.Datetime = time.Now()
+	}
+
+	// a plan is started like an unsaved template
+	template := &models.WorkoutTemplate{Exercises: workoutTemplateRequest{Exercises: req.Exercises}.exercises()}
+	session := template.NewSession(uid, req.Datetime)
+	if err := h.sessionRepo.Create(ctx, session); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if loaded, err := h.sessionRepo.GetByID(ctx, session.ID); err == nil {
+		session = loaded
+	}
+	c.JSON(http.StatusCreated, session)
+}
+
+// fallback logs why the language model failed and returns the rule engine's suggestion instead.
+func (h *SuggestHandler) fallback(ctx context.Context, uid uint, llmErr error) (*use_case.RuleSuggestion, error) {
+	log.Printf("workout suggestion for user %d: language model failed, using rules: %v", uid, llmErr)
+	return h.rules.Suggest(ctx, uid, time.Now())
+}
+
+// fallbackText is fallback as text. Without workout types to choose from, the rules suggest
+// what users without history get.
+func (h *SuggestHandler) fallbackText(ctx context.Context, uid uint, llmErr error) (string, error) {
+	rules, err := h.fallback(ctx, uid, llmErr)
+	if errors.Is(err, use_case.ErrNoWorkoutTypes) {
+		return noHistorySuggestion, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return rules.Text(), nil
+}
+
+// rulesPlan turns a rule engine suggestion into a plan.
+func rulesPlan(rules *use_case.RuleSuggestion) workoutPlanResponse {
+	res := workoutPlanResponse{Title: "Rest day", Rationale: rules.Reason, Exercises: []workoutPlanExercise{}, Engine: use_case.EngineRules}
+	if !rules.Rest {
+		res.Title = "Train " + rules.MuscleGroup
+	}
+	for _, e := range rules.Exercises {
+		res.Exercises = append(res.Exercises, workoutPlanExercise{
+			WorkoutTypeID: e.WorkoutTypeID,
+			WorkoutType:   e.WorkoutType,
+			TargetSets:    e.Sets,
+			TargetReps:    e.Reps,
+			TargetWeight:  e.Weight,
+			Unit:          e.Unit,
+			RestSeconds:   e.RestSeconds,
+		})
+	}
+	return res
+}
+
+// maxPlanCatalog caps the workout types a plan is chosen from.
+const maxPlanCatalog = 200
+
+// noHistorySuggestion is suggested to users without any sessions, without asking the model.
+const noHistorySuggestion = "No history yet. Start with a full-body beginner routine."
+
+// trainingHistory describes the last 10 sessions of a user for the model; it is empty without sessions.
+func trainingHistory(ctx context.Context, sessionRepo repository.WorkoutSessionRepository, oneRM *use_case.OneRepMaxService, uid uint) (string, error) {
+	sessions, err := sessionRepo.ListByUser(ctx, uid, 10, nil)
+	if err != nil || len(sessions) == 0 {
+		return "", err
+	}
 	var parts []string
 	for idx, s := range sessions {
-		line := "Session " + strconv.Itoa(idx+1) + ": " + s.WorkoutType.Name
+		line := "Session " + strconv.Itoa(idx+1) + ": " + sessionTitle(s)
 		if len(s.Details) > 0 {
 			var dParts []string
 			for _, d := range s.Details {
@@ -73,11 +405,91 @@ func (h *SuggestHandler) suggest(c *gin.Context) {
 		}
 		parts = append(parts, line)
 	}
-	history := strings.Join(parts, "\n")
-	suggestion, err := h.suggester.Suggest(history)
+	// estimated maxes let the model prescribe loads as percentages
+	maxes, err := oneRM.CurrentByType(ctx, uid)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		return "", err
+	}
+	if line := oneRepMaxLine(sessions, maxes); line != "" {
+		parts = append(parts, line)
+	}
+	return strings.Join(parts, "\n"), nil
+}
+
+// acceptsEventStream reports whether the client asked for server-sent events.
+func acceptsEventStream(c *gin.Context) bool {
+	return strings.Contains(c.GetHeader("Accept"), "text/event-stream")
+}
+
+// startEventStream answers with a stream of server-sent events; c.SSEvent sets the content type.
+func startEventStream(c *gin.Context) {
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// keep reverse proxies such as nginx from buffering the stream
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+}
+
+// sendEvent writes an event and flushes it to the client right away.
+func sendEvent(c *gin.Context, event string, data any) {
+	c.SSEvent(event, data)
+	c.Writer.Flush()
+}
+
+// sendChunk sends a "chunk" event with a piece of generated text. Its error, set once the
+// client has disconnected, aborts the generation.
+func sendChunk(c *gin.Context, text string) error {
+	// the request context ends when the client disconnects
+	if err := c.Request.Context().Err(); err != nil {
+		return err
+	}
+	sendEvent(c, "chunk", suggestionChunk{Text: text})
+	return c.Request.Context().Err()
+}
+
+// sendError ends a stream with an "error" event, unless the client is already gone.
+func sendError(c *gin.Context, err error) {
+	if c.Request.Context().Err() == nil {
+		sendEvent(c, "error", errorResponse{Error: err.Error()})
+	}
+}
+
+// sessionTitle names the exercises of a session in order, falling back to its primary type.
+func sessionTitle(s *models.WorkoutSession) string {
+	var names []string
+	for _, ex := range s.Exercises {
+		if ex.WorkoutType != nil {
+			names = append(names, ex.WorkoutType.Name)
+		}
+	}
+	if len(names) == 0 && s.WorkoutType != nil {
+		return s.WorkoutType.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// oneRepMaxLine lists the estimated one-rep maxes of the workout types in sessions.
+func oneRepMaxLine(sessions []*models.WorkoutSession, maxes map[uint]float64) string {
+	names := make(map[uint]string)
+	for _, s := range sessions {
+		if s.WorkoutType != nil {
+			names[s.WorkoutTypeID] = s.WorkoutType.Name
+		}
+		for _, ex := range s.Exercises {
+			if ex.WorkoutType != nil {
+				names[ex.WorkoutTypeID] = ex.WorkoutType.Name
+			}
+		}
+	}
+	var parts []string
+	for id, kg := range maxes {
+		if name, ok := names[id]; ok {
+			parts = append(parts, name+" "+strconv.FormatFloat(kg, 'f', 1, 64)+" kg")
+		}
+	}
+	if len(parts) == 0 {
+		return ""
 	}
-	c.JSON(http.StatusOK, suggestionResponse{Suggestion: suggestion})
+	sort.Strings(parts)
+	return "Estimated one-rep maxes: " + strings.Join(parts, ", ")
 }
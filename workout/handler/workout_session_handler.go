@@ -1,41 +1,93 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 
 	"github.com/VibeTeam/fitness-tracker-backend/shared/middleware"
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
 	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
 	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/use_case"
 )
 
 type WorkoutSessionHandler struct {
 	repo       repository.WorkoutSessionRepository
 	detailRepo repository.WorkoutDetailRepository
+	records    *use_case.RecordService
 }
 
-func NewWorkoutSessionHandler(repo repository.WorkoutSessionRepository, detailRepo repository.WorkoutDetailRepository) *WorkoutSessionHandler {
-	return &WorkoutSessionHandler{repo: repo, detailRepo: detailRepo}
+func NewWorkoutSessionHandler(repo repository.WorkoutSessionRepository, detailRepo repository.WorkoutDetailRepository, records *use_case.RecordService) *WorkoutSessionHandler {
+	return &WorkoutSessionHandler{repo: repo, detailRepo: detailRepo, records: records}
 }
 
 func (h *WorkoutSessionHandler) RegisterRoutes(r *gin.Engine, auth gin.HandlerFunc) {
 	ws := r.Group("/workout-sessions")
-	ws.Use(auth)
+	ws.Use(auth, middleware.RequireAccess(middleware.ScopeSessionsRead, middleware.ScopeSessionsWrite))
 	{
 		ws.POST("", h.create)
 		ws.GET("", h.list)
 		ws.GET("/:id", h.getByID)
+		ws.PUT("/:id", h.update)
 		ws.DELETE("/:id", h.delete)
 		ws.POST("/:id/details", h.addDetail)
+		ws.POST("/:id/exercises", h.addExercise)
+		ws.PUT("/:id/exercises/order", h.reorderExercises)
+		ws.DELETE("/:id/exercises/:exercise_id", h.removeExercise)
 	}
 }
 
+// workoutSessionRequest creates a session either from a single workout_type_id
+// or from an ordered list of exercises.
 type workoutSessionRequest struct {
-	WorkoutTypeID uint      `json:"workout_type_id" binding:"required"`
-	Datetime      time.Time `json:"datetime"`
+	WorkoutTypeID uint                     `json:"workout_type_id"`
+	Datetime      time.Time                `json:"datetime"`
+	EndedAt       *time.Time               `json:"ended_at"`
+	Exercises     []workoutExerciseRequest `json:"exercises" binding:"dive"`
+}
+
+// workoutExerciseRequest is one exercise of a session, optionally with its sets.
+type workoutExerciseRequest struct {
+	WorkoutTypeID uint                `json:"workout_type_id" binding:"required"`
+	Notes         string              `json:"notes"`
+	Sets          []workoutSetRequest `json:"sets" binding:"dive"`
+}
+
+// toModel builds the exercise at the given 1-based position.
+func (req workoutExerciseRequest) toModel(position int) models.WorkoutExercise {
+	ex := models.WorkoutExercise{WorkoutTypeID: req.WorkoutTypeID, Position: position, Notes: req.Notes}
+	for i, sr := range req.Sets {
+		set := models.WorkoutSet{SetIndex: i + 1}
+		sr.apply(&set)
+		ex.Sets = append(ex.Sets, set)
+	}
+	return ex
+}
+
+type workoutSessionUpdateRequest struct {
+	Datetime *time.Time `json:"datetime"`
+	EndedAt  *time.Time `json:"ended_at"`
+}
+
+type exerciseOrderRequest struct {
+	ExerciseIDs []uint `json:"exercise_ids" binding:"required"`
+}
+
+// sessionResponse is a saved session together with the personal records it set.
+type sessionResponse struct {
+	*models.WorkoutSession
+	NewRecords []*models.PersonalRecord `json:"new_records,omitempty"`
+}
+
+// detailResponse is a saved detail together with the personal records it set.
+type detailResponse struct {
+	*models.WorkoutDetail
+	NewRecords []*models.PersonalRecord `json:"new_records,omitempty"`
 }
 
 // detail request DTO
@@ -48,10 +100,13 @@ type workoutDetailRequest struct {
 // @Summary      Create workout session
 // @Tags         workout-sessions
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
+// @Description  Accepts either a single workout_type_id or an ordered list of exercises with their sets.
+// @Description  new_records lists the personal records set by the session.
 // @Param        payload  body      workoutSessionRequest  true  "Session"
-// @Success      201      {object}  models.WorkoutSession
+// @Success      201      {object}  sessionResponse
 // @Failure      400      {object}  gin.H
 // @Failure      500      {object}  gin.H
 // @Router       /workout-sessions [post]
@@ -66,26 +121,44 @@ func (h *WorkoutSessionHandler) create(c *gin.Context) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
 		return
 	}
+	if req.WorkoutTypeID == 0 && len(req.Exercises) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "workout_type_id or exercises is required"})
+		return
+	}
+	if len(req.Exercises) == 0 {
+		req.Exercises = []workoutExerciseRequest{{WorkoutTypeID: req.WorkoutTypeID}}
+	}
+	if req.WorkoutTypeID == 0 {
+		req.WorkoutTypeID = req.Exercises[0].WorkoutTypeID
+	}
 	if req.Datetime.IsZero() {
 		req.Datetime = time.Now()
 	}
-	session := &models.WorkoutSession{UserID: uid, WorkoutTypeID: req.WorkoutTypeID, Datetime: req.Datetime}
+	if req.EndedAt != nil && req.EndedAt.Before(req.Datetime) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ended_at must not be before datetime"})
+		return
+	}
+	session := &models.WorkoutSession{UserID: uid, WorkoutTypeID: req.WorkoutTypeID, Datetime: req.Datetime, EndedAt: req.EndedAt}
+	for i, er := range req.Exercises {
+		session.Exercises = append(session.Exercises, er.toModel(i+1))
+	}
 	if err := h.repo.Create(c.Request.Context(), session); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusCreated, session)
+	c.JSON(http.StatusCreated, sessionResponse{session, detectRecords(c, h.records, h.repo, session.ID)})
 }
 
 // add detail
 // @Summary      Add detail to workout session
 // @Tags         workout-sessions
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id       path      int                  true  "WorkoutSession ID"
 // @Param        payload  body      workoutDetailRequest true  "Detail"
-// @Success      201      {object}  models.WorkoutDetail
+// @Success      201      {object}  detailResponse
 // @Failure      400      {object}  gin.H
 // @Failure      404      {object}  gin.H
 // @Failure      500      {object}  gin.H
@@ -125,17 +198,32 @@ func (h *WorkoutSessionHandler) addDetail(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusCreated, detail)
+	c.JSON(http.StatusCreated, detailResponse{detail, detectRecords(c, h.records, h.repo, session.ID)})
+}
+
+// sessionPage is a page of sessions together with the number of sessions matching the filters.
+type sessionPage struct {
+	Items      []*models.WorkoutSession `json:"items"`
+	NextCursor string                   `json:"next_cursor,omitempty"`
+	Total      int                      `json:"total"`
 }
 
 // list sessions for user
 // @Summary      List workout sessions for user
 // @Tags         workout-sessions
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Produce      json
-// @Param        limit   query     int  false  "Limit"
-// @Param        offset  query     int  false  "Offset"
-// @Success      200  {array}   models.WorkoutSession
+// @Param        from             query     string  false  "Sessions on or after this date (YYYY-MM-DD or RFC3339)"
+// @Param        to               query     string  false  "Sessions before the end of this date (YYYY-MM-DD) or before this instant (RFC3339)"
+// @Param        workout_type_id  query     int     false  "Sessions containing this workout type"
+// @Param        muscle_group_id  query     int     false  "Sessions training this muscle group"
+// @Param        has_details      query     bool    false  "Only sessions with (true) or without (false) details"
+// @Param        sort             query     string  false  "datetime order: desc (default) or asc"
+// @Param        limit            query     int     false  "Page size (default 100, max 500)"
+// @Param        cursor           query     string  false  "next_cursor of the previous page"
+// @Success      200  {object}  sessionPage
+// @Failure      400  {object}  gin.H
 // @Router       /workout-sessions [get]
 func (h *WorkoutSessionHandler) list(c *gin.Context) {
 	uid, ok := middleware.UserID(c)
@@ -143,20 +231,100 @@ func (h *WorkoutSessionHandler) list(c *gin.Context) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
 		return
 	}
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	sessions, err := h.repo.ListByUser(c.Request.Context(), uid, limit, offset)
+	q, err := sessionQuery(c, uid)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	p, err := pagination.Parse(c.Query("limit"), c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	q.Limit, q.After = p.Limit+1, p.After
+
+	sessions, err := h.repo.Query(c.Request.Context(), q)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, sessions)
+	total, err := h.repo.CountByUser(c.Request.Context(), q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	page := pagination.NewPage(sessions, p, func(s *models.WorkoutSession) pagination.Cursor {
+		return pagination.Cursor{Time: s.Datetime, ID: s.ID}
+	})
+	c.JSON(http.StatusOK, sessionPage{Items: page.Items, NextCursor: page.NextCursor, Total: total})
+}
+
+// sessionQuery reads the list filters of a request; paging is read separately.
+func sessionQuery(c *gin.Context, uid uint) (repository.SessionQuery, error) {
+	q := repository.SessionQuery{UserID: uid}
+
+	if v := c.Query("from"); v != "" {
+		t, _, err := parseBound(v)
+		if err != nil {
+			return q, errors.New("from must be YYYY-MM-DD or RFC3339")
+		}
+		q.From = &t
+	}
+	if v := c.Query("to"); v != "" {
+		t, dateOnly, err := parseBound(v)
+		if err != nil {
+			return q, errors.New("to must be YYYY-MM-DD or RFC3339")
+		}
+		if dateOnly {
+			t = t.AddDate(0, 0, 1)
+		}
+		q.To = &t
+	}
+	if v := c.Query("workout_type_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id <= 0 {
+			return q, errors.New("invalid workout_type_id")
+		}
+		q.WorkoutTypeID = uint(id)
+	}
+	if v := c.Query("muscle_group_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id <= 0 {
+			return q, errors.New("invalid muscle_group_id")
+		}
+		q.MuscleGroupID = uint(id)
+	}
+	if v := c.Query("has_details"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return q, errors.New("has_details must be true or false")
+		}
+		q.HasDetails = &b
+	}
+	switch c.DefaultQuery("sort", "desc") {
+	case "desc":
+	case "asc":
+		q.Ascending = true
+	default:
+		return q, errors.New("sort must be asc or desc")
+	}
+	return q, nil
+}
+
+// parseBound accepts a calendar date or an RFC3339 timestamp and reports which one it was.
+func parseBound(v string) (time.Time, bool, error) {
+	if t, err := time.Parse(dateLayout, v); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	return t, false, err
 }
 
 // get session
 // @Summary      Get workout session by ID
 // @Tags         workout-sessions
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Produce      json
 // @Param        id   path      int  true  "WorkoutSession ID"
 // @Success      200  {object}  models.WorkoutSession
@@ -186,6 +354,7 @@ func (h *WorkoutSessionHandler) getByID(c *gin.Context) {
 // @Summary      Delete workout session
 // @Tags         workout-sessions
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Param        id   path      int  true  "WorkoutSession ID"
 // @Success      204  {string}  string  "No Content"
 // @Failure      400  {object}  gin.H
@@ -212,3 +381,178 @@ func (h *WorkoutSessionHandler) delete(c *gin.Context) {
 	}
 	c.Status(http.StatusNoContent)
 }
+
+// update session
+// @Summary      Update workout session start/end time
+// @Tags         workout-sessions
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                          true  "WorkoutSession ID"
+// @Param        payload  body      workoutSessionUpdateRequest  true  "Times"
+// @Success      200      {object}  models.WorkoutSession
+// @Failure      400      {object}  gin.H
+// @Failure      404      {object}  gin.H
+// @Failure      500      {object}  gin.H
+// @Router       /workout-sessions/{id} [put]
+func (h *WorkoutSessionHandler) update(c *gin.Context) {
+	session, ok := ownedSession(c, h.repo)
+	if !ok {
+		return
+	}
+	var req workoutSessionUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Datetime != nil {
+		session.Datetime = *req.Datetime
+	}
+	if req.EndedAt != nil {
+		session.EndedAt = req.EndedAt
+	}
+	if session.EndedAt != nil && session.EndedAt.Before(session.Datetime) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ended_at must not be before datetime"})
+		return
+	}
+	if err := h.repo.Update(c.Request.Context(), session); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, session)
+}
+
+// add exercise
+// @Summary      Append an exercise to a workout session
+// @Tags         workout-sessions
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                     true  "WorkoutSession ID"
+// @Param        payload  body      workoutExerciseRequest  true  "Exercise"
+// @Success      201      {object}  models.WorkoutExercise
+// @Failure      400      {object}  gin.H
+// @Failure      404      {object}  gin.H
+// @Failure      500      {object}  gin.H
+// @Router       /workout-sessions/{id}/exercises [post]
+func (h *WorkoutSessionHandler) addExercise(c *gin.Context) {
+	session, ok := ownedSession(c, h.repo)
+	if !ok {
+		return
+	}
+	var req workoutExerciseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	exercise := req.toModel(0)
+	exercise.WorkoutSessionID = session.ID
+	if err := h.repo.AddExercise(c.Request.Context(), &exercise); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, exercise)
+}
+
+// reorder exercises
+// @Summary      Reorder the exercises of a workout session
+// @Tags         workout-sessions
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                   true  "WorkoutSession ID"
+// @Param        payload  body      exerciseOrderRequest  true  "Exercise IDs in the new order"
+// @Success      200      {object}  models.WorkoutSession
+// @Failure      400      {object}  gin.H
+// @Failure      404      {object}  gin.H
+// @Failure      500      {object}  gin.H
+// @Router       /workout-sessions/{id}/exercises/order [put]
+func (h *WorkoutSessionHandler) reorderExercises(c *gin.Context) {
+	session, ok := ownedSession(c, h.repo)
+	if !ok {
+		return
+	}
+	var req exerciseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	err := h.repo.ReorderExercises(c.Request.Context(), session.ID, req.ExerciseIDs)
+	if errors.Is(err, repository.ErrInvalidExerciseOrder) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.respondWithSession(c, session.ID)
+}
+
+// remove exercise
+// @Summary      Remove an exercise and its sets from a workout session
+// @Tags         workout-sessions
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id           path      int  true  "WorkoutSession ID"
+// @Param        exercise_id  path      int  true  "WorkoutExercise ID"
+// @Success      204          {string}  string  "No Content"
+// @Failure      400          {object}  gin.H
+// @Failure      404          {object}  gin.H
+// @Router       /workout-sessions/{id}/exercises/{exercise_id} [delete]
+func (h *WorkoutSessionHandler) removeExercise(c *gin.Context) {
+	session, ok := ownedSession(c, h.repo)
+	if !ok {
+		return
+	}
+	exerciseID, err := strconv.Atoi(c.Param("exercise_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid exercise id"})
+		return
+	}
+	err = h.repo.RemoveExercise(c.Request.Context(), session.ID, uint(exerciseID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	detectRecords(c, h.records, h.repo, session.ID)
+	c.Status(http.StatusNoContent)
+}
+
+// respondWithSession reloads the session so the response reflects the stored order.
+func (h *WorkoutSessionHandler) respondWithSession(c *gin.Context, id uint) {
+	session, err := h.repo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, session)
+}
+
+// ownedSession loads the session from the :id path parameter and verifies it belongs to the caller.
+// It writes the error response itself and reports whether the handler may continue.
+func ownedSession(c *gin.Context, repo repository.WorkoutSessionRepository) (*models.WorkoutSession, bool) {
+	sid, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return nil, false
+	}
+	session, err := repo.GetByID(c.Request.Context(), uint(sid))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	uid, _ := middleware.UserID(c)
+	if session.UserID != uid {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return nil, false
+	}
+	return session, true
+}
@@ -0,0 +1,273 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/middleware"
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/use_case"
+)
+
+// WorkoutSetHandler handles typed set logging within a workout session.
+type WorkoutSetHandler struct {
+	sessionRepo repository.WorkoutSessionRepository
+	repo        repository.WorkoutSetRepository
+	records     *use_case.RecordService
+}
+
+func NewWorkoutSetHandler(sessionRepo repository.WorkoutSessionRepository, repo repository.WorkoutSetRepository, records *use_case.RecordService) *WorkoutSetHandler {
+	return &WorkoutSetHandler{sessionRepo: sessionRepo, repo: repo, records: records}
+}
+
+func (h *WorkoutSetHandler) RegisterRoutes(r *gin.Engine, auth gin.HandlerFunc) {
+	sets := r.Group("/workout-sessions/:id/sets")
+	sets.Use(auth, middleware.RequireAccess(middleware.ScopeSessionsRead, middleware.ScopeSessionsWrite))
+	{
+		sets.POST("", h.create)
+		sets.GET("", h.list)
+		sets.PUT("/:set_id", h.update)
+		sets.DELETE("/:set_id", h.delete)
+	}
+}
+
+type workoutSetRequest struct {
+	// ExerciseID attaches the set to an exercise of the session; it defaults to the
+	// only exercise when the session has exactly one. Ignored for nested sets.
+	ExerciseID  *uint    `json:"exercise_id"`
+	SetIndex    int      `json:"set_index" binding:"min=0"`
+	Reps        int      `json:"reps" binding:"min=0"`
+	Weight      float64  `json:"weight" binding:"min=0"`
+	Unit        string   `json:"unit" binding:"omitempty,oneof=kg lb"`
+	RPE         *float64 `json:"rpe" binding:"omitempty,min=0,max=10"`
+	RestSeconds int      `json:"rest_seconds" binding:"min=0"`
+	Completed   *bool    `json:"completed"`
+}
+
+// setResponse is a saved set together with the personal records it set.
+type setResponse struct {
+	*models.WorkoutSet
+	NewRecords []*models.PersonalRecord `json:"new_records,omitempty"`
+}
+
+// apply copies request fields onto set, defaulting unit to kg and completed to true.
+func (req workoutSetRequest) apply(set *models.WorkoutSet) {
+	set.Reps = req.Reps
+	set.Weight = req.Weight
+	set.Unit = req.Unit
+	if set.Unit == "" {
+		set.Unit = models.UnitKilograms
+	}
+	set.RPE = req.RPE
+	set.RestSeconds = req.RestSeconds
+	set.Completed = req.Completed == nil || *req.Completed
+	if req.SetIndex > 0 {
+		set.SetIndex = req.SetIndex
+	}
+}
+
+// create set
+// @Summary      Log a set in a workout session
+// @Tags         workout-sets
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                true  "WorkoutSession ID"
+// @Param        payload  body      workoutSetRequest  true  "Set"
+// @Success      201      {object}  setResponse
+// @Failure      400      {object}  gin.H
+// @Failure      404      {object}  gin.H
+// @Failure      500      {object}  gin.H
+// @Router       /workout-sessions/{id}/sets [post]
+func (h *WorkoutSetHandler) create(c *gin.Context) {
+	session, ok := ownedSession(c, h.sessionRepo)
+	if !ok {
+		return
+	}
+	var req workoutSetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	exerciseID, ok := resolveExercise(c, session, req.ExerciseID)
+	if !ok {
+		return
+	}
+
+	// append after the last logged set unless the client numbers sets itself
+	set := &models.WorkoutSet{WorkoutSessionID: session.ID, WorkoutExerciseID: exerciseID, SetIndex: nextSetIndex(session, exerciseID)}
+	req.apply(set)
+	if err := h.repo.Create(c.Request.Context(), set); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, setResponse{set, detectRecords(c, h.records, h.sessionRepo, session.ID)})
+}
+
+// list sets
+// @Summary      List sets of a workout session
+// @Tags         workout-sets
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        id      path      int     true   "WorkoutSession ID"
+// @Param        limit   query     int     false  "Page size (default 100, max 500)"
+// @Param        cursor  query     string  false  "next_cursor of the previous page"
+// @Success      200     {object}  pagination.Page[models.WorkoutSet]
+// @Failure      400     {object}  gin.H
+// @Failure      404     {object}  gin.H
+// @Router       /workout-sessions/{id}/sets [get]
+func (h *WorkoutSetHandler) list(c *gin.Context) {
+	session, ok := ownedSession(c, h.sessionRepo)
+	if !ok {
+		return
+	}
+	p, err := pagination.Parse(c.Query("limit"), c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sets, err := h.repo.PageBySession(c.Request.Context(), session.ID, p.Limit+1, p.After)
+	if errors.Is(err, pagination.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pagination.NewPage(sets, p, func(s *models.WorkoutSet) pagination.Cursor {
+		return pagination.Cursor{Key: strconv.Itoa(s.SetIndex), ID: s.ID}
+	}))
+}
+
+// update set
+// @Summary      Update a set of a workout session
+// @Tags         workout-sets
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                true  "WorkoutSession ID"
+// @Param        set_id   path      int                true  "WorkoutSet ID"
+// @Param        payload  body      workoutSetRequest  true  "Set"
+// @Success      200      {object}  setResponse
+// @Failure      400      {object}  gin.H
+// @Failure      404      {object}  gin.H
+// @Failure      500      {object}  gin.H
+// @Router       /workout-sessions/{id}/sets/{set_id} [put]
+func (h *WorkoutSetHandler) update(c *gin.Context) {
+	session, ok := ownedSession(c, h.sessionRepo)
+	if !ok {
+		return
+	}
+	set, ok := h.sessionSet(c, session)
+	if !ok {
+		return
+	}
+	var req workoutSetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.ExerciseID != nil {
+		exerciseID, ok := resolveExercise(c, session, req.ExerciseID)
+		if !ok {
+			return
+		}
+		set.WorkoutExerciseID = exerciseID
+	}
+	req.apply(set)
+	if err := h.repo.Update(c.Request.Context(), set); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, setResponse{set, detectRecords(c, h.records, h.sessionRepo, session.ID)})
+}
+
+// delete set
+// @Summary      Delete a set of a workout session
+// @Tags         workout-sets
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id      path      int  true  "WorkoutSession ID"
+// @Param        set_id  path      int  true  "WorkoutSet ID"
+// @Success      204     {string}  string  "No Content"
+// @Failure      400     {object}  gin.H
+// @Failure      404     {object}  gin.H
+// @Router       /workout-sessions/{id}/sets/{set_id} [delete]
+func (h *WorkoutSetHandler) delete(c *gin.Context) {
+	session, ok := ownedSession(c, h.sessionRepo)
+	if !ok {
+		return
+	}
+	set, ok := h.sessionSet(c, session)
+	if !ok {
+		return
+	}
+	if err := h.repo.Delete(c.Request.Context(), set.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	// records set by the deleted set are lowered or removed
+	detectRecords(c, h.records, h.sessionRepo, session.ID)
+	c.Status(http.StatusNoContent)
+}
+
+// sessionSet loads the set from the :set_id path parameter and verifies it belongs to session.
+func (h *WorkoutSetHandler) sessionSet(c *gin.Context, session *models.WorkoutSession) (*models.WorkoutSet, bool) {
+	id, err := strconv.Atoi(c.Param("set_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid set id"})
+		return nil, false
+	}
+	set, err := h.repo.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if set.WorkoutSessionID != session.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return nil, false
+	}
+	return set, true
+}
+
+// resolveExercise validates the requested exercise against the session. Without a request
+// it falls back to the session's only exercise, or to no exercise at all.
+func resolveExercise(c *gin.Context, session *models.WorkoutSession, requested *uint) (*uint, bool) {
+	if requested == nil {
+		if len(session.Exercises) == 1 {
+			id := session.Exercises[0].ID
+			return &id, true
+		}
+		return nil, true
+	}
+	for _, ex := range session.Exercises {
+		if ex.ID == *requested {
+			return requested, true
+		}
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "exercise does not belong to session"})
+	return nil, false
+}
+
+// nextSetIndex numbers sets per exercise, or per session for sets without an exercise. It
+// follows the highest index so numbers stay unique after a set is deleted.
+func nextSetIndex(session *models.WorkoutSession, exerciseID *uint) int {
+	n := 0
+	for _, s := range session.Sets {
+		if (exerciseID == nil && s.WorkoutExerciseID == nil) ||
+			(exerciseID != nil && s.WorkoutExerciseID != nil && *s.WorkoutExerciseID == *exerciseID) {
+			n = max(n, s.SetIndex)
+		}
+	}
+	return n + 1
+}
@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/middleware"
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
+)
+
+// WorkoutTemplateHandler handles reusable workout routines and starting sessions from them.
+type WorkoutTemplateHandler struct {
+	repo        repository.WorkoutTemplateRepository
+	sessionRepo repository.WorkoutSessionRepository
+}
+
+func NewWorkoutTemplateHandler(repo repository.WorkoutTemplateRepository, sessionRepo repository.WorkoutSessionRepository) *WorkoutTemplateHandler {
+	return &WorkoutTemplateHandler{repo: repo, sessionRepo: sessionRepo}
+}
+
+func (h *WorkoutTemplateHandler) RegisterRoutes(r *gin.Engine, auth gin.HandlerFunc) {
+	wt := r.Group("/workout-templates")
+	wt.Use(auth, middleware.RequireAccess(middleware.ScopeTemplatesRead, middleware.ScopeTemplatesWrite))
+	{
+		wt.POST("", h.create)
+		wt.GET("", h.list)
+		wt.GET("/:id", h.getByID)
+		wt.PUT("/:id", h.update)
+		wt.DELETE("/:id", h.delete)
+		wt.POST("/:id/start", middleware.RequireScope(middleware.ScopeSessionsWrite), h.start)
+	}
+}
+
+type workoutTemplateRequest struct {
+	Name      string                           `json:"name" binding:"required"`
+	Notes     string                           `json:"notes"`
+	Exercises []workoutTemplateExerciseRequest `json:"exercises" binding:"required,min=1,dive"`
+}
+
+type workoutTemplateExerciseRequest struct {
+	WorkoutTypeID uint    `json:"workout_type_id" binding:"required"`
+	TargetSets    int     `json:"target_sets" binding:"required,min=1,max=50"`
+	TargetReps    int     `json:"target_reps" binding:"min=0"`
+	TargetWeight  float64 `json:"target_weight" binding:"min=0"`
+	Unit          string  `json:"unit" binding:"omitempty,oneof=kg lb"`
+	RestSeconds   int     `json:"rest_seconds" binding:"min=0"`
+	Notes         string  `json:"notes"`
+}
+
+type startTemplateRequest struct {
+	Datetime time.Time `json:"datetime"`
+}
+
+// exercises converts the ordered request entries into template exercises.
+func (req workoutTemplateRequest) exercises() []models.WorkoutTemplateExercise {
+	out := make([]models.WorkoutTemplateExercise, len(req.Exercises))
+	for i, e := range req.Exercises {
+		unit := e.Unit
+		if unit == "" {
+			unit = models.UnitKilograms
+		}
+		out[i] = models.WorkoutTemplateExercise{
+			WorkoutTypeID: e.WorkoutTypeID,
+			Position:      i + 1,
+			TargetSets:    e.TargetSets,
+			TargetReps:    e.TargetReps,
+			TargetWeight:  e.TargetWeight,
+			Unit:          unit,
+			RestSeconds:   e.RestSeconds,
+			Notes:         e.Notes,
+		}
+	}
+	return out
+}
+
+// create template
+// @Summary      Create workout template
+// @Tags         workout-templates
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      workoutTemplateRequest  true  "Template"
+// @Success      201      {object}  models.WorkoutTemplate
+// @Failure      400      {object}  gin.H
+// @Failure      500      {object}  gin.H
+// @Router       /workout-templates [post]
+func (h *WorkoutTemplateHandler) create(c *gin.Context) {
+	var req workoutTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uid, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+	template := &models.WorkoutTemplate{UserID: uid, Name: req.Name, Notes: req.Notes, Exercises: req.exercises()}
+	if err := h.repo.Create(c.Request.Context(), template); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	// Retrieve with associations to include workout type names
+	if loaded, err := h.repo.GetByID(c.Request.Context(), template.ID); err == nil {
+		template = loaded
+	}
+	c.JSON(http.StatusCreated, template)
+}
+
+// list templates
+// @Summary      List workout templates of the user
+// @Description  Ordered by name
+// @Tags         workout-templates
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        limit   query     int     false  "Page size (default 100, max 500)"
+// @Param        cursor  query     string  false  "next_cursor of the previous page"
+// @Success      200     {object}  pagination.Page[models.WorkoutTemplate]
+// @Failure      400     {object}  gin.H
+// @Router       /workout-templates [get]
+func (h *WorkoutTemplateHandler) list(c *gin.Context) {
+	uid, ok := middleware.UserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user"})
+		return
+	}
+	p, err := pagination.Parse(c.Query("limit"), c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	templates, err := h.repo.ListByUser(c.Request.Context(), uid, p.Limit+1, p.After)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pagination.NewPage(templates, p, func(t *models.WorkoutTemplate) pagination.Cursor {
+		return pagination.Cursor{Key: t.Name, ID: t.ID}
+	}))
+}
+
+// get template
+// @Summary      Get workout template by ID
+// @Tags         workout-templates
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Param        id   path      int  true  "WorkoutTemplate ID"
+// @Success      200  {object}  models.WorkoutTemplate
+// @Failure      400  {object}  gin.H
+// @Failure      404  {object}  gin.H
+// @Router       /workout-templates/{id} [get]
+func (h *WorkoutTemplateHandler) getByID(c *gin.Context) {
+	template, ok := h.ownedTemplate(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+// update template
+// @Summary      Update workout template
+// @Description  Replaces name, notes and the full ordered exercise list
+// @Tags         workout-templates
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                     true  "WorkoutTemplate ID"
+// @Param        payload  body      workoutTemplateRequest  true  "Template"
+// @Success      200      {object}  models.WorkoutTemplate
+// @Failure      400      {object}  gin.H
+// @Failure      404      {object}  gin.H
+// @Failure      500      {object}  gin.H
+// @Router       /workout-templates/{id} [put]
+func (h *WorkoutTemplateHandler) update(c *gin.Context) {
+	template, ok := h.ownedTemplate(c)
+	if !ok {
+		return
+	}
+	var req workoutTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	template.Name = req.Name
+	template.Notes = req.Notes
+	template.Exercises = req.exercises()
+	if err := h.repo.Update(c.Request.Context(), template); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if loaded, err := h.repo.GetByID(c.Request.Context(), template.ID); err == nil {
+		template = loaded
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+// delete template
+// @Summary      Delete workout template
+// @Tags         workout-templates
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id   path      int  true  "WorkoutTemplate ID"
+// @Success      204  {string}  string  "No Content"
+// @Failure      400  {object}  gin.H
+// @Failure      404  {object}  gin.H
+// @Router       /workout-templates/{id} [delete]
+func (h *WorkoutTemplateHandler) delete(c *gin.Context) {
+	template, ok := h.ownedTemplate(c)
+	if !ok {
+		return
+	}
+	if err := h.repo.Delete(c.Request.Context(), template.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// start session from template
+// @Summary      Start a workout session from a template
+// @Description  Creates a new session for the authenticated user pre-filled with the template's exercises and planned sets.
+// @Description  API keys need the sessions:write scope besides templates:write.
+// @Tags         workout-templates
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                   true   "WorkoutTemplate ID"
+// @Param        payload  body      startTemplateRequest  false  "Start time (defaults to now)"
+// @Success      201      {object}  models.WorkoutSession
+// @Failure      400      {object}  gin.H
+// @Failure      403      {object}  gin.H
+// @Failure      404      {object}  gin.H
+// @Failure      500      {object}  gin.H
+// @Router       /workout-templates/{id}/start [post]
+func (h *WorkoutTemplateHandler) start(c *gin.Context) {
+	template, ok := h.ownedTemplate(c)
+	if !ok {
+		return
+	}
+	var req startTemplateRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.Datetime.IsZero() {
+		req.Datetime = time.Now()
+	}
+	if len(template.Exercises) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "template has no exercises"})
+		return
+	}
+
+	session := template.NewSession(template.UserID, req.Datetime)
+	if err := h.sessionRepo.Create(c.Request.Context(), session); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if loaded, err := h.sessionRepo.GetByID(c.Request.Context(), session.ID); err == nil {
+		session = loaded
+	}
+	c.JSON(http.StatusCreated, session)
+}
+
+// ownedTemplate loads the template from the :id path parameter and verifies it belongs to the caller.
+func (h *WorkoutTemplateHandler) ownedTemplate(c *gin.Context) (*models.WorkoutTemplate, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return nil, false
+	}
+	template, err := h.repo.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	uid, _ := middleware.UserID(c)
+	if template.UserID != uid {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return nil, false
+	}
+	return template, true
+}
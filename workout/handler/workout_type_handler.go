@@ -6,6 +6,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/VibeTeam/fitness-tracker-backend/shared/middleware"
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
 	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
 	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
 )
@@ -20,7 +22,7 @@ func NewWorkoutTypeHandler(repo repository.WorkoutTypeRepository) *WorkoutTypeHa
 
 func (h *WorkoutTypeHandler) RegisterRoutes(r *gin.Engine, auth gin.HandlerFunc) {
 	wt := r.Group("/workout-types")
-	wt.Use(auth)
+	wt.Use(auth, middleware.RequireAccess(middleware.ScopeCatalogRead, middleware.ScopeCatalogWrite))
 	{
 		wt.POST("", h.create)
 		wt.GET("", h.list)
@@ -39,6 +41,7 @@ type workoutTypeRequest struct {
 // @Summary      Create workout type
 // @Tags         workout-types
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        payload  body      workoutTypeRequest  true  "Workout type"
@@ -68,22 +71,34 @@ func (h *WorkoutTypeHandler) create(c *gin.Context) {
 // @Summary      List workout types
 // @Tags         workout-types
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Produce      json
-// @Success      200  {array}   models.WorkoutType
+// @Param        limit   query     int     false  "Page size (default 100, max 500)"
+// @Param        cursor  query     string  false  "next_cursor of the previous page"
+// @Success      200     {object}  pagination.Page[models.WorkoutType]
+// @Failure      400     {object}  gin.H
 // @Router       /workout-types [get]
 func (h *WorkoutTypeHandler) list(c *gin.Context) {
-	types, err := h.repo.List(c.Request.Context(), 100, 0)
+	p, err := pagination.Parse(c.Query("limit"), c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	types, err := h.repo.List(c.Request.Context(), p.Limit+1, p.After)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, types)
+	c.JSON(http.StatusOK, pagination.NewPage(types, p, func(wt *models.WorkoutType) pagination.Cursor {
+		return pagination.Cursor{ID: wt.ID}
+	}))
 }
 
 // get workout type
 // @Summary      Get workout type by ID
 // @Tags         workout-types
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Produce      json
 // @Param        id   path      int  true  "WorkoutType ID"
 // @Success      200  {object}  models.WorkoutType
@@ -108,6 +123,7 @@ func (h *WorkoutTypeHandler) getByID(c *gin.Context) {
 // @Summary      Update workout type
 // @Tags         workout-types
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Accept       json
 // @Produce      json
 // @Param        id       path      int                 true  "WorkoutType ID"
@@ -145,6 +161,7 @@ func (h *WorkoutTypeHandler) update(c *gin.Context) {
 // @Summary      Delete workout type
 // @Tags         workout-types
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Param        id   path      int  true  "WorkoutType ID"
 // @Success      204  {string}  string  "No Content"
 // @Failure      400  {object}  gin.H
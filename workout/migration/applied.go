@@ -0,0 +1,44 @@
+package migration
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// appliedMigration records that a one-off migration has run, so later starts skip it and
+// never touch data users changed since.
+type appliedMigration struct {
+	Name      string    `gorm:"primaryKey;type:text"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (appliedMigration) TableName() string { return "workout_data_migrations" }
+
+// once runs migrate in a transaction unless the migration called name has run before, and
+// records it in the same transaction. It returns what migrate returns, or 0 when skipped.
+func once(ctx context.Context, db *gorm.DB, name string, migrate func(tx *gorm.DB) (int, error)) (int, error) {
+	if err := db.WithContext(ctx).AutoMigrate(&appliedMigration{}); err != nil {
+		return 0, err
+	}
+	n := 0
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var applied int64
+		if err := tx.Model(&appliedMigration{}).Where("name = ?", name).Count(&applied).Error; err != nil {
+			return err
+		}
+		if applied > 0 {
+			return nil
+		}
+		var err error
+		if n, err = migrate(tx); err != nil {
+			return err
+		}
+		return tx.Create(&appliedMigration{Name: name, AppliedAt: time.Now()}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,44 @@
+// Package migration holds one-off data migrations for the workout domain that
+// go beyond what GORM's AutoMigrate can express.
+package migration
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
+)
+
+// DetailsToSets converts numeric key-value details of sessions that have no typed
+// sets yet into WorkoutSet rows. Details are left untouched. It runs once per database;
+// sets a user deletes later stay deleted. It returns the number of sets created.
+func DetailsToSets(ctx context.Context, db *gorm.DB) (int, error) {
+	return once(ctx, db, "details_to_sets", func(tx *gorm.DB) (int, error) {
+		var sessions []*models.WorkoutSession
+		err := tx.
+			Where("id IN (?)", tx.Model(&models.WorkoutDetail{}).Select("workout_session_id")).
+			Where("id NOT IN (?)", tx.Model(&models.WorkoutSet{}).Select("workout_session_id")).
+			Preload("Details").
+			Find(&sessions).Error
+		if err != nil {
+			return 0, err
+		}
+
+		created := 0
+		for _, s := range sessions {
+			sets := models.SetsFromDetails(s.Details)
+			if len(sets) == 0 {
+				continue
+			}
+			for i := range sets {
+				sets[i].WorkoutSessionID = s.ID
+			}
+			if err := tx.Create(&sets).Error; err != nil {
+				return 0, err
+			}
+			created += len(sets)
+		}
+		return created, nil
+	})
+}
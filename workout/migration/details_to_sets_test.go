@@ -0,0 +1,108 @@
+package migration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
+)
+
+func TestDetailsToSets(t *testing.T) {
+	ctx := context.Background()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("opening DB: %v", err)
+	}
+	if err := db.AutoMigrate(&models.WorkoutSession{}, &models.WorkoutExercise{}, &models.WorkoutDetail{}, &models.WorkoutSet{}); err != nil {
+		t.Fatalf("migrating schema: %v", err)
+	}
+
+	session := &models.WorkoutSession{
+		WorkoutTypeID: 1,
+		UserID:        1,
+		Datetime:      time.Now(),
+		Details: []models.WorkoutDetail{
+			{DetailName: "Sets", DetailValue: "3"},
+			{DetailName: "reps", DetailValue: "8"},
+			{DetailName: "Weight", DetailValue: "80kg"},
+		},
+	}
+	notes := &models.WorkoutSession{
+		WorkoutTypeID: 1,
+		UserID:        1,
+		Datetime:      time.Now(),
+		Details:       []models.WorkoutDetail{{DetailName: "mood", DetailValue: "great"}},
+	}
+	if err := db.Create(session).Error; err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	if err := db.Create(notes).Error; err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	n, err := DetailsToSets(ctx, db)
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("migrate: want 3 sets, got %d", n)
+	}
+
+	var sets []models.WorkoutSet
+	db.Where("workout_session_id = ?", session.ID).Order("set_index").Find(&sets)
+	if len(sets) != 3 || sets[2].SetIndex != 3 || sets[0].Reps != 8 || sets[0].Weight != 80 || sets[0].Unit != models.UnitKilograms {
+		t.Fatalf("unexpected sets %+v", sets)
+	}
+
+	// running again must not duplicate sets
+	if n, err := DetailsToSets(ctx, db); err != nil || n != 0 {
+		t.Fatalf("rerun: want 0 sets, got %d (err=%v)", n, err)
+	}
+
+	// legacy sessions become single-exercise sessions owning their sets
+	if n, err := SessionsToExercises(ctx, db); err != nil || n != 2 {
+		t.Fatalf("exercises: want 2, got %d (err=%v)", n, err)
+	}
+	var exercise models.WorkoutExercise
+	db.Preload("Sets").Where("workout_session_id = ?", session.ID).First(&exercise)
+	if exercise.WorkoutTypeID != 1 || exercise.Position != 1 || len(exercise.Sets) != 3 {
+		t.Fatalf("unexpected exercise %+v", exercise)
+	}
+	if n, err := SessionsToExercises(ctx, db); err != nil || n != 0 {
+		t.Fatalf("exercises rerun: want 0, got %d (err=%v)", n, err)
+	}
+
+	// sets a user deleted stay deleted on the next start
+	db.Where("workout_session_id = ?", session.ID).Delete(&models.WorkoutSet{})
+	if n, err := DetailsToSets(ctx, db); err != nil || n != 0 {
+		t.Fatalf("rerun after deleting sets: want 0 sets, got %d (err=%v)", n, err)
+	}
+	// and so do removed exercises
+	db.Where("workout_session_id = ?", session.ID).Delete(&models.WorkoutExercise{})
+	if n, err := SessionsToExercises(ctx, db); err != nil || n != 0 {
+		t.Fatalf("rerun after removing exercises: want 0, got %d (err=%v)", n, err)
+	}
+}
+
+func TestSetsFromDetailsNotation(t *testing.T) {
+	sets := models.SetsFromDetails([]models.WorkoutDetail{
+		{DetailName: "sets", DetailValue: "5x5"},
+		{DetailName: "weight", DetailValue: "225 lbs"},
+	})
+	if len(sets) != 5 || sets[0].Reps != 5 || sets[0].Unit != models.UnitPounds || sets[0].Weight != 225 {
+		t.Fatalf("unexpected sets %+v", sets)
+	}
+
+	// free text cannot make the conversion allocate without bound
+	sets = models.SetsFromDetails([]models.WorkoutDetail{
+		{DetailName: "sets", DetailValue: "2000000000"},
+		{DetailName: "reps", DetailValue: "5"},
+	})
+	if len(sets) != 50 {
+		t.Fatalf("want sets capped at 50, got %d", len(sets))
+	}
+}
@@ -0,0 +1,39 @@
+package migration
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
+)
+
+// SessionsToExercises gives every single-type session without exercises one exercise for
+// its WorkoutTypeID and attaches the session's loose sets to it. Like DetailsToSets it runs
+// once per database, so sessions whose exercises a user removed later stay empty. It returns
+// the number of exercises created.
+func SessionsToExercises(ctx context.Context, db *gorm.DB) (int, error) {
+	return once(ctx, db, "sessions_to_exercises", func(tx *gorm.DB) (int, error) {
+		var sessions []*models.WorkoutSession
+		err := tx.
+			Where("id NOT IN (?)", tx.Model(&models.WorkoutExercise{}).Select("workout_session_id")).
+			Find(&sessions).Error
+		if err != nil {
+			return 0, err
+		}
+
+		for _, s := range sessions {
+			exercise := &models.WorkoutExercise{WorkoutSessionID: s.ID, WorkoutTypeID: s.WorkoutTypeID, Position: 1}
+			if err := tx.Create(exercise).Error; err != nil {
+				return 0, err
+			}
+			err := tx.Model(&models.WorkoutSet{}).
+				Where("workout_session_id = ? AND workout_exercise_id IS NULL", s.ID).
+				Update("workout_exercise_id", exercise.ID).Error
+			if err != nil {
+				return 0, err
+			}
+		}
+		return len(sessions), nil
+	})
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Roles of coach messages; they match the chat roles of the LLM providers.
+const (
+	CoachRoleUser      = "user"
+	CoachRoleAssistant = "assistant"
+)
+
+// CoachThread is a conversation of a user with the AI coach.
+type CoachThread struct {
+	ID     uint   `gorm:"primaryKey;autoIncrement"`
+	UserID uint   `gorm:"not null;index"`
+	Title  string `gorm:"type:text;not null"`
+	// UpdatedAt moves with every message, so threads can be listed by recent activity.
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime;index"`
+
+	// Associations
+	Messages []CoachMessage `gorm:"foreignKey:ThreadID"`
+}
+
+// CoachMessage is a message of the user or a reply of the coach within a thread.
+type CoachMessage struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	ThreadID  uint      `gorm:"not null;index"`
+	Role      string    `gorm:"type:text;not null"`
+	Content   string    `gorm:"type:text;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+)
+
+// maxDetailSets caps the sets read from a legacy "sets" detail, which is free text.
+const maxDetailSets = 50
+
+// SetsFromDetails interprets legacy key-value details ("reps=10", "weight=80kg", "sets=3" or "sets=5x5")
+// as typed sets. Details that carry no numeric reps or weight yield no sets, and more than
+// maxDetailSets sets are read as maxDetailSets.
+func SetsFromDetails(details []WorkoutDetail) []WorkoutSet {
+	var (
+		sets, reps int
+		weight     float64
+		unit       = UnitKilograms
+		rpe        *float64
+		rest       int
+		hasReps    bool
+		hasWeight  bool
+	)
+
+	for _, d := range details {
+		value := strings.ToLower(strings.TrimSpace(d.DetailValue))
+		switch strings.ToLower(strings.TrimSpace(d.DetailName)) {
+		case "sets", "set":
+			if n, m, ok := parseSetsByReps(value); ok {
+				sets, reps, hasReps = n, m, true
+			} else if n, ok := parseInt(value); ok {
+				sets = n
+			}
+		case "reps", "rep", "repetitions":
+			if n, ok := parseInt(value); ok {
+				reps, hasReps = n, true
+			}
+		case "weight", "load":
+			if w, u, ok := parseWeight(value); ok {
+				weight, unit, hasWeight = w, u, true
+			}
+		case "rpe":
+			if f, ok := parseFloat(value); ok {
+				rpe = &f
+			}
+		case "rest", "rest_seconds", "rest seconds":
+			if n, ok := parseInt(strings.TrimSuffix(value, "s")); ok {
+				rest = n
+			}
+		}
+	}
+
+	if !hasReps && !hasWeight {
+		return nil
+	}
+	sets = min(max(sets, 1), maxDetailSets)
+
+	out := make([]WorkoutSet, sets)
+	for i := range out {
+		out[i] = WorkoutSet{
+			SetIndex:    i + 1,
+			Reps:        reps,
+			Weight:      weight,
+			Unit:        unit,
+			RPE:         rpe,
+			RestSeconds: rest,
+			Completed:   true,
+		}
+	}
+	return out
+}
+
+// parseSetsByReps understands the "5x5" / "3 x 10" notation.
+func parseSetsByReps(v string) (int, int, bool) {
+	parts := strings.Split(strings.ReplaceAll(v, "×", "x"), "x")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	sets, ok1 := parseInt(parts[0])
+	reps, ok2 := parseInt(parts[1])
+	return sets, reps, ok1 && ok2
+}
+
+// parseWeight reads a number with an optional kg/lb(s) suffix.
+func parseWeight(v string) (float64, string, bool) {
+	unit := UnitKilograms
+	switch {
+	case strings.HasSuffix(v, "lbs"):
+		v, unit = strings.TrimSuffix(v, "lbs"), UnitPounds
+	case strings.HasSuffix(v, "lb"):
+		v, unit = strings.TrimSuffix(v, "lb"), UnitPounds
+	case strings.HasSuffix(v, "kg"):
+		v = strings.TrimSuffix(v, "kg")
+	}
+	w, ok := parseFloat(v)
+	return w, unit, ok
+}
+
+func parseInt(v string) (int, bool) {
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	return n, err == nil && n >= 0
+}
+
+func parseFloat(v string) (float64, bool) {
+	f, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(v), ",", "."), 64)
+	return f, err == nil && f >= 0
+}
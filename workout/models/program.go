@@ -0,0 +1,148 @@
+package models
+
+import "time"
+
+// Program is a multi-week training plan authored by a user that others can enroll in.
+type Program struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement"`
+	UserID      uint      `gorm:"not null;index"`
+	Name        string    `gorm:"type:text;not null"`
+	Description string    `gorm:"type:text"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+
+	// Associations
+	Weeks []ProgramWeek `gorm:"foreignKey:ProgramID"`
+}
+
+// ProgramWeek groups the training days of one program week (1-based).
+type ProgramWeek struct {
+	ID         uint `gorm:"primaryKey;autoIncrement"`
+	ProgramID  uint `gorm:"not null;index"`
+	WeekNumber int  `gorm:"not null"`
+
+	// Associations
+	Days []ProgramDay `gorm:"foreignKey:ProgramWeekID"`
+}
+
+// ProgramDay is a scheduled training day; DayNumber 1-7 counts from the enrollment start weekday.
+// Days missing from a week are rest days.
+type ProgramDay struct {
+	ID            uint   `gorm:"primaryKey;autoIncrement"`
+	ProgramWeekID uint   `gorm:"not null;index"`
+	DayNumber     int    `gorm:"not null"`
+	Name          string `gorm:"type:text"`
+
+	// Associations
+	Prescriptions []ProgramPrescription `gorm:"foreignKey:ProgramDayID"`
+}
+
+// ProgramPrescription says what to do for one WorkoutType on a day, e.g. 5x5 at 75% 1RM.
+// Either PercentOneRM or Weight may be set; both empty means bodyweight or free choice.
+type ProgramPrescription struct {
+	ID            uint `gorm:"primaryKey;autoIncrement"`
+	ProgramDayID  uint `gorm:"not null;index"`
+	WorkoutTypeID uint `gorm:"not null;index"`
+	Position      int  `gorm:"not null"`
+	Sets          int  `gorm:"not null"`
+	Reps          int  `gorm:"not null"`
+	PercentOneRM  *float64
+	Weight        *float64
+	Unit          string `gorm:"type:text;not null;default:kg"`
+	Notes         string `gorm:"type:text"`
+
+	// Associations
+	WorkoutType *WorkoutType `gorm:"foreignKey:WorkoutTypeID"`
+}
+
+// ProgramEnrollment records that a user follows a program starting on StartDate.
+type ProgramEnrollment struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	ProgramID uint      `gorm:"not null;index"`
+	UserID    uint      `gorm:"not null;index"`
+	StartDate time.Time `gorm:"not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+
+	// Associations
+	Program *Program `gorm:"foreignKey:ProgramID"`
+}
+
+// Schedule returns the 1-based week and day of the program that falls on date.
+// Dates are compared as calendar days; ok is false before the start.
+func (e *ProgramEnrollment) Schedule(date time.Time) (week, day int, ok bool) {
+	days := calendarDays(e.StartDate, date)
+	if days < 0 {
+		return 0, 0, false
+	}
+	return days/7 + 1, days%7 + 1, true
+}
+
+// DateOf returns the calendar date on which the given week and day are scheduled.
+func (e *ProgramEnrollment) DateOf(week, day int) time.Time {
+	y, m, d := e.StartDate.Date()
+	return time.Date(y, m, d+(week-1)*7+day-1, 0, 0, 0, 0, time.UTC)
+}
+
+// Day finds the scheduled day for the given week and day number, or nil for a rest day.
+func (p *Program) Day(week, day int) *ProgramDay {
+	w := p.Week(week)
+	if w == nil {
+		return nil
+	}
+	for di := range w.Days {
+		if w.Days[di].DayNumber == day {
+			return &w.Days[di]
+		}
+	}
+	return nil
+}
+
+// Week finds the week with the given number, or nil if the program has none.
+func (p *Program) Week(number int) *ProgramWeek {
+	for wi := range p.Weeks {
+		if p.Weeks[wi].WeekNumber == number {
+			return &p.Weeks[wi]
+		}
+	}
+	return nil
+}
+
+// LastWeek returns the highest week number of the program.
+func (p *Program) LastWeek() int {
+	last := 0
+	for _, w := range p.Weeks {
+		if w.WeekNumber > last {
+			last = w.WeekNumber
+		}
+	}
+	return last
+}
+
+// NewSession instantiates the day as a session with planned sets. Prescribed weights are
+// taken as-is; percentage-based prescriptions get a weight of zero.
+func (d *ProgramDay) NewSession(userID uint, start time.Time) *WorkoutSession {
+	session := &WorkoutSession{UserID: userID, Datetime: start}
+	for i, p := range d.Prescriptions {
+		ex := WorkoutExercise{WorkoutTypeID: p.WorkoutTypeID, Position: i + 1, Notes: p.Notes}
+		weight := 0.0
+		if p.Weight != nil {
+			weight = *p.Weight
+		}
+		for n := 1; n <= p.Sets; n++ {
+			ex.Sets = append(ex.Sets, WorkoutSet{SetIndex: n, Reps: p.Reps, Weight: weight, Unit: p.Unit})
+		}
+		session.Exercises = append(session.Exercises, ex)
+	}
+	if len(session.Exercises) > 0 {
+		session.WorkoutTypeID = session.Exercises[0].WorkoutTypeID
+	}
+	return session
+}
+
+// calendarDays counts whole calendar days from a to b, ignoring time of day.
+func calendarDays(a, b time.Time) int {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	from := time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)
+	to := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
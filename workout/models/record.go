@@ -0,0 +1,50 @@
+package models
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// Kinds of personal records tracked per user and WorkoutType.
+const (
+	RecordHeaviestWeight = "heaviest_weight"
+	RecordMostReps       = "most_reps"
+	RecordEstimated1RM   = "estimated_1rm"
+	RecordVolume         = "volume"
+)
+
+// PersonalRecord is a best performance of a user for a WorkoutType. Each session that beats a
+// record stores one row for it, so the table doubles as record history.
+//
+// Value holds the measured quantity: kilograms for weight, e1RM and volume records and the
+// rep count for most_reps records, which are tracked per Weight.
+type PersonalRecord struct {
+	ID               uint      `gorm:"primaryKey;autoIncrement"`
+	UserID           uint      `gorm:"not null;index"`
+	WorkoutTypeID    uint      `gorm:"not null;index"`
+	Kind             string    `gorm:"type:text;not null;index"`
+	Value            float64   `gorm:"not null"`
+	Reps             int       `gorm:"not null"`
+	Weight           float64   `gorm:"not null"`
+	WorkoutSessionID uint      `gorm:"not null;index"`
+	WorkoutSetID     *uint     `gorm:"index"`
+	AchievedAt       time.Time `gorm:"not null"`
+	CreatedAt        time.Time `gorm:"autoCreateTime"`
+
+	// Associations
+	WorkoutType *WorkoutType `gorm:"foreignKey:WorkoutTypeID"`
+}
+
+// Key identifies the record slot a record competes in; most_reps records compete per weight.
+func (r *PersonalRecord) Key() string {
+	if r.Kind == RecordMostReps {
+		return r.Kind + "@" + formatWeight(r.Weight)
+	}
+	return r.Kind
+}
+
+// formatWeight rounds a kilogram load to 10 g so unit conversions compare equal.
+func formatWeight(kg float64) string {
+	return strconv.FormatFloat(math.Round(kg*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// WorkoutTemplate is a reusable routine (e.g. "Push Day") owned by a user.
+type WorkoutTemplate struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	UserID    uint      `gorm:"not null;index"`
+	Name      string    `gorm:"type:text;not null"`
+	Notes     string    `gorm:"type:text"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+
+	// Associations
+	Exercises []WorkoutTemplateExercise `gorm:"foreignKey:WorkoutTemplateID"`
+}
+
+// WorkoutTemplateExercise is an ordered template entry with its target sets, reps and weight.
+type WorkoutTemplateExercise struct {
+	ID                uint    `gorm:"primaryKey;autoIncrement"`
+	WorkoutTemplateID uint    `gorm:"not null;index"`
+	WorkoutTypeID     uint    `gorm:"not null;index"`
+	Position          int     `gorm:"not null"`
+	TargetSets        int     `gorm:"not null"`
+	TargetReps        int     `gorm:"not null"`
+	TargetWeight      float64 `gorm:"not null"`
+	Unit              string  `gorm:"type:text;not null;default:kg"`
+	RestSeconds       int     `gorm:"not null"`
+	Notes             string  `gorm:"type:text"`
+
+	// Associations
+	WorkoutType *WorkoutType `gorm:"foreignKey:WorkoutTypeID"`
+}
+
+// NewSession instantiates the template as a session for userID starting at start.
+// Every target set becomes a planned (not yet completed) WorkoutSet.
+func (t *WorkoutTemplate) NewSession(userID uint, start time.Time) *WorkoutSession {
+	session := &WorkoutSession{UserID: userID, Datetime: start}
+	for i, te := range t.Exercises {
+		ex := WorkoutExercise{WorkoutTypeID: te.WorkoutTypeID, Position: i + 1, Notes: te.Notes}
+		for n := 1; n <= te.TargetSets; n++ {
+			ex.Sets = append(ex.Sets, WorkoutSet{
+				SetIndex:    n,
+				Reps:        te.TargetReps,
+				Weight:      te.TargetWeight,
+				Unit:        te.Unit,
+				RestSeconds: te.RestSeconds,
+			})
+		}
+		session.Exercises = append(session.Exercises, ex)
+	}
+	if len(session.Exercises) > 0 {
+		session.WorkoutTypeID = session.Exercises[0].WorkoutTypeID
+	}
+	return session
+}
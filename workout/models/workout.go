@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // MuscleGroup represents a primary muscle group targeted by a workout.
 type MuscleGroup struct {
@@ -19,15 +23,55 @@ type WorkoutType struct {
 }
 
 // WorkoutSession is a log entry for a completed workout instance performed by a user.
+// A session holds an ordered list of exercises; WorkoutTypeID mirrors the first one
+// so single-exercise clients keep working.
 type WorkoutSession struct {
-	ID            uint      `gorm:"primaryKey;autoIncrement"`
-	WorkoutTypeID uint      `gorm:"not null;index"`
-	UserID        uint      `gorm:"not null;index"`
-	Datetime      time.Time `gorm:"not null"`
+	ID              uint      `gorm:"primaryKey;autoIncrement"`
+	WorkoutTypeID   uint      `gorm:"not null;index"`
+	UserID          uint      `gorm:"not null;index"`
+	Datetime        time.Time `gorm:"not null"`
+	EndedAt         *time.Time
+	DurationSeconds int `gorm:"not null;default:0"`
+
+	// ProgramEnrollmentID and ProgramDayID link a session to the scheduled program day it completes.
+	ProgramEnrollmentID *uint `gorm:"index"`
+	ProgramDayID        *uint `gorm:"index"`
+
+	// Associations
+	WorkoutType *WorkoutType      `gorm:"foreignKey:WorkoutTypeID"`
+	Exercises   []WorkoutExercise `gorm:"foreignKey:WorkoutSessionID"`
+	Details     []WorkoutDetail   `gorm:"foreignKey:WorkoutSessionID"`
+	Sets        []WorkoutSet      `gorm:"foreignKey:WorkoutSessionID"`
+}
+
+// BeforeSave keeps DurationSeconds in sync with the start and end time.
+func (s *WorkoutSession) BeforeSave(*gorm.DB) error {
+	s.DurationSeconds = 0
+	if s.EndedAt != nil && s.EndedAt.After(s.Datetime) {
+		s.DurationSeconds = int(s.EndedAt.Sub(s.Datetime).Seconds())
+	}
+	return nil
+}
+
+// WorkoutExercise is one exercise performed within a session, ordered by Position.
+type WorkoutExercise struct {
+	ID               uint   `gorm:"primaryKey;autoIncrement"`
+	WorkoutSessionID uint   `gorm:"not null;index"`
+	WorkoutTypeID    uint   `gorm:"not null;index"`
+	Position         int    `gorm:"not null"`
+	Notes            string `gorm:"type:text"`
 
 	// Associations
-	WorkoutType *WorkoutType    `gorm:"foreignKey:WorkoutTypeID"`
-	Details     []WorkoutDetail `gorm:"foreignKey:WorkoutSessionID"`
+	WorkoutType *WorkoutType `gorm:"foreignKey:WorkoutTypeID"`
+	Sets        []WorkoutSet `gorm:"foreignKey:WorkoutExerciseID"`
+}
+
+// BeforeCreate propagates the session to sets created together with the exercise.
+func (e *WorkoutExercise) BeforeCreate(*gorm.DB) error {
+	for i := range e.Sets {
+		e.Sets[i].WorkoutSessionID = e.WorkoutSessionID
+	}
+	return nil
 }
 
 // WorkoutDetail stores arbitrary key-value data points for a workout session (e.g., reps, weight).
@@ -37,3 +81,34 @@ type WorkoutDetail struct {
 	DetailName       string `gorm:"type:text;not null"`
 	DetailValue      string `gorm:"type:text;not null"`
 }
+
+// Weight units accepted for WorkoutSet.Unit.
+const (
+	UnitKilograms = "kg"
+	UnitPounds    = "lb"
+)
+
+// kilogramsPerPound converts pound loads to kilograms for comparisons across units.
+const kilogramsPerPound = 0.45359237
+
+// WorkoutSet is a single typed set (reps x weight) performed within a workout session.
+type WorkoutSet struct {
+	ID                uint    `gorm:"primaryKey;autoIncrement"`
+	WorkoutSessionID  uint    `gorm:"not null;index"`
+	WorkoutExerciseID *uint   `gorm:"index"`
+	SetIndex          int     `gorm:"not null"`
+	Reps              int     `gorm:"not null"`
+	Weight            float64 `gorm:"not null"`
+	Unit              string  `gorm:"type:text;not null;default:kg"`
+	RPE               *float64
+	RestSeconds       int  `gorm:"not null"`
+	Completed         bool `gorm:"not null"`
+}
+
+// WeightKg returns the set load normalised to kilograms.
+func (s WorkoutSet) WeightKg() float64 {
+	if s.Unit == UnitPounds {
+		return s.Weight * kilogramsPerPound
+	}
+	return s.Weight
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// Periods and groupings accepted by VolumeQuery.
+const (
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+
+	GroupByMuscleGroup = "muscle_group"
+	GroupByWorkoutType = "workout_type"
+)
+
+// VolumeQuery selects the completed sets of a user with From <= datetime < To, bucketed by
+// Period (week or month) and grouped by muscle group or workout type.
+type VolumeQuery struct {
+	UserID  uint
+	From    time.Time
+	To      time.Time
+	Period  string
+	GroupBy string
+}
+
+// VolumeRow is the training volume of one group in one period. Period is the first day of the
+// bucket (YYYY-MM-DD, weeks start on Monday) and Tonnage is reps x weight in kilograms.
+type VolumeRow struct {
+	Period    string  `json:"period"`
+	GroupID   uint    `json:"group_id"`
+	GroupName string  `json:"group_name"`
+	Sets      int     `json:"sets"`
+	Reps      int     `json:"reps"`
+	Tonnage   float64 `json:"tonnage"`
+}
+
+// AnalyticsRepository runs aggregate queries over logged training data.
+type AnalyticsRepository interface {
+	Volume(ctx context.Context, q VolumeQuery) ([]VolumeRow, error)
+}
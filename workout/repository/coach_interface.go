@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
+)
+
+// CoachRepository persists AI coach threads and their messages.
+type CoachRepository interface {
+	CreateThread(ctx context.Context, thread *models.CoachThread) error
+	// GetThread returns a thread with its messages, oldest first.
+	GetThread(ctx context.Context, id uint) (*models.CoachThread, error)
+	// UpdateThread saves the thread's own fields; its messages are left alone.
+	UpdateThread(ctx context.Context, thread *models.CoachThread) error
+	// DeleteThread removes a thread together with its messages.
+	DeleteThread(ctx context.Context, id uint) error
+	// DeleteByUser removes every thread of a user together with its messages.
+	DeleteByUser(ctx context.Context, userID uint) error
+
+	// ListThreadsByUser lists up to limit threads of a user without their messages, most
+	// recently active first, starting after the given cursor.
+	ListThreadsByUser(ctx context.Context, userID uint, limit int, after *pagination.Cursor) ([]*models.CoachThread, error)
+
+	// AddMessages appends messages to their thread in one transaction and marks the thread as
+	// updated, so a question is never stored without its answer.
+	AddMessages(ctx context.Context, messages ...*models.CoachMessage) error
+}
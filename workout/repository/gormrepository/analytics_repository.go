@@ -0,0 +1,65 @@
+package gormrepository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
+)
+
+// gormAnalyticsRepository implements repository.AnalyticsRepository with aggregate SQL.
+type gormAnalyticsRepository struct {
+	db *gorm.DB
+}
+
+// NewAnalyticsRepository returns a GORM-backed analytics repository.
+func NewAnalyticsRepository(db *gorm.DB) repository.AnalyticsRepository {
+	return &gormAnalyticsRepository{db: db}
+}
+
+// Sets belong to the exercise's workout type, or to the session's for sets without an exercise.
+// Pound loads are converted so tonnage is always in kilograms.
+const volumeQuery = `
+SELECT %s AS period, %s AS group_id, %s AS group_name,
+	COUNT(*) AS sets,
+	SUM(st.reps) AS reps,
+	SUM(st.reps * CASE WHEN st.unit = 'lb' THEN st.weight * 0.45359237 ELSE st.weight END) AS tonnage
+FROM workout_sets st
+JOIN workout_sessions s ON s.id = st.workout_session_id
+LEFT JOIN workout_exercises e ON e.id = st.workout_exercise_id
+JOIN workout_types t ON t.id = COALESCE(e.workout_type_id, s.workout_type_id)
+JOIN muscle_groups mg ON mg.id = t.muscle_group_id
+WHERE s.user_id = ? AND s.datetime >= ? AND s.datetime < ? AND st.completed = ?
+GROUP BY 1, 2, 3
+ORDER BY 1, 3`
+
+func (r *gormAnalyticsRepository) Volume(ctx context.Context, q repository.VolumeQuery) ([]repository.VolumeRow, error) {
+	groupID, groupName := "mg.id", "mg.name"
+	if q.GroupBy == repository.GroupByWorkoutType {
+		groupID, groupName = "t.id", "t.name"
+	}
+	query := fmt.Sprintf(volumeQuery, r.periodExpr(q.Period), groupID, groupName)
+
+	rows := []repository.VolumeRow{}
+	err := r.db.WithContext(ctx).Raw(query, q.UserID, q.From, q.To, true).Scan(&rows).Error
+	return rows, err
+}
+
+// periodExpr truncates the session datetime to the first day of its week or month in the
+// dialect of the connected database.
+func (r *gormAnalyticsRepository) periodExpr(period string) string {
+	if r.db.Dialector.Name() == "sqlite" {
+		if period == repository.PeriodMonth {
+			return "strftime('%Y-%m-01', s.datetime)"
+		}
+		// next Sunday (or today when it is Sunday), then back to that week's Monday
+		return "date(s.datetime, 'weekday 0', '-6 days')"
+	}
+	unit := "week"
+	if period == repository.PeriodMonth {
+		unit = "month"
+	}
+	return "to_char(date_trunc('" + unit + "', s.datetime), 'YYYY-MM-DD')"
+}
@@ -0,0 +1,86 @@
+package gormrepository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
+)
+
+// gormCoachRepository implements repository.CoachRepository using GORM.
+type gormCoachRepository struct {
+	db *gorm.DB
+}
+
+// NewCoachRepository returns a GORM-backed coach thread repository.
+func NewCoachRepository(db *gorm.DB) repository.CoachRepository {
+	return &gormCoachRepository{db: db}
+}
+
+func (r *gormCoachRepository) CreateThread(ctx context.Context, thread *models.CoachThread) error {
+	return r.db.WithContext(ctx).Create(thread).Error
+}
+
+func (r *gormCoachRepository) GetThread(ctx context.Context, id uint) (*models.CoachThread, error) {
+	var thread models.CoachThread
+	err := r.db.WithContext(ctx).
+		Preload("Messages", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		First(&thread, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &thread, nil
+}
+
+func (r *gormCoachRepository) UpdateThread(ctx context.Context, thread *models.CoachThread) error {
+	return r.db.WithContext(ctx).Omit("Messages").Save(thread).Error
+}
+
+func (r *gormCoachRepository) DeleteThread(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("thread_id = ?", id).Delete(&models.CoachMessage{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.CoachThread{}, id).Error
+	})
+}
+
+func (r *gormCoachRepository) DeleteByUser(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		threads := tx.Session(&gorm.Session{NewDB: true}).
+			Model(&models.CoachThread{}).Select("id").Where("user_id = ?", userID)
+		if err := tx.Where("thread_id IN (?)", threads).Delete(&models.CoachMessage{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.CoachThread{}).Error
+	})
+}
+
+func (r *gormCoachRepository) ListThreadsByUser(ctx context.Context, userID uint, limit int, after *pagination.Cursor) ([]*models.CoachThread, error) {
+	var threads []*models.CoachThread
+	err := afterTime(r.db.WithContext(ctx), "updated_at", after, true).
+		Where("user_id = ?", userID).
+		Order("updated_at DESC, id DESC").
+		Limit(limit).
+		Find(&threads).Error
+	return threads, err
+}
+
+func (r *gormCoachRepository) AddMessages(ctx context.Context, messages ...*models.CoachMessage) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, message := range messages {
+			if err := tx.Create(message).Error; err != nil {
+				return err
+			}
+			err := tx.Model(&models.CoachThread{}).Where("id = ?", message.ThreadID).
+				Update("updated_at", message.CreatedAt).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -5,6 +5,7 @@ import (
 
 	"gorm.io/gorm"
 
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
 	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
 	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
 )
@@ -40,9 +41,9 @@ func (r *gormMuscleGroupRepository) Delete(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Delete(&models.MuscleGroup{}, id).Error
 }
 
-func (r *gormMuscleGroupRepository) List(ctx context.Context, limit, offset int) ([]*models.MuscleGroup, error) {
+func (r *gormMuscleGroupRepository) List(ctx context.Context, limit int, after *pagination.Cursor) ([]*models.MuscleGroup, error) {
 	var mgs []*models.MuscleGroup
-	err := r.db.WithContext(ctx).Limit(limit).Offset(offset).Find(&mgs).Error
+	err := afterID(r.db.WithContext(ctx), after).Order("id").Limit(limit).Find(&mgs).Error
 	return mgs, err
 }
 
@@ -0,0 +1,34 @@
+package gormrepository
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
+)
+
+// afterID continues an ID-ordered listing after the cursor.
+func afterID(db *gorm.DB, after *pagination.Cursor) *gorm.DB {
+	if after == nil {
+		return db
+	}
+	return db.Where("id > ?", after.ID)
+}
+
+// afterValue continues a listing ordered ascending by (column, id) after the row whose sort
+// key is value and whose ID is id.
+func afterValue(db *gorm.DB, column string, value any, id uint) *gorm.DB {
+	return db.Where(column+" > ? OR ("+column+" = ? AND id > ?)", value, value, id)
+}
+
+// afterTime continues a listing ordered by (column, id) after the cursor, walking
+// backwards in time when desc is set.
+func afterTime(db *gorm.DB, column string, after *pagination.Cursor, desc bool) *gorm.DB {
+	if after == nil {
+		return db
+	}
+	op := ">"
+	if desc {
+		op = "<"
+	}
+	return db.Where(column+" "+op+" ? OR ("+column+" = ? AND id "+op+" ?)", after.Time, after.Time, after.ID)
+}
@@ -0,0 +1,77 @@
+package gormrepository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
+)
+
+// gormPersonalRecordRepository implements repository.PersonalRecordRepository using GORM.
+type gormPersonalRecordRepository struct {
+	db *gorm.DB
+}
+
+// NewPersonalRecordRepository returns a GORM-backed PersonalRecord repository.
+func NewPersonalRecordRepository(db *gorm.DB) repository.PersonalRecordRepository {
+	return &gormPersonalRecordRepository{db: db}
+}
+
+func (r *gormPersonalRecordRepository) Create(ctx context.Context, record *models.PersonalRecord) error {
+	return r.db.WithContext(ctx).Create(record).Error
+}
+
+func (r *gormPersonalRecordRepository) Update(ctx context.Context, record *models.PersonalRecord) error {
+	return r.db.WithContext(ctx).Omit("WorkoutType").Save(record).Error
+}
+
+func (r *gormPersonalRecordRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.PersonalRecord{}, id).Error
+}
+
+func (r *gormPersonalRecordRepository) DeleteByUser(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.PersonalRecord{}).Error
+}
+
+func (r *gormPersonalRecordRepository) ListByUser(ctx context.Context, userID uint) ([]*models.PersonalRecord, error) {
+	var records []*models.PersonalRecord
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("achieved_at, id").
+		Preload("WorkoutType").
+		Find(&records).Error
+	return records, err
+}
+
+func (r *gormPersonalRecordRepository) ListByUserAndType(ctx context.Context, userID, workoutTypeID uint) ([]*models.PersonalRecord, error) {
+	var records []*models.PersonalRecord
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND workout_type_id = ?", userID, workoutTypeID).
+		Order("achieved_at, id").
+		Preload("WorkoutType").
+		Find(&records).Error
+	return records, err
+}
+
+func (r *gormPersonalRecordRepository) PageByUserAndType(ctx context.Context, userID, workoutTypeID uint, limit int, after *pagination.Cursor) ([]*models.PersonalRecord, error) {
+	var records []*models.PersonalRecord
+	err := afterTime(r.db.WithContext(ctx), "achieved_at", after, false).
+		Where("user_id = ? AND workout_type_id = ?", userID, workoutTypeID).
+		Order("achieved_at, id").
+		Limit(limit).
+		Preload("WorkoutType").
+		Find(&records).Error
+	return records, err
+}
+
+func (r *gormPersonalRecordRepository) ListBySession(ctx context.Context, sessionID uint) ([]*models.PersonalRecord, error) {
+	var records []*models.PersonalRecord
+	err := r.db.WithContext(ctx).
+		Where("workout_session_id = ?", sessionID).
+		Order("id").
+		Find(&records).Error
+	return records, err
+}
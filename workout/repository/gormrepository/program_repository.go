@@ -0,0 +1,239 @@
+package gormrepository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
+)
+
+// gormProgramRepository implements repository.ProgramRepository using GORM.
+type gormProgramRepository struct {
+	db *gorm.DB
+}
+
+// NewProgramRepository returns a GORM-backed Program repository.
+func NewProgramRepository(db *gorm.DB) repository.ProgramRepository {
+	return &gormProgramRepository{db: db}
+}
+
+func (r *gormProgramRepository) Create(ctx context.Context, program *models.Program) error {
+	return r.db.WithContext(ctx).Create(program).Error
+}
+
+func (r *gormProgramRepository) GetByID(ctx context.Context, id uint) (*models.Program, error) {
+	var program models.Program
+	err := preloadProgram(r.db.WithContext(ctx), "").First(&program, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &program, nil
+}
+
+func (r *gormProgramRepository) Update(ctx context.Context, program *models.Program) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(program).Error; err != nil {
+			return err
+		}
+		var existing []models.ProgramWeek
+		if err := tx.Preload("Days").Where("program_id = ?", program.ID).Find(&existing).Error; err != nil {
+			return err
+		}
+		oldWeeks := make(map[int]*models.ProgramWeek, len(existing))
+		for i := range existing {
+			oldWeeks[existing[i].WeekNumber] = &existing[i]
+		}
+
+		kept := make(map[uint]bool)
+		for wi := range program.Weeks {
+			week := &program.Weeks[wi]
+			week.ID, week.ProgramID = 0, program.ID
+			oldDays := make(map[int]uint)
+			if old := oldWeeks[week.WeekNumber]; old != nil {
+				week.ID = old.ID
+				for _, d := range old.Days {
+					oldDays[d.DayNumber] = d.ID
+				}
+			}
+			if err := tx.Omit(clause.Associations).Save(week).Error; err != nil {
+				return err
+			}
+			for di := range week.Days {
+				// days keep their IDs so sessions linked to them stay linked
+				day := &week.Days[di]
+				day.ID, day.ProgramWeekID = oldDays[day.DayNumber], week.ID
+				if err := tx.Omit(clause.Associations).Save(day).Error; err != nil {
+					return err
+				}
+				kept[day.ID] = true
+				if err := tx.Where("program_day_id = ?", day.ID).Delete(&models.ProgramPrescription{}).Error; err != nil {
+					return err
+				}
+				for pi := range day.Prescriptions {
+					day.Prescriptions[pi].ID, day.Prescriptions[pi].ProgramDayID = 0, day.ID
+				}
+				if len(day.Prescriptions) > 0 {
+					if err := tx.Omit(clause.Associations).Create(&day.Prescriptions).Error; err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		var staleDays, staleWeeks []uint
+		for _, w := range existing {
+			if program.Week(w.WeekNumber) == nil {
+				staleWeeks = append(staleWeeks, w.ID)
+			}
+			for _, d := range w.Days {
+				if !kept[d.ID] {
+					staleDays = append(staleDays, d.ID)
+				}
+			}
+		}
+		if len(staleDays) > 0 {
+			if err := tx.Where("program_day_id IN ?", staleDays).Delete(&models.ProgramPrescription{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Delete(&models.ProgramDay{}, staleDays).Error; err != nil {
+				return err
+			}
+		}
+		if len(staleWeeks) > 0 {
+			return tx.Delete(&models.ProgramWeek{}, staleWeeks).Error
+		}
+		return nil
+	})
+}
+
+func (r *gormProgramRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return deleteProgram(tx, id)
+	})
+}
+
+func (r *gormProgramRepository) DeleteByUser(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		if err := tx.Model(&models.Program{}).Where("user_id = ?", userID).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err := deleteProgram(tx, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *gormProgramRepository) List(ctx context.Context, limit int, after *pagination.Cursor) ([]*models.Program, error) {
+	var programs []*models.Program
+	err := afterID(r.db.WithContext(ctx), after).Order("id").Limit(limit).Find(&programs).Error
+	return programs, err
+}
+
+func (r *gormProgramRepository) Count(ctx context.Context) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Program{}).Count(&count).Error
+	return int(count), err
+}
+
+// gormProgramEnrollmentRepository implements repository.ProgramEnrollmentRepository using GORM.
+type gormProgramEnrollmentRepository struct {
+	db *gorm.DB
+}
+
+// NewProgramEnrollmentRepository returns a GORM-backed ProgramEnrollment repository.
+func NewProgramEnrollmentRepository(db *gorm.DB) repository.ProgramEnrollmentRepository {
+	return &gormProgramEnrollmentRepository{db: db}
+}
+
+func (r *gormProgramEnrollmentRepository) Create(ctx context.Context, enrollment *models.ProgramEnrollment) error {
+	return r.db.WithContext(ctx).Create(enrollment).Error
+}
+
+func (r *gormProgramEnrollmentRepository) GetByID(ctx context.Context, id uint) (*models.ProgramEnrollment, error) {
+	var enrollment models.ProgramEnrollment
+	err := preloadProgram(r.db.WithContext(ctx), "Program.").
+		Preload("Program").
+		First(&enrollment, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &enrollment, nil
+}
+
+func (r *gormProgramEnrollmentRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.ProgramEnrollment{}, id).Error
+}
+
+func (r *gormProgramEnrollmentRepository) DeleteByUser(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.ProgramEnrollment{}).Error
+}
+
+func (r *gormProgramEnrollmentRepository) ListByUser(ctx context.Context, userID uint, limit int, after *pagination.Cursor) ([]*models.ProgramEnrollment, error) {
+	var enrollments []*models.ProgramEnrollment
+	err := afterTime(r.db.WithContext(ctx), "start_date", after, true).
+		Where("user_id = ?", userID).
+		Order("start_date DESC, id DESC").
+		Limit(limit).
+		Preload("Program").
+		Find(&enrollments).Error
+	return enrollments, err
+}
+
+func (r *gormProgramEnrollmentRepository) CompletedDayIDs(ctx context.Context, enrollmentID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).
+		Model(&models.WorkoutSession{}).
+		Where("program_enrollment_id = ? AND program_day_id IS NOT NULL", enrollmentID).
+		Distinct().
+		Pluck("program_day_id", &ids).Error
+	return ids, err
+}
+
+// preloadProgram preloads the week/day/prescription tree below the given association prefix.
+func preloadProgram(db *gorm.DB, prefix string) *gorm.DB {
+	return db.
+		Preload(prefix+"Weeks", func(db *gorm.DB) *gorm.DB { return db.Order("week_number") }).
+		Preload(prefix+"Weeks.Days", func(db *gorm.DB) *gorm.DB { return db.Order("day_number") }).
+		Preload(prefix+"Weeks.Days.Prescriptions", orderExercises).
+		Preload(prefix + "Weeks.Days.Prescriptions.WorkoutType")
+}
+
+// deleteProgram ends every enrollment in a program, unlinking the sessions logged for them,
+// and removes the program with its tree.
+func deleteProgram(tx *gorm.DB, id uint) error {
+	enrollments := tx.Session(&gorm.Session{NewDB: true}).
+		Model(&models.ProgramEnrollment{}).Select("id").Where("program_id = ?", id)
+	err := tx.Model(&models.WorkoutSession{}).Where("program_enrollment_id IN (?)", enrollments).
+		Updates(map[string]any{"program_enrollment_id": nil, "program_day_id": nil}).Error
+	if err != nil {
+		return err
+	}
+	if err := tx.Where("program_id = ?", id).Delete(&models.ProgramEnrollment{}).Error; err != nil {
+		return err
+	}
+	if err := deleteProgramTree(tx, id); err != nil {
+		return err
+	}
+	return tx.Delete(&models.Program{}, id).Error
+}
+
+// deleteProgramTree removes all weeks, days and prescriptions of a program.
+func deleteProgramTree(tx *gorm.DB, programID uint) error {
+	weeks := tx.Model(&models.ProgramWeek{}).Select("id").Where("program_id = ?", programID)
+	days := tx.Model(&models.ProgramDay{}).Select("id").Where("program_week_id IN (?)", weeks)
+	if err := tx.Where("program_day_id IN (?)", days).Delete(&models.ProgramPrescription{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("program_week_id IN (?)", weeks).Delete(&models.ProgramDay{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("program_id = ?", programID).Delete(&models.ProgramWeek{}).Error
+}
@@ -23,7 +23,16 @@ func newTestDB(t *testing.T) *gorm.DB {
 		&models.MuscleGroup{},
 		&models.WorkoutType{},
 		&models.WorkoutSession{},
+		&models.WorkoutExercise{},
 		&models.WorkoutDetail{},
+		&models.WorkoutSet{},
+		&models.Program{},
+		&models.ProgramWeek{},
+		&models.ProgramDay{},
+		&models.ProgramPrescription{},
+		&models.ProgramEnrollment{},
+		&models.CoachThread{},
+		&models.CoachMessage{},
 	); err != nil {
 		t.Fatalf("migrating schema: %v", err)
 	}
@@ -65,7 +74,7 @@ func TestMuscleGroupCRUD(t *testing.T) {
 	}
 
 	// LIST / COUNT
-	list, err := mgRepo.List(ctx, 10, 0)
+	list, err := mgRepo.List(ctx, 10, nil)
 	if err != nil || len(list) != 1 {
 		t.Fatalf("list: want 1, got %d (err=%v)", len(list), err)
 	}
@@ -169,3 +178,338 @@ func TestWorkoutSessionWithDetails(t *testing.T) {
 		t.Fatalf("details not persisted: %+v", stored.Details)
 	}
 }
+
+/*
+Typed sets are listed in set order and preloaded with their session.
+*/
+func TestWorkoutSetsOrderedBySetIndex(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	wsRepo := NewWorkoutSessionRepository(db)
+	setRepo := NewWorkoutSetRepository(db)
+
+	session := &models.WorkoutSession{WorkoutTypeID: 1, UserID: 7, Datetime: time.Now()}
+	if err := wsRepo.Create(ctx, session); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	for _, idx := range []int{2, 1} {
+		set := &models.WorkoutSet{WorkoutSessionID: session.ID, SetIndex: idx, Reps: 5, Weight: 100, Unit: models.UnitKilograms}
+		if err := setRepo.Create(ctx, set); err != nil {
+			t.Fatalf("create set: %v", err)
+		}
+	}
+
+	sets, err := setRepo.ListBySession(ctx, session.ID)
+	if err != nil || len(sets) != 2 {
+		t.Fatalf("list: want 2, got %d (err=%v)", len(sets), err)
+	}
+	if sets[0].SetIndex != 1 || sets[1].SetIndex != 2 {
+		t.Fatalf("list: wrong order %d, %d", sets[0].SetIndex, sets[1].SetIndex)
+	}
+
+	stored, err := wsRepo.GetByID(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("get session: %v", err)
+	}
+	if len(stored.Sets) != 2 || stored.Sets[0].SetIndex != 1 {
+		t.Fatalf("sets not preloaded in order: %+v", stored.Sets)
+	}
+}
+
+/*
+Exercises inside one session can be appended, reordered and removed; the
+session's primary workout type follows the first exercise.
+*/
+func TestWorkoutSessionExercises(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	wsRepo := NewWorkoutSessionRepository(db)
+
+	session := &models.WorkoutSession{
+		WorkoutTypeID: 10,
+		UserID:        8,
+		Datetime:      time.Now(),
+		Exercises: []models.WorkoutExercise{
+			{WorkoutTypeID: 10, Position: 1, Sets: []models.WorkoutSet{{SetIndex: 1, Reps: 5}}},
+			{WorkoutTypeID: 11, Position: 2},
+		},
+	}
+	if err := wsRepo.Create(ctx, session); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	if session.Exercises[0].Sets[0].WorkoutSessionID != session.ID {
+		t.Fatalf("nested set not linked to session")
+	}
+
+	third := &models.WorkoutExercise{WorkoutSessionID: session.ID, WorkoutTypeID: 12}
+	if err := wsRepo.AddExercise(ctx, third); err != nil {
+		t.Fatalf("add exercise: %v", err)
+	}
+	if third.Position != 3 {
+		t.Fatalf("add exercise: want position 3, got %d", third.Position)
+	}
+
+	first, second := session.Exercises[0].ID, session.Exercises[1].ID
+	if err := wsRepo.ReorderExercises(ctx, session.ID, []uint{first, second}); err != repository.ErrInvalidExerciseOrder {
+		t.Fatalf("reorder: want ErrInvalidExerciseOrder, got %v", err)
+	}
+	if err := wsRepo.ReorderExercises(ctx, session.ID, []uint{third.ID, first, second}); err != nil {
+		t.Fatalf("reorder: %v", err)
+	}
+
+	if err := wsRepo.RemoveExercise(ctx, session.ID, first); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+
+	stored, err := wsRepo.GetByID(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("get session: %v", err)
+	}
+	if len(stored.Exercises) != 2 || stored.Exercises[0].ID != third.ID || stored.Exercises[1].Position != 2 {
+		t.Fatalf("unexpected exercises %+v", stored.Exercises)
+	}
+	if stored.WorkoutTypeID != 12 {
+		t.Fatalf("primary type: want 12, got %d", stored.WorkoutTypeID)
+	}
+	if len(stored.Sets) != 0 {
+		t.Fatalf("sets of removed exercise still present: %+v", stored.Sets)
+	}
+}
+
+/*
+Deleting a user's sessions removes their children and leaves other users alone.
+*/
+func TestWorkoutSessionDeleteByUser(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	wsRepo := NewWorkoutSessionRepository(db)
+
+	var ids []uint
+	for _, userID := range []uint{41, 41, 42} {
+		session := &models.WorkoutSession{
+			WorkoutTypeID: 10,
+			UserID:        userID,
+			Datetime:      time.Now(),
+			Exercises: []models.WorkoutExercise{
+				{WorkoutTypeID: 10, Position: 1, Sets: []models.WorkoutSet{{SetIndex: 1, Reps: 5}}},
+			},
+			Details: []models.WorkoutDetail{{DetailName: "note", DetailValue: "x"}},
+		}
+		if err := wsRepo.Create(ctx, session); err != nil {
+			t.Fatalf("create session: %v", err)
+		}
+		ids = append(ids, session.ID)
+	}
+
+	if err := wsRepo.DeleteByUser(ctx, 41); err != nil {
+		t.Fatalf("delete by user: %v", err)
+	}
+
+	for _, model := range []any{&models.WorkoutExercise{}, &models.WorkoutSet{}, &models.WorkoutDetail{}} {
+		var count int64
+		if err := db.Model(model).Where("workout_session_id IN ?", ids[:2]).Count(&count).Error; err != nil {
+			t.Fatalf("count %T: %v", model, err)
+		}
+		if count != 0 {
+			t.Fatalf("%T of deleted sessions left: %d", model, count)
+		}
+	}
+	if _, err := wsRepo.GetByID(ctx, ids[0]); err == nil {
+		t.Fatalf("session of deleted user still present")
+	}
+	kept, err := wsRepo.GetByID(ctx, ids[2])
+	if err != nil {
+		t.Fatalf("other user's session: %v", err)
+	}
+	if len(kept.Exercises) != 1 || len(kept.Sets) != 1 || len(kept.Details) != 1 {
+		t.Fatalf("other user's session lost children: %+v", kept)
+	}
+}
+
+/*
+Updating a program replaces its week/day tree, and completed days are derived
+from sessions linked to the enrollment.
+*/
+func TestProgramTreeAndCompletedDays(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	programRepo := NewProgramRepository(db)
+	enrollmentRepo := NewProgramEnrollmentRepository(db)
+	wsRepo := NewWorkoutSessionRepository(db)
+
+	day := func(n int) models.ProgramDay {
+		return models.ProgramDay{DayNumber: n, Prescriptions: []models.ProgramPrescription{{WorkoutTypeID: 1, Position: 1, Sets: 5, Reps: 5}}}
+	}
+	program := &models.Program{UserID: 9, Name: "Linear", Weeks: []models.ProgramWeek{
+		{WeekNumber: 1, Days: []models.ProgramDay{day(1), day(3), day(5)}},
+	}}
+	if err := programRepo.Create(ctx, program); err != nil {
+		t.Fatalf("create program: %v", err)
+	}
+	firstDayID := program.Weeks[0].Days[0].ID
+
+	program.Weeks = []models.ProgramWeek{
+		{WeekNumber: 1, Days: []models.ProgramDay{day(1), day(4)}},
+		{WeekNumber: 2, Days: []models.ProgramDay{day(1)}},
+	}
+	if err := programRepo.Update(ctx, program); err != nil {
+		t.Fatalf("update program: %v", err)
+	}
+	stored, err := programRepo.GetByID(ctx, program.ID)
+	if err != nil {
+		t.Fatalf("get program: %v", err)
+	}
+	if len(stored.Weeks) != 2 || len(stored.Weeks[0].Days) != 2 || stored.Weeks[0].Days[1].DayNumber != 4 {
+		t.Fatalf("tree not replaced: %+v", stored.Weeks)
+	}
+	if stored.Weeks[0].Days[0].ID != firstDayID {
+		t.Fatalf("unchanged day got a new ID: %d, was %d", stored.Weeks[0].Days[0].ID, firstDayID)
+	}
+	var days, prescriptions int64
+	db.Model(&models.ProgramDay{}).Count(&days)
+	if days != 3 {
+		t.Fatalf("stale days left behind: %d", days)
+	}
+	db.Model(&models.ProgramPrescription{}).Count(&prescriptions)
+	if prescriptions != 3 {
+		t.Fatalf("stale prescriptions left behind: %d", prescriptions)
+	}
+
+	enrollment := &models.ProgramEnrollment{ProgramID: program.ID, UserID: 9, StartDate: time.Now()}
+	if err := enrollmentRepo.Create(ctx, enrollment); err != nil {
+		t.Fatalf("enroll: %v", err)
+	}
+	dayID := stored.Weeks[0].Days[0].ID
+	for i := 0; i < 2; i++ {
+		session := &models.WorkoutSession{WorkoutTypeID: 1, UserID: 9, Datetime: time.Now(), ProgramEnrollmentID: &enrollment.ID, ProgramDayID: &dayID}
+		if err := wsRepo.Create(ctx, session); err != nil {
+			t.Fatalf("create session: %v", err)
+		}
+	}
+	ids, err := enrollmentRepo.CompletedDayIDs(ctx, enrollment.ID)
+	if err != nil || len(ids) != 1 || ids[0] != dayID {
+		t.Fatalf("completed days: got %v (err=%v)", ids, err)
+	}
+
+	loaded, err := enrollmentRepo.GetByID(ctx, enrollment.ID)
+	if err != nil || loaded.Program == nil || len(loaded.Program.Weeks) != 2 {
+		t.Fatalf("enrollment program not preloaded: %+v (err=%v)", loaded, err)
+	}
+
+	if err := programRepo.Delete(ctx, program.ID); err != nil {
+		t.Fatalf("delete program: %v", err)
+	}
+	db.Model(&models.ProgramDay{}).Count(&days)
+	if days != 0 {
+		t.Fatalf("days not cascaded: %d", days)
+	}
+}
+
+/*
+Deleting an author's programs also ends other users' enrollments in them; their
+sessions stay, unlinked. Programs of other authors are untouched.
+*/
+func TestProgramDeleteByUser(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	programRepo := NewProgramRepository(db)
+	enrollmentRepo := NewProgramEnrollmentRepository(db)
+	wsRepo := NewWorkoutSessionRepository(db)
+	const author, athlete uint = 8001, 8002
+
+	newProgram := func(userID uint) *models.Program {
+		program := &models.Program{UserID: userID, Name: "Block", Weeks: []models.ProgramWeek{
+			{WeekNumber: 1, Days: []models.ProgramDay{{DayNumber: 1, Prescriptions: []models.ProgramPrescription{{WorkoutTypeID: 1, Position: 1, Sets: 3, Reps: 5}}}}},
+		}}
+		if err := programRepo.Create(ctx, program); err != nil {
+			t.Fatalf("create program: %v", err)
+		}
+		return program
+	}
+	authored, own := newProgram(author), newProgram(athlete)
+
+	enrollment := &models.ProgramEnrollment{ProgramID: authored.ID, UserID: athlete, StartDate: time.Now()}
+	if err := enrollmentRepo.Create(ctx, enrollment); err != nil {
+		t.Fatalf("enroll: %v", err)
+	}
+	dayID := authored.Weeks[0].Days[0].ID
+	session := &models.WorkoutSession{WorkoutTypeID: 1, UserID: athlete, Datetime: time.Now(), ProgramEnrollmentID: &enrollment.ID, ProgramDayID: &dayID}
+	if err := wsRepo.Create(ctx, session); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	// a retried deletion finds nothing left to do
+	for i := 0; i < 2; i++ {
+		if err := programRepo.DeleteByUser(ctx, author); err != nil {
+			t.Fatalf("delete by user: %v", err)
+		}
+	}
+
+	if _, err := programRepo.GetByID(ctx, authored.ID); err == nil {
+		t.Fatal("authored program survived")
+	}
+	if _, err := enrollmentRepo.GetByID(ctx, enrollment.ID); err == nil {
+		t.Fatal("other user's enrollment in the deleted program survived")
+	}
+	kept, err := wsRepo.GetByID(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("other user's session: %v", err)
+	}
+	if kept.ProgramEnrollmentID != nil || kept.ProgramDayID != nil {
+		t.Fatalf("session still linked to the deleted program: %+v", kept)
+	}
+	if _, err := programRepo.GetByID(ctx, own.ID); err != nil {
+		t.Fatalf("other author's program: %v", err)
+	}
+}
+
+/*
+Coach threads list by latest message and are removed with their messages.
+*/
+func TestCoachThreads(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewCoachRepository(db)
+	const userID, otherUserID uint = 7001, 7002
+
+	older := &models.CoachThread{UserID: userID, Title: "Shoulder"}
+	newer := &models.CoachThread{UserID: userID, Title: "Deload"}
+	other := &models.CoachThread{UserID: otherUserID, Title: "Mine"}
+	for _, thread := range []*models.CoachThread{older, newer, other} {
+		if err := repo.CreateThread(ctx, thread); err != nil {
+			t.Fatalf("create thread: %v", err)
+		}
+	}
+	time.Sleep(time.Millisecond)
+	for _, content := range []string{"my shoulder hurts", "swap the bench"} {
+		if err := repo.AddMessages(ctx, &models.CoachMessage{ThreadID: older.ID, Role: models.CoachRoleUser, Content: content}); err != nil {
+			t.Fatalf("add message: %v", err)
+		}
+	}
+
+	list, err := repo.ListThreadsByUser(ctx, userID, 10, nil)
+	if err != nil || len(list) != 2 || list[0].ID != older.ID {
+		t.Fatalf("list: want the thread with the latest message first, got %+v (err=%v)", list, err)
+	}
+	got, err := repo.GetThread(ctx, older.ID)
+	if err != nil || len(got.Messages) != 2 || got.Messages[1].Content != "swap the bench" {
+		t.Fatalf("get: unexpected thread %+v (err=%v)", got, err)
+	}
+
+	if err := repo.DeleteByUser(ctx, userID); err != nil {
+		t.Fatalf("delete by user: %v", err)
+	}
+	var messages int64
+	db.Model(&models.CoachMessage{}).Where("thread_id = ?", older.ID).Count(&messages)
+	if list, _ := repo.ListThreadsByUser(ctx, userID, 10, nil); len(list) != 0 || messages != 0 {
+		t.Fatalf("delete by user: %d threads and %d messages left", len(list), messages)
+	}
+	if _, err := repo.GetThread(ctx, other.ID); err != nil {
+		t.Fatalf("delete by user removed another user's thread: %v", err)
+	}
+}
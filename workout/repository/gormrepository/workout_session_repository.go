@@ -2,9 +2,11 @@ package gormrepository
 
 import (
 	"context"
+	"time"
 
 	"gorm.io/gorm"
 
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
 	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
 	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
 )
@@ -27,7 +29,11 @@ func (r *gormWorkoutSessionRepository) GetByID(ctx context.Context, id uint) (*m
 	var session models.WorkoutSession
 	err := r.db.WithContext(ctx).
 		Preload("WorkoutType").
+		Preload("Exercises", orderExercises).
+		Preload("Exercises.WorkoutType").
+		Preload("Exercises.Sets", orderSets).
 		Preload("Details").
+		Preload("Sets", orderSets).
 		First(&session, id).Error
 	if err != nil {
 		return nil, err
@@ -40,24 +46,234 @@ func (r *gormWorkoutSessionRepository) Update(ctx context.Context, session *mode
 }
 
 func (r *gormWorkoutSessionRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&models.WorkoutSession{}, id).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, child := range []any{&models.WorkoutSet{}, &models.WorkoutDetail{}, &models.WorkoutExercise{}, &models.PersonalRecord{}} {
+			if err := tx.Where("workout_session_id = ?", id).Delete(child).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&models.WorkoutSession{}, id).Error
+	})
 }
 
-func (r *gormWorkoutSessionRepository) ListByUser(ctx context.Context, userID uint, limit, offset int) ([]*models.WorkoutSession, error) {
+func (r *gormWorkoutSessionRepository) DeleteByUser(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		sessions := tx.Session(&gorm.Session{NewDB: true}).
+			Model(&models.WorkoutSession{}).Select("id").Where("user_id = ?", userID)
+		for _, child := range []any{&models.WorkoutSet{}, &models.WorkoutDetail{}, &models.WorkoutExercise{}} {
+			if err := tx.Where("workout_session_id IN (?)", sessions).Delete(child).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.WorkoutSession{}).Error
+	})
+}
+
+func (r *gormWorkoutSessionRepository) ListByUser(ctx context.Context, userID uint, limit int, after *pagination.Cursor) ([]*models.WorkoutSession, error) {
 	var sessions []*models.WorkoutSession
-	err := r.db.WithContext(ctx).
+	err := afterTime(r.db.WithContext(ctx), "datetime", after, true).
 		Where("user_id = ?", userID).
-		Order("datetime DESC").
+		Order("datetime DESC, id DESC").
 		Limit(limit).
-		Offset(offset).
 		Preload("WorkoutType").
+		Preload("Exercises", orderExercises).
+		Preload("Exercises.WorkoutType").
+		Preload("Exercises.Sets", orderSets).
+		Preload("Details").
+		Preload("Sets", orderSets).
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *gormWorkoutSessionRepository) Query(ctx context.Context, q repository.SessionQuery) ([]*models.WorkoutSession, error) {
+	order := "datetime DESC, id DESC"
+	if q.Ascending {
+		order = "datetime, id"
+	}
+	var sessions []*models.WorkoutSession
+	err := afterTime(filterSessions(r.db.WithContext(ctx), q), "workout_sessions.datetime", q.After, !q.Ascending).
+		Order(order).
+		Limit(q.Limit).
+		Preload("WorkoutType").
+		Preload("Exercises", orderExercises).
+		Preload("Exercises.WorkoutType").
+		Preload("Exercises.Sets", orderSets).
 		Preload("Details").
+		Preload("Sets", orderSets).
 		Find(&sessions).Error
 	return sessions, err
 }
 
-func (r *gormWorkoutSessionRepository) CountByUser(ctx context.Context, userID uint) (int, error) {
+func (r *gormWorkoutSessionRepository) CountByUser(ctx context.Context, q repository.SessionQuery) (int, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(&models.WorkoutSession{}).Where("user_id = ?", userID).Count(&count).Error
+	err := filterSessions(r.db.WithContext(ctx).Model(&models.WorkoutSession{}), q).Count(&count).Error
 	return int(count), err
 }
+
+// filterSessions applies the filters of q to a query on workout_sessions.
+func filterSessions(db *gorm.DB, q repository.SessionQuery) *gorm.DB {
+	db = db.Where("workout_sessions.user_id = ?", q.UserID)
+	if q.From != nil {
+		db = db.Where("workout_sessions.datetime >= ?", *q.From)
+	}
+	if q.To != nil {
+		db = db.Where("workout_sessions.datetime < ?", *q.To)
+	}
+
+	// a session trains every type it lists as an exercise, plus its primary type
+	if q.WorkoutTypeID != 0 {
+		exercises := db.Session(&gorm.Session{NewDB: true}).
+			Table("workout_exercises").
+			Select("workout_session_id").
+			Where("workout_type_id = ?", q.WorkoutTypeID)
+		db = db.Where("workout_sessions.workout_type_id = ? OR workout_sessions.id IN (?)", q.WorkoutTypeID, exercises)
+	}
+	if q.MuscleGroupID != 0 {
+		types := db.Session(&gorm.Session{NewDB: true}).
+			Model(&models.WorkoutType{}).
+			Select("id").
+			Where("muscle_group_id = ?", q.MuscleGroupID)
+		exercises := db.Session(&gorm.Session{NewDB: true}).
+			Table("workout_exercises").
+			Select("workout_session_id").
+			Where("workout_type_id IN (?)", types)
+		db = db.Where("workout_sessions.workout_type_id IN (?) OR workout_sessions.id IN (?)", types, exercises)
+	}
+	if q.HasDetails != nil {
+		details := db.Session(&gorm.Session{NewDB: true}).
+			Model(&models.WorkoutDetail{}).
+			Select("workout_session_id")
+		if *q.HasDetails {
+			db = db.Where("workout_sessions.id IN (?)", details)
+		} else {
+			db = db.Where("workout_sessions.id NOT IN (?)", details)
+		}
+	}
+	return db
+}
+
+func (r *gormWorkoutSessionRepository) ListByUserInRange(ctx context.Context, userID uint, from, to time.Time) ([]*models.WorkoutSession, error) {
+	var sessions []*models.WorkoutSession
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND datetime >= ? AND datetime < ?", userID, from, to).
+		Order("datetime, id").
+		Preload("WorkoutType").
+		Preload("Exercises", orderExercises).
+		Preload("Exercises.WorkoutType").
+		Preload("Exercises.Sets", orderSets).
+		Preload("Details").
+		Preload("Sets", orderSets).
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *gormWorkoutSessionRepository) AddExercise(ctx context.Context, exercise *models.WorkoutExercise) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var last int
+		err := tx.Model(&models.WorkoutExercise{}).
+			Where("workout_session_id = ?", exercise.WorkoutSessionID).
+			Select("COALESCE(MAX(position), 0)").
+			Scan(&last).Error
+		if err != nil {
+			return err
+		}
+		exercise.Position = last + 1
+		if err := tx.Create(exercise).Error; err != nil {
+			return err
+		}
+		return syncPrimaryType(tx, exercise.WorkoutSessionID)
+	})
+}
+
+func (r *gormWorkoutSessionRepository) ReorderExercises(ctx context.Context, sessionID uint, exerciseIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing []uint
+		err := tx.Model(&models.WorkoutExercise{}).
+			Where("workout_session_id = ?", sessionID).
+			Pluck("id", &existing).Error
+		if err != nil {
+			return err
+		}
+		if !sameIDs(existing, exerciseIDs) {
+			return repository.ErrInvalidExerciseOrder
+		}
+		for pos, id := range exerciseIDs {
+			err := tx.Model(&models.WorkoutExercise{}).
+				Where("id = ?", id).
+				Update("position", pos+1).Error
+			if err != nil {
+				return err
+			}
+		}
+		return syncPrimaryType(tx, sessionID)
+	})
+}
+
+func (r *gormWorkoutSessionRepository) RemoveExercise(ctx context.Context, sessionID, exerciseID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("id = ? AND workout_session_id = ?", exerciseID, sessionID).Delete(&models.WorkoutExercise{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Where("workout_exercise_id = ?", exerciseID).Delete(&models.WorkoutSet{}).Error; err != nil {
+			return err
+		}
+
+		var remaining []uint
+		err := tx.Model(&models.WorkoutExercise{}).
+			Where("workout_session_id = ?", sessionID).
+			Order("position").
+			Pluck("id", &remaining).Error
+		if err != nil {
+			return err
+		}
+		for pos, id := range remaining {
+			if err := tx.Model(&models.WorkoutExercise{}).Where("id = ?", id).Update("position", pos+1).Error; err != nil {
+				return err
+			}
+		}
+		return syncPrimaryType(tx, sessionID)
+	})
+}
+
+// syncPrimaryType points the session's WorkoutTypeID at its first exercise, if any.
+func syncPrimaryType(tx *gorm.DB, sessionID uint) error {
+	var first models.WorkoutExercise
+	err := tx.Where("workout_session_id = ?", sessionID).Order("position").Limit(1).Find(&first).Error
+	if err != nil || first.ID == 0 {
+		return err
+	}
+	return tx.Model(&models.WorkoutSession{}).
+		Where("id = ?", sessionID).
+		UpdateColumn("workout_type_id", first.WorkoutTypeID).Error
+}
+
+// sameIDs reports whether got is a permutation of want.
+func sameIDs(want, got []uint) bool {
+	if len(want) != len(got) {
+		return false
+	}
+	seen := make(map[uint]bool, len(want))
+	for _, id := range want {
+		seen[id] = true
+	}
+	for _, id := range got {
+		if !seen[id] {
+			return false
+		}
+		delete(seen, id)
+	}
+	return true
+}
+
+func orderExercises(db *gorm.DB) *gorm.DB {
+	return db.Order("position, id")
+}
+
+// orderSets keeps preloaded sets in the order they were performed.
+func orderSets(db *gorm.DB) *gorm.DB {
+	return db.Order("set_index, id")
+}
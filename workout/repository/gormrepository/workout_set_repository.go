@@ -0,0 +1,71 @@
+package gormrepository
+
+import (
+	"context"
+	"strconv"
+
+	"gorm.io/gorm"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
+)
+
+// gormWorkoutSetRepository implements repository.WorkoutSetRepository using GORM.
+type gormWorkoutSetRepository struct {
+	db *gorm.DB
+}
+
+// NewWorkoutSetRepository returns a GORM-backed WorkoutSet repository.
+func NewWorkoutSetRepository(db *gorm.DB) repository.WorkoutSetRepository {
+	return &gormWorkoutSetRepository{db: db}
+}
+
+func (r *gormWorkoutSetRepository) Create(ctx context.Context, set *models.WorkoutSet) error {
+	return r.db.WithContext(ctx).Create(set).Error
+}
+
+func (r *gormWorkoutSetRepository) GetByID(ctx context.Context, id uint) (*models.WorkoutSet, error) {
+	var set models.WorkoutSet
+	err := r.db.WithContext(ctx).First(&set, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &set, nil
+}
+
+func (r *gormWorkoutSetRepository) Update(ctx context.Context, set *models.WorkoutSet) error {
+	return r.db.WithContext(ctx).Save(set).Error
+}
+
+func (r *gormWorkoutSetRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.WorkoutSet{}, id).Error
+}
+
+func (r *gormWorkoutSetRepository) ListBySession(ctx context.Context, sessionID uint) ([]*models.WorkoutSet, error) {
+	var sets []*models.WorkoutSet
+	err := r.db.WithContext(ctx).
+		Where("workout_session_id = ?", sessionID).
+		Order("set_index, id").
+		Find(&sets).Error
+	return sets, err
+}
+
+func (r *gormWorkoutSetRepository) PageBySession(ctx context.Context, sessionID uint, limit int, after *pagination.Cursor) ([]*models.WorkoutSet, error) {
+	db := r.db.WithContext(ctx)
+	if after != nil {
+		// the cursor key is the set index of the last set seen
+		index, err := strconv.Atoi(after.Key)
+		if err != nil {
+			return nil, pagination.ErrInvalidCursor
+		}
+		db = afterValue(db, "set_index", index, after.ID)
+	}
+	var sets []*models.WorkoutSet
+	err := db.
+		Where("workout_session_id = ?", sessionID).
+		Order("set_index, id").
+		Limit(limit).
+		Find(&sets).Error
+	return sets, err
+}
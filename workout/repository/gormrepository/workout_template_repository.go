@@ -0,0 +1,92 @@
+package gormrepository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
+)
+
+// gormWorkoutTemplateRepository implements repository.WorkoutTemplateRepository using GORM.
+type gormWorkoutTemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewWorkoutTemplateRepository returns a GORM-backed WorkoutTemplate repository.
+func NewWorkoutTemplateRepository(db *gorm.DB) repository.WorkoutTemplateRepository {
+	return &gormWorkoutTemplateRepository{db: db}
+}
+
+func (r *gormWorkoutTemplateRepository) Create(ctx context.Context, template *models.WorkoutTemplate) error {
+	return r.db.WithContext(ctx).Create(template).Error
+}
+
+func (r *gormWorkoutTemplateRepository) GetByID(ctx context.Context, id uint) (*models.WorkoutTemplate, error) {
+	var template models.WorkoutTemplate
+	err := r.db.WithContext(ctx).
+		Preload("Exercises", orderExercises).
+		Preload("Exercises.WorkoutType").
+		First(&template, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *gormWorkoutTemplateRepository) Update(ctx context.Context, template *models.WorkoutTemplate) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("workout_template_id = ?", template.ID).Delete(&models.WorkoutTemplateExercise{}).Error; err != nil {
+			return err
+		}
+		for i := range template.Exercises {
+			template.Exercises[i].ID = 0
+			template.Exercises[i].WorkoutTemplateID = template.ID
+		}
+		return tx.Save(template).Error
+	})
+}
+
+func (r *gormWorkoutTemplateRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("workout_template_id = ?", id).Delete(&models.WorkoutTemplateExercise{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.WorkoutTemplate{}, id).Error
+	})
+}
+
+func (r *gormWorkoutTemplateRepository) DeleteByUser(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		templates := tx.Session(&gorm.Session{NewDB: true}).
+			Model(&models.WorkoutTemplate{}).Select("id").Where("user_id = ?", userID)
+		if err := tx.Where("workout_template_id IN (?)", templates).Delete(&models.WorkoutTemplateExercise{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.WorkoutTemplate{}).Error
+	})
+}
+
+func (r *gormWorkoutTemplateRepository) ListByUser(ctx context.Context, userID uint, limit int, after *pagination.Cursor) ([]*models.WorkoutTemplate, error) {
+	var templates []*models.WorkoutTemplate
+	db := r.db.WithContext(ctx)
+	if after != nil {
+		db = afterValue(db, "name", after.Key, after.ID)
+	}
+	err := db.
+		Where("user_id = ?", userID).
+		Order("name, id").
+		Limit(limit).
+		Preload("Exercises", orderExercises).
+		Preload("Exercises.WorkoutType").
+		Find(&templates).Error
+	return templates, err
+}
+
+func (r *gormWorkoutTemplateRepository) CountByUser(ctx context.Context, userID uint) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.WorkoutTemplate{}).Where("user_id = ?", userID).Count(&count).Error
+	return int(count), err
+}
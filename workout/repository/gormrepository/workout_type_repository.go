@@ -5,6 +5,7 @@ import (
 
 	"gorm.io/gorm"
 
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
 	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
 	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
 )
@@ -40,9 +41,9 @@ func (r *gormWorkoutTypeRepository) Delete(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Delete(&models.WorkoutType{}, id).Error
 }
 
-func (r *gormWorkoutTypeRepository) List(ctx context.Context, limit, offset int) ([]*models.WorkoutType, error) {
+func (r *gormWorkoutTypeRepository) List(ctx context.Context, limit int, after *pagination.Cursor) ([]*models.WorkoutType, error) {
 	var wts []*models.WorkoutType
-	err := r.db.WithContext(ctx).Preload("MuscleGroup").Limit(limit).Offset(offset).Find(&wts).Error
+	err := afterID(r.db.WithContext(ctx), after).Preload("MuscleGroup").Order("id").Limit(limit).Find(&wts).Error
 	return wts, err
 }
 
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
 	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
 )
 
@@ -12,6 +13,7 @@ type MuscleGroupRepository interface {
 	GetByID(ctx context.Context, id uint) (*models.MuscleGroup, error)
 	Update(ctx context.Context, mg *models.MuscleGroup) error
 	Delete(ctx context.Context, id uint) error
-	List(ctx context.Context, limit, offset int) ([]*models.MuscleGroup, error)
+	// List returns up to limit muscle groups ordered by ID, starting after the given cursor.
+	List(ctx context.Context, limit int, after *pagination.Cursor) ([]*models.MuscleGroup, error)
 	Count(ctx context.Context) (int, error)
 }
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
+)
+
+// PersonalRecordRepository stores personal records and their history.
+type PersonalRecordRepository interface {
+	Create(ctx context.Context, record *models.PersonalRecord) error
+	Update(ctx context.Context, record *models.PersonalRecord) error
+	Delete(ctx context.Context, id uint) error
+	// ListByUser returns all records of a user, oldest first.
+	ListByUser(ctx context.Context, userID uint) ([]*models.PersonalRecord, error)
+	// ListByUserAndType returns the record history of a user for one WorkoutType, oldest first.
+	ListByUserAndType(ctx context.Context, userID, workoutTypeID uint) ([]*models.PersonalRecord, error)
+	// PageByUserAndType returns up to limit records of that history, oldest first, starting after the given cursor.
+	PageByUserAndType(ctx context.Context, userID, workoutTypeID uint, limit int, after *pagination.Cursor) ([]*models.PersonalRecord, error)
+	// ListBySession returns the records set in one WorkoutSession.
+	ListBySession(ctx context.Context, sessionID uint) ([]*models.PersonalRecord, error)
+	// DeleteByUser removes the whole record history of a user.
+	DeleteByUser(ctx context.Context, userID uint) error
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
+)
+
+// ProgramRepository provides CRUD operations for Program entities including their weeks, days and prescriptions.
+type ProgramRepository interface {
+	Create(ctx context.Context, program *models.Program) error
+	GetByID(ctx context.Context, id uint) (*models.Program, error)
+	// Update saves the program and its week/day/prescription tree. Weeks and days are matched by
+	// number and updated in place, keeping their IDs; those missing from the program are removed.
+	Update(ctx context.Context, program *models.Program) error
+	// Delete removes the program with its tree and ends every enrollment in it, whoever enrolled.
+	// Sessions logged for the program are kept but no longer linked to it.
+	Delete(ctx context.Context, id uint) error
+	// DeleteByUser deletes every program a user authored like Delete does, so enrollments of
+	// other users in them end too.
+	DeleteByUser(ctx context.Context, userID uint) error
+	// List returns up to limit programs ordered by ID, starting after the given cursor.
+	List(ctx context.Context, limit int, after *pagination.Cursor) ([]*models.Program, error)
+	Count(ctx context.Context) (int, error)
+}
+
+// ProgramEnrollmentRepository provides operations for users' program enrollments.
+type ProgramEnrollmentRepository interface {
+	Create(ctx context.Context, enrollment *models.ProgramEnrollment) error
+	// GetByID returns the enrollment with its full program preloaded.
+	GetByID(ctx context.Context, id uint) (*models.ProgramEnrollment, error)
+	Delete(ctx context.Context, id uint) error
+	// DeleteByUser removes every enrollment of a user. Programs the user authored are removed
+	// by ProgramRepository.DeleteByUser.
+	DeleteByUser(ctx context.Context, userID uint) error
+	// ListByUser returns up to limit enrollments of a user, latest start first, starting after the given cursor.
+	ListByUser(ctx context.Context, userID uint, limit int, after *pagination.Cursor) ([]*models.ProgramEnrollment, error)
+
+	// CompletedDayIDs returns the distinct program days that have a linked workout session.
+	CompletedDayIDs(ctx context.Context, enrollmentID uint) ([]uint, error)
+}
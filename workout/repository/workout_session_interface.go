@@ -2,7 +2,10 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
 	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
 )
 
@@ -11,9 +14,46 @@ type WorkoutSessionRepository interface {
 	Create(ctx context.Context, session *models.WorkoutSession) error
 	GetByID(ctx context.Context, id uint) (*models.WorkoutSession, error)
 	Update(ctx context.Context, session *models.WorkoutSession) error
+	// Delete removes a session together with its exercises, sets, details and personal records.
 	Delete(ctx context.Context, id uint) error
+	// DeleteByUser removes every session of a user together with its exercises, sets and details.
+	DeleteByUser(ctx context.Context, userID uint) error
 
-	// ListByUser lists all sessions for a specific user with pagination.
-	ListByUser(ctx context.Context, userID uint, limit, offset int) ([]*models.WorkoutSession, error)
-	CountByUser(ctx context.Context, userID uint) (int, error)
-}
\ No newline at end of file
+	// ListByUser lists up to limit sessions of a user, newest first, starting after the given cursor.
+	ListByUser(ctx context.Context, userID uint, limit int, after *pagination.Cursor) ([]*models.WorkoutSession, error)
+	// Query lists the sessions matching q.
+	Query(ctx context.Context, q SessionQuery) ([]*models.WorkoutSession, error)
+	// CountByUser counts the sessions matching q, ignoring its ordering and paging.
+	CountByUser(ctx context.Context, q SessionQuery) (int, error)
+	// ListByUserInRange lists a user's sessions with from <= datetime < to, oldest first.
+	ListByUserInRange(ctx context.Context, userID uint, from, to time.Time) ([]*models.WorkoutSession, error)
+
+	// AddExercise appends an exercise to the end of its session.
+	AddExercise(ctx context.Context, exercise *models.WorkoutExercise) error
+	// ReorderExercises sets the exercise order of a session; exerciseIDs must list every exercise exactly once.
+	ReorderExercises(ctx context.Context, sessionID uint, exerciseIDs []uint) error
+	// RemoveExercise deletes an exercise together with its sets and closes the gap in positions.
+	RemoveExercise(ctx context.Context, sessionID, exerciseID uint) error
+}
+
+// ErrInvalidExerciseOrder is returned when a reorder request does not match the session's exercises.
+var ErrInvalidExerciseOrder = errors.New("exercise ids must list every exercise of the session exactly once")
+
+// SessionQuery selects a user's sessions. Zero-valued filters are not applied.
+type SessionQuery struct {
+	UserID uint
+	// From and To bound the session datetime as From <= datetime < To.
+	From *time.Time
+	To   *time.Time
+	// WorkoutTypeID matches the session type or any of its exercises.
+	WorkoutTypeID uint
+	// MuscleGroupID matches sessions training the group through any of their workout types.
+	MuscleGroupID uint
+	// HasDetails keeps only sessions with (true) or without (false) key-value details.
+	HasDetails *bool
+	// Ascending orders by datetime oldest first instead of newest first.
+	Ascending bool
+	Limit     int
+	// After continues the listing after this (datetime, id) cursor in the requested order.
+	After *pagination.Cursor
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
+)
+
+// WorkoutSetRepository provides CRUD operations for WorkoutSet entities.
+type WorkoutSetRepository interface {
+	Create(ctx context.Context, set *models.WorkoutSet) error
+	GetByID(ctx context.Context, id uint) (*models.WorkoutSet, error)
+	Update(ctx context.Context, set *models.WorkoutSet) error
+	Delete(ctx context.Context, id uint) error
+
+	// ListBySession returns the sets of a session ordered by set index.
+	ListBySession(ctx context.Context, sessionID uint) ([]*models.WorkoutSet, error)
+	// PageBySession returns up to limit sets of a session ordered by set index, starting after
+	// the given cursor.
+	PageBySession(ctx context.Context, sessionID uint, limit int, after *pagination.Cursor) ([]*models.WorkoutSet, error)
+}
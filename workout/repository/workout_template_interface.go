@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
+)
+
+// WorkoutTemplateRepository provides CRUD operations for WorkoutTemplate entities and their exercises.
+type WorkoutTemplateRepository interface {
+	Create(ctx context.Context, template *models.WorkoutTemplate) error
+	GetByID(ctx context.Context, id uint) (*models.WorkoutTemplate, error)
+	// Update saves the template and replaces its exercises with template.Exercises.
+	Update(ctx context.Context, template *models.WorkoutTemplate) error
+	// Delete removes the template together with its exercises.
+	Delete(ctx context.Context, id uint) error
+	// DeleteByUser removes every template of a user together with its exercises.
+	DeleteByUser(ctx context.Context, userID uint) error
+
+	// ListByUser lists up to limit templates of a user by name, starting after the given cursor.
+	ListByUser(ctx context.Context, userID uint, limit int, after *pagination.Cursor) ([]*models.WorkoutTemplate, error)
+	CountByUser(ctx context.Context, userID uint) (int, error)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
 	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
 )
 
@@ -12,6 +13,7 @@ type WorkoutTypeRepository interface {
 	GetByID(ctx context.Context, id uint) (*models.WorkoutType, error)
 	Update(ctx context.Context, wt *models.WorkoutType) error
 	Delete(ctx context.Context, id uint) error
-	List(ctx context.Context, limit, offset int) ([]*models.WorkoutType, error)
+	// List returns up to limit workout types ordered by ID, starting after the given cursor.
+	List(ctx context.Context, limit int, after *pagination.Cursor) ([]*models.WorkoutType, error)
 	Count(ctx context.Context) (int, error)
 }
\ No newline at end of file
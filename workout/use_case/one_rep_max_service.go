@@ -0,0 +1,168 @@
+package use_case
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
+)
+
+// Formula names an estimated one-rep-max formula.
+type Formula string
+
+// Supported one-rep-max formulas.
+const (
+	FormulaEpley    Formula = "epley"
+	FormulaBrzycki  Formula = "brzycki"
+	FormulaLombardi Formula = "lombardi"
+)
+
+// ErrUnknownFormula is returned when a formula name is not supported.
+var ErrUnknownFormula = errors.New("unknown one-rep-max formula (use epley, brzycki or lombardi)")
+
+// ParseFormula resolves a case-insensitive formula name.
+func ParseFormula(name string) (Formula, error) {
+	switch f := Formula(strings.ToLower(strings.TrimSpace(name))); f {
+	case FormulaEpley, FormulaBrzycki, FormulaLombardi:
+		return f, nil
+	}
+	return "", ErrUnknownFormula
+}
+
+// maxBrzyckiReps is the highest rep count the Brzycki formula is trusted for.
+const maxBrzyckiReps = 12
+
+// Estimate returns the estimated one-rep max for weight lifted for reps. A single rep is
+// its own max; zero reps or weight estimate nothing. Brzycki falls back to Epley above
+// maxBrzyckiReps reps.
+func (f Formula) Estimate(weight float64, reps int) float64 {
+	if weight <= 0 || reps <= 0 {
+		return 0
+	}
+	if reps == 1 {
+		return weight
+	}
+	r := float64(reps)
+	switch f {
+	case FormulaBrzycki:
+		if reps <= maxBrzyckiReps {
+			return weight * 36 / (37 - r)
+		}
+		// the formula climbs towards infinity at 37 reps; use Epley beyond its valid range
+		return FormulaEpley.Estimate(weight, reps)
+	case FormulaLombardi:
+		return weight * math.Pow(r, 0.10)
+	default:
+		return weight * (1 + r/30)
+	}
+}
+
+// oneRepMaxLookback is how far back Current looks for the best recent estimate.
+const oneRepMaxLookback = 90 * 24 * time.Hour
+
+// OneRepMaxPoint is the best estimated one-rep max of one session, in kilograms.
+type OneRepMaxPoint struct {
+	Date      time.Time `json:"date"`
+	SessionID uint      `json:"session_id"`
+	Estimate  float64   `json:"estimate"`
+	Weight    float64   `json:"weight"`
+	Reps      int       `json:"reps"`
+}
+
+// OneRepMaxService estimates one-rep maxes from logged sets.
+type OneRepMaxService struct {
+	sessionRepo repository.WorkoutSessionRepository
+	formula     Formula
+}
+
+// NewOneRepMaxService wires the session repository into a OneRepMaxService using formula
+// whenever callers do not ask for a specific one.
+func NewOneRepMaxService(sessionRepo repository.WorkoutSessionRepository, formula Formula) *OneRepMaxService {
+	if formula == "" {
+		formula = FormulaEpley
+	}
+	return &OneRepMaxService{sessionRepo: sessionRepo, formula: formula}
+}
+
+// Default returns the configured default formula.
+func (s *OneRepMaxService) Default() Formula {
+	return s.formula
+}
+
+// Series returns one point per session in [from, to) with a completed set of the WorkoutType,
+// oldest first. An empty formula uses the default.
+func (s *OneRepMaxService) Series(ctx context.Context, userID, workoutTypeID uint, from, to time.Time, formula Formula) ([]OneRepMaxPoint, error) {
+	if formula == "" {
+		formula = s.formula
+	}
+	sessions, err := s.sessionRepo.ListByUserInRange(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	points := []OneRepMaxPoint{}
+	for _, session := range sessions {
+		var best OneRepMaxPoint
+		for _, ts := range sessionSets(session) {
+			if ts.typeID != workoutTypeID || !ts.set.Completed {
+				continue
+			}
+			if e := formula.Estimate(ts.set.WeightKg(), ts.set.Reps); e > best.Estimate {
+				best = OneRepMaxPoint{Date: session.Datetime, SessionID: session.ID, Estimate: e, Weight: ts.set.WeightKg(), Reps: ts.set.Reps}
+			}
+		}
+		if best.Estimate > 0 {
+			points = append(points, best)
+		}
+	}
+	return points, nil
+}
+
+// Current returns the best estimate of the last 90 days using the default formula; ok is
+// false when there is no recent weighted set of the WorkoutType.
+func (s *OneRepMaxService) Current(ctx context.Context, userID, workoutTypeID uint) (float64, bool, error) {
+	now := time.Now()
+	points, err := s.Series(ctx, userID, workoutTypeID, now.Add(-oneRepMaxLookback), now.Add(time.Minute), s.formula)
+	if err != nil {
+		return 0, false, err
+	}
+	best := 0.0
+	for _, p := range points {
+		best = math.Max(best, p.Estimate)
+	}
+	return best, best > 0, nil
+}
+
+// Prescribe turns a percentage of the current one-rep max into a working weight in
+// kilograms, rounded down to the nearest 2.5 kg plate step.
+func (s *OneRepMaxService) Prescribe(ctx context.Context, userID, workoutTypeID uint, percent float64) (float64, bool, error) {
+	oneRM, ok, err := s.Current(ctx, userID, workoutTypeID)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+	return math.Floor(oneRM*percent/100/2.5) * 2.5, true, nil
+}
+
+// CurrentByType returns the current estimate for every WorkoutType trained in the last 90
+// days, keyed by WorkoutType ID.
+func (s *OneRepMaxService) CurrentByType(ctx context.Context, userID uint) (map[uint]float64, error) {
+	now := time.Now()
+	sessions, err := s.sessionRepo.ListByUserInRange(ctx, userID, now.Add(-oneRepMaxLookback), now.Add(time.Minute))
+	if err != nil {
+		return nil, err
+	}
+	best := make(map[uint]float64)
+	for _, session := range sessions {
+		for _, ts := range sessionSets(session) {
+			if !ts.set.Completed {
+				continue
+			}
+			if e := s.formula.Estimate(ts.set.WeightKg(), ts.set.Reps); e > best[ts.typeID] {
+				best[ts.typeID] = e
+			}
+		}
+	}
+	return best, nil
+}
@@ -0,0 +1,37 @@
+package use_case_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/VibeTeam/fitness-tracker-backend/workout/use_case"
+)
+
+func TestOneRepMaxFormulas(t *testing.T) {
+	// 100 kg x 10
+	require.InDelta(t, 133.33, use_case.FormulaEpley.Estimate(100, 10), 0.01)
+	require.InDelta(t, 133.33, use_case.FormulaBrzycki.Estimate(100, 10), 0.01)
+	require.InDelta(t, 125.89, use_case.FormulaLombardi.Estimate(100, 10), 0.01)
+
+	// a single is its own max and empty sets estimate nothing
+	for _, f := range []use_case.Formula{use_case.FormulaEpley, use_case.FormulaBrzycki, use_case.FormulaLombardi} {
+		require.Equal(t, 140.0, f.Estimate(140, 1))
+		require.Zero(t, f.Estimate(0, 10))
+		require.Zero(t, f.Estimate(100, 0))
+	}
+
+	// Brzycki is used up to 12 reps and falls back to Epley beyond
+	require.InDelta(t, 144.0, use_case.FormulaBrzycki.Estimate(100, 12), 0.01)
+	require.InDelta(t, 143.33, use_case.FormulaBrzycki.Estimate(100, 13), 0.01)
+	require.InDelta(t, 266.67, use_case.FormulaBrzycki.Estimate(100, 50), 0.01)
+}
+
+func TestParseFormula(t *testing.T) {
+	f, err := use_case.ParseFormula(" Brzycki ")
+	require.NoError(t, err)
+	require.Equal(t, use_case.FormulaBrzycki, f)
+
+	_, err = use_case.ParseFormula("mayhew")
+	require.ErrorIs(t, err, use_case.ErrUnknownFormula)
+}
@@ -0,0 +1,285 @@
+// Package use_case holds workout-domain workflows that combine several repositories.
+package use_case
+
+import (
+	"context"
+	"sort"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
+)
+
+// RecordService detects and stores personal records from logged sessions.
+type RecordService struct {
+	repo repository.PersonalRecordRepository
+}
+
+// NewRecordService wires the record repository into a ready-to-use RecordService.
+func NewRecordService(repo repository.PersonalRecordRepository) *RecordService {
+	return &RecordService{repo: repo}
+}
+
+// Detect brings the records of session up to date with its completed sets. A session holds
+// at most one record per WorkoutType and record slot: its best performance, kept only while it
+// beats the user's records from earlier sessions, so editing an old session keeps the records it
+// set even when later sessions beat them. Detecting again after sets were edited or
+// deleted raises, lowers or removes the session's records. It returns the records that were
+// created or improved, so detecting an unchanged session twice yields nothing the second time.
+// Legacy details are used for sessions without typed sets.
+func (s *RecordService) Detect(ctx context.Context, session *models.WorkoutSession) ([]*models.PersonalRecord, error) {
+	candidates := sessionCandidates(session)
+	stored, err := s.repo.ListBySession(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	own := make(map[uint]map[string]*models.PersonalRecord)
+	for _, rec := range stored {
+		if own[rec.WorkoutTypeID] == nil {
+			own[rec.WorkoutTypeID] = make(map[string]*models.PersonalRecord)
+		}
+		if _, dup := own[rec.WorkoutTypeID][rec.Key()]; dup {
+			// sessions detected before records were kept per session may hold several
+			if err := s.repo.Delete(ctx, rec.ID); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		own[rec.WorkoutTypeID][rec.Key()] = rec
+		if _, ok := candidates[rec.WorkoutTypeID]; !ok {
+			candidates[rec.WorkoutTypeID] = nil
+		}
+	}
+
+	var improved []*models.PersonalRecord
+	for _, typeID := range sortedKeys(candidates) {
+		history, err := s.repo.ListByUserAndType(ctx, session.UserID, typeID)
+		if err != nil {
+			return nil, err
+		}
+		earlier := history[:0:0]
+		for _, rec := range history {
+			if achievedBefore(rec, session) {
+				earlier = append(earlier, rec)
+			}
+		}
+		best := bestByKey(earlier)
+		for _, rec := range candidates[typeID] {
+			old := own[typeID][rec.Key()]
+			delete(own[typeID], rec.Key())
+			if cur, ok := best[rec.Key()]; ok && rec.Value <= cur.Value {
+				if old != nil {
+					if err := s.repo.Delete(ctx, old.ID); err != nil {
+						return nil, err
+					}
+				}
+				continue
+			}
+			if old == nil {
+				if err := s.repo.Create(ctx, rec); err != nil {
+					return nil, err
+				}
+				improved = append(improved, rec)
+				continue
+			}
+			if sameRecord(old, rec) {
+				continue
+			}
+			rec.ID, rec.CreatedAt = old.ID, old.CreatedAt
+			if err := s.repo.Update(ctx, rec); err != nil {
+				return nil, err
+			}
+			if rec.Value > old.Value {
+				improved = append(improved, rec)
+			}
+		}
+		// slots the session no longer has a performance in
+		for _, old := range own[typeID] {
+			if err := s.repo.Delete(ctx, old.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return improved, nil
+}
+
+// Current returns up to limit of the user's standing records, one per WorkoutType and record
+// slot, ordered by WorkoutType ID and slot. The cursor of a record carries its WorkoutType ID
+// as ID and its slot as Key.
+func (s *RecordService) Current(ctx context.Context, userID uint, limit int, after *pagination.Cursor) ([]*models.PersonalRecord, error) {
+	records, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byType := make(map[uint][]*models.PersonalRecord)
+	for _, r := range records {
+		byType[r.WorkoutTypeID] = append(byType[r.WorkoutTypeID], r)
+	}
+	current := make([]*models.PersonalRecord, 0, len(records))
+	for _, typeID := range sortedKeys(byType) {
+		best := bestByKey(byType[typeID])
+		keys := make([]string, 0, len(best))
+		for k := range best {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if after != nil && (typeID < after.ID || (typeID == after.ID && k <= after.Key)) {
+				continue
+			}
+			current = append(current, best[k])
+			if len(current) == limit {
+				return current, nil
+			}
+		}
+	}
+	return current, nil
+}
+
+// History returns up to limit records the user set for a WorkoutType, oldest first, starting
+// after the given cursor.
+func (s *RecordService) History(ctx context.Context, userID, workoutTypeID uint, limit int, after *pagination.Cursor) ([]*models.PersonalRecord, error) {
+	return s.repo.PageByUserAndType(ctx, userID, workoutTypeID, limit, after)
+}
+
+// achievedBefore reports whether rec was set in a session that started before session; sessions
+// starting at the same time are ordered by ID.
+func achievedBefore(rec *models.PersonalRecord, session *models.WorkoutSession) bool {
+	if rec.WorkoutSessionID == session.ID {
+		return false
+	}
+	if rec.AchievedAt.Equal(session.Datetime) {
+		return rec.WorkoutSessionID < session.ID
+	}
+	return rec.AchievedAt.Before(session.Datetime)
+}
+
+// sessionCandidates computes the best performance per WorkoutType and record slot in session.
+func sessionCandidates(session *models.WorkoutSession) map[uint][]*models.PersonalRecord {
+	best := make(map[uint]map[string]*models.PersonalRecord)
+	volume := make(map[uint]float64)
+
+	offer := func(typeID uint, rec *models.PersonalRecord) {
+		if best[typeID] == nil {
+			best[typeID] = make(map[string]*models.PersonalRecord)
+		}
+		if cur, ok := best[typeID][rec.Key()]; !ok || rec.Value > cur.Value {
+			best[typeID][rec.Key()] = rec
+		}
+	}
+	newRecord := func(typeID uint, kind string, value float64, set models.WorkoutSet) *models.PersonalRecord {
+		rec := &models.PersonalRecord{
+			UserID:           session.UserID,
+			WorkoutTypeID:    typeID,
+			Kind:             kind,
+			Value:            value,
+			Reps:             set.Reps,
+			Weight:           set.WeightKg(),
+			WorkoutSessionID: session.ID,
+			AchievedAt:       session.Datetime,
+		}
+		if set.ID != 0 {
+			id := set.ID
+			rec.WorkoutSetID = &id
+		}
+		return rec
+	}
+
+	for _, ts := range sessionSets(session) {
+		set := ts.set
+		if !set.Completed || set.Reps <= 0 {
+			continue
+		}
+		kg := set.WeightKg()
+		if kg > 0 {
+			// e1RM records always use Epley so they stay comparable when the default formula changes
+			offer(ts.typeID, newRecord(ts.typeID, models.RecordHeaviestWeight, kg, set))
+			offer(ts.typeID, newRecord(ts.typeID, models.RecordEstimated1RM, FormulaEpley.Estimate(kg, set.Reps), set))
+			volume[ts.typeID] += kg * float64(set.Reps)
+		}
+		offer(ts.typeID, newRecord(ts.typeID, models.RecordMostReps, float64(set.Reps), set))
+	}
+	for typeID, v := range volume {
+		offer(typeID, newRecord(typeID, models.RecordVolume, v, models.WorkoutSet{}))
+	}
+
+	out := make(map[uint][]*models.PersonalRecord, len(best))
+	for typeID, slots := range best {
+		keys := make([]string, 0, len(slots))
+		for k := range slots {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			out[typeID] = append(out[typeID], slots[k])
+		}
+	}
+	return out
+}
+
+// typedSet is a set together with the WorkoutType it was performed for.
+type typedSet struct {
+	typeID uint
+	set    models.WorkoutSet
+}
+
+// sessionSets flattens the sets of a session, attributing each one to its exercise's type or,
+// without an exercise, to the session type. Sets are collected from both the session and its
+// exercises so freshly created and reloaded sessions look the same.
+func sessionSets(session *models.WorkoutSession) []typedSet {
+	exerciseType := make(map[uint]uint, len(session.Exercises))
+	seen := make(map[uint]bool)
+	var out []typedSet
+	for _, ex := range session.Exercises {
+		exerciseType[ex.ID] = ex.WorkoutTypeID
+		for _, set := range ex.Sets {
+			seen[set.ID] = true
+			out = append(out, typedSet{ex.WorkoutTypeID, set})
+		}
+	}
+	for _, set := range session.Sets {
+		if seen[set.ID] {
+			continue
+		}
+		typeID := session.WorkoutTypeID
+		if set.WorkoutExerciseID != nil {
+			if t, ok := exerciseType[*set.WorkoutExerciseID]; ok {
+				typeID = t
+			}
+		}
+		out = append(out, typedSet{typeID, set})
+	}
+	if len(out) == 0 {
+		for _, set := range models.SetsFromDetails(session.Details) {
+			out = append(out, typedSet{session.WorkoutTypeID, set})
+		}
+	}
+	return out
+}
+
+// sameRecord reports whether two records of the same slot describe the same performance.
+func sameRecord(a, b *models.PersonalRecord) bool {
+	sameSet := (a.WorkoutSetID == nil) == (b.WorkoutSetID == nil) &&
+		(a.WorkoutSetID == nil || *a.WorkoutSetID == *b.WorkoutSetID)
+	return sameSet && a.Value == b.Value && a.Reps == b.Reps && a.Weight == b.Weight && a.AchievedAt.Equal(b.AchievedAt)
+}
+
+// bestByKey keeps the highest record per record slot.
+func bestByKey(records []*models.PersonalRecord) map[string]*models.PersonalRecord {
+	best := make(map[string]*models.PersonalRecord, len(records))
+	for _, r := range records {
+		if cur, ok := best[r.Key()]; !ok || r.Value > cur.Value {
+			best[r.Key()] = r
+		}
+	}
+	return best
+}
+
+func sortedKeys[V any](m map[uint]V) []uint {
+	keys := make([]uint, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
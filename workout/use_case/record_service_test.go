@@ -0,0 +1,276 @@
+package use_case_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/use_case"
+)
+
+/* -------------------------------------------------------------------------- */
+/* In-memory PersonalRecordRepository stub                                    */
+/* -------------------------------------------------------------------------- */
+
+type inMemRecordRepo struct {
+	records []*models.PersonalRecord
+	nextID  uint
+}
+
+func (r *inMemRecordRepo) Create(_ context.Context, rec *models.PersonalRecord) error {
+	r.nextID++
+	rec.ID = r.nextID
+	r.records = append(r.records, rec)
+	return nil
+}
+
+func (r *inMemRecordRepo) Update(_ context.Context, rec *models.PersonalRecord) error {
+	for i, cur := range r.records {
+		if cur.ID == rec.ID {
+			r.records[i] = rec
+		}
+	}
+	return nil
+}
+
+func (r *inMemRecordRepo) Delete(_ context.Context, id uint) error {
+	kept := r.records[:0]
+	for _, rec := range r.records {
+		if rec.ID != id {
+			kept = append(kept, rec)
+		}
+	}
+	r.records = kept
+	return nil
+}
+
+func (r *inMemRecordRepo) ListBySession(_ context.Context, sessionID uint) ([]*models.PersonalRecord, error) {
+	var out []*models.PersonalRecord
+	for _, rec := range r.records {
+		if rec.WorkoutSessionID == sessionID {
+			out = append(out, rec)
+		}
+	}
+	return out, nil
+}
+
+func (r *inMemRecordRepo) ListByUser(_ context.Context, userID uint) ([]*models.PersonalRecord, error) {
+	var out []*models.PersonalRecord
+	for _, rec := range r.records {
+		if rec.UserID == userID {
+			out = append(out, rec)
+		}
+	}
+	return out, nil
+}
+
+func (r *inMemRecordRepo) ListByUserAndType(_ context.Context, userID, workoutTypeID uint) ([]*models.PersonalRecord, error) {
+	var out []*models.PersonalRecord
+	for _, rec := range r.records {
+		if rec.UserID == userID && rec.WorkoutTypeID == workoutTypeID {
+			out = append(out, rec)
+		}
+	}
+	return out, nil
+}
+
+func (r *inMemRecordRepo) PageByUserAndType(ctx context.Context, userID, workoutTypeID uint, limit int, after *pagination.Cursor) ([]*models.PersonalRecord, error) {
+	all, _ := r.ListByUserAndType(ctx, userID, workoutTypeID)
+	var out []*models.PersonalRecord
+	for _, rec := range all {
+		if (after == nil || rec.ID > after.ID) && len(out) < limit {
+			out = append(out, rec)
+		}
+	}
+	return out, nil
+}
+
+func (r *inMemRecordRepo) DeleteByUser(_ context.Context, userID uint) error {
+	kept := r.records[:0]
+	for _, rec := range r.records {
+		if rec.UserID != userID {
+			kept = append(kept, rec)
+		}
+	}
+	r.records = kept
+	return nil
+}
+
+/* -------------------------------------------------------------------------- */
+/* helpers                                                                    */
+/* -------------------------------------------------------------------------- */
+
+func session(id uint, sets ...models.WorkoutSet) *models.WorkoutSession {
+	for i := range sets {
+		sets[i].ID = id*100 + uint(i)
+		sets[i].Completed = true
+	}
+	return &models.WorkoutSession{ID: id, UserID: 1, WorkoutTypeID: 5, Datetime: time.Now(), Sets: sets}
+}
+
+func kinds(records []*models.PersonalRecord) map[string]float64 {
+	out := make(map[string]float64)
+	for _, r := range records {
+		out[r.Key()] = r.Value
+	}
+	return out
+}
+
+/* -------------------------------------------------------------------------- */
+/* tests                                                                      */
+/* -------------------------------------------------------------------------- */
+
+func TestDetectFirstSessionSetsAllRecords(t *testing.T) {
+	svc := use_case.NewRecordService(&inMemRecordRepo{})
+
+	got, err := svc.Detect(context.Background(), session(1,
+		models.WorkoutSet{Reps: 5, Weight: 100, Unit: models.UnitKilograms},
+		models.WorkoutSet{Reps: 8, Weight: 90, Unit: models.UnitKilograms},
+	))
+	require.NoError(t, err)
+
+	k := kinds(got)
+	require.Equal(t, 100.0, k[models.RecordHeaviestWeight])
+	require.InDelta(t, 116.67, k[models.RecordEstimated1RM], 0.01) // 100 * (1 + 5/30) beats 90 * (1 + 8/30)
+	require.Equal(t, 1220.0, k[models.RecordVolume])
+	require.Equal(t, 5.0, k[models.RecordMostReps+"@100"])
+	require.Equal(t, 8.0, k[models.RecordMostReps+"@90"])
+}
+
+func TestDetectOnlyStoresImprovements(t *testing.T) {
+	ctx := context.Background()
+	repo := &inMemRecordRepo{}
+	svc := use_case.NewRecordService(repo)
+
+	first := session(1, models.WorkoutSet{Reps: 5, Weight: 100, Unit: models.UnitKilograms})
+	_, err := svc.Detect(ctx, first)
+	require.NoError(t, err)
+
+	// detecting the same session again is a no-op
+	again, err := svc.Detect(ctx, first)
+	require.NoError(t, err)
+	require.Empty(t, again)
+
+	// one more rep at the same weight beats reps, e1RM and volume but not weight
+	got, err := svc.Detect(ctx, session(2, models.WorkoutSet{Reps: 6, Weight: 100, Unit: models.UnitKilograms}))
+	require.NoError(t, err)
+	k := kinds(got)
+	require.Len(t, k, 3)
+	require.Equal(t, 6.0, k[models.RecordMostReps+"@100"])
+	require.NotContains(t, k, models.RecordHeaviestWeight)
+
+	// pounds are compared in kilograms: 225 lb is about 102 kg
+	got, err = svc.Detect(ctx, session(3, models.WorkoutSet{Reps: 1, Weight: 225, Unit: models.UnitPounds}))
+	require.NoError(t, err)
+	require.InDelta(t, 102.06, kinds(got)[models.RecordHeaviestWeight], 0.01)
+
+	current, err := svc.Current(ctx, 1, 100, nil)
+	require.NoError(t, err)
+	require.Len(t, current, 5) // weight, e1RM, volume, reps@100, reps@102.06
+
+	// pages continue after the slot of the cursor
+	page, err := svc.Current(ctx, 1, 2, nil)
+	require.NoError(t, err)
+	require.Equal(t, current[:2], page)
+	page, err = svc.Current(ctx, 1, 100, &pagination.Cursor{ID: page[1].WorkoutTypeID, Key: page[1].Key()})
+	require.NoError(t, err)
+	require.Equal(t, current[2:], page)
+}
+
+func TestDetectIgnoresPlannedSetsAndUsesDetails(t *testing.T) {
+	svc := use_case.NewRecordService(&inMemRecordRepo{})
+
+	planned := session(1, models.WorkoutSet{Reps: 5, Weight: 100})
+	planned.Sets[0].Completed = false
+	got, err := svc.Detect(context.Background(), planned)
+	require.NoError(t, err)
+	require.Empty(t, got)
+
+	legacy := &models.WorkoutSession{ID: 2, UserID: 1, WorkoutTypeID: 5, Details: []models.WorkoutDetail{
+		{DetailName: "sets", DetailValue: "3x10"},
+		{DetailName: "weight", DetailValue: "60kg"},
+	}}
+	got, err = svc.Detect(context.Background(), legacy)
+	require.NoError(t, err)
+	require.Equal(t, 1800.0, kinds(got)[models.RecordVolume])
+}
+
+func TestDetectKeepsOneRecordPerSessionAndRevisesIt(t *testing.T) {
+	ctx := context.Background()
+	repo := &inMemRecordRepo{}
+	svc := use_case.NewRecordService(repo)
+
+	_, err := svc.Detect(ctx, session(1, models.WorkoutSet{Reps: 5, Weight: 100, Unit: models.UnitKilograms}))
+	require.NoError(t, err)
+
+	// warm-up sets ramping up to the top set, detected after each set like the API does
+	var sets []models.WorkoutSet
+	for _, kg := range []float64{60, 80, 105} {
+		sets = append(sets, models.WorkoutSet{Reps: 3, Weight: kg, Unit: models.UnitKilograms})
+		_, err := svc.Detect(ctx, session(2, append([]models.WorkoutSet(nil), sets...)...))
+		require.NoError(t, err)
+	}
+	stored, err := repo.ListBySession(ctx, 2)
+	require.NoError(t, err)
+	k := kinds(stored)
+	require.Len(t, stored, len(k), "one record per slot and session")
+	require.Equal(t, 105.0, k[models.RecordHeaviestWeight])
+	require.Equal(t, 735.0, k[models.RecordVolume])
+	require.Equal(t, 3.0, k[models.RecordMostReps+"@105"])
+
+	// editing the top set down to the old best removes the weight record
+	sets[2].Weight = 100
+	_, err = svc.Detect(ctx, session(2, sets...))
+	require.NoError(t, err)
+	stored, err = repo.ListBySession(ctx, 2)
+	require.NoError(t, err)
+	require.NotContains(t, kinds(stored), models.RecordHeaviestWeight)
+
+	// deleting every set removes the session's records
+	_, err = svc.Detect(ctx, session(2))
+	require.NoError(t, err)
+	stored, err = repo.ListBySession(ctx, 2)
+	require.NoError(t, err)
+	require.Empty(t, stored)
+}
+
+func TestDetectKeepsRecordsBeatenLater(t *testing.T) {
+	ctx := context.Background()
+	repo := &inMemRecordRepo{}
+	svc := use_case.NewRecordService(repo)
+
+	first := session(1, models.WorkoutSet{Reps: 5, Weight: 100, Unit: models.UnitKilograms})
+	_, err := svc.Detect(ctx, first)
+	require.NoError(t, err)
+	second := session(2, models.WorkoutSet{Reps: 5, Weight: 105, Unit: models.UnitKilograms})
+	second.Datetime = first.Datetime.Add(time.Hour)
+	_, err = svc.Detect(ctx, second)
+	require.NoError(t, err)
+
+	// editing the first session leaves the record it set at the time
+	first.Sets = append(first.Sets, models.WorkoutSet{ID: 199, Reps: 3, Weight: 90, Unit: models.UnitKilograms, Completed: true})
+	_, err = svc.Detect(ctx, first)
+	require.NoError(t, err)
+	stored, err := repo.ListBySession(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, 100.0, kinds(stored)[models.RecordHeaviestWeight])
+
+	history, err := svc.History(ctx, 1, 5, 100, nil)
+	require.NoError(t, err)
+	var weights []float64
+	for _, rec := range history {
+		if rec.Kind == models.RecordHeaviestWeight {
+			weights = append(weights, rec.Value)
+		}
+	}
+	require.Equal(t, []float64{100, 105}, weights)
+
+	// while the standing record is still the later one
+	current, err := svc.Current(ctx, 1, 100, nil)
+	require.NoError(t, err)
+	require.Equal(t, 105.0, kinds(current)[models.RecordHeaviestWeight])
+}
@@ -0,0 +1,279 @@
+package use_case
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
+)
+
+// Engines that produce workout suggestions.
+const (
+	EngineLLM   = "llm"
+	EngineRules = "rules"
+)
+
+const (
+	// ruleLookback is how much training history the rules consider.
+	ruleLookback = 28 * 24 * time.Hour
+	// volumeWindow is the window recent volume per muscle group is summed over.
+	volumeWindow = 7 * 24 * time.Hour
+	// minRecovery is the rest a muscle group needs before it is trained again.
+	minRecovery = 48 * time.Hour
+	// maxStreakDays is the number of training days in a row after which a rest day is due.
+	maxStreakDays = 3
+	// ruleExercises is the number of exercises suggested for the chosen muscle group.
+	ruleExercises = 3
+	// ruleRestSeconds is the rest between sets suggested when the user logged none.
+	ruleRestSeconds = 90
+	// maxCatalogTypes caps the workout types the rules choose from.
+	maxCatalogTypes = 500
+)
+
+// ErrNoWorkoutTypes is returned by RuleSuggester.Suggest while the workout type catalog is empty.
+var ErrNoWorkoutTypes = errors.New("no workout types to suggest from")
+
+// RuleSuggestion is a workout suggested by the rule engine: exercises for the muscle group
+// that has rested longest, or a rest day.
+type RuleSuggestion struct {
+	// Rest is set when a rest day is suggested; there are no exercises then.
+	Rest        bool
+	MuscleGroup string
+	Exercises   []SuggestedExercise
+	// Reason explains the choice in a sentence.
+	Reason string
+}
+
+// SuggestedExercise is an exercise of a RuleSuggestion. Sets, reps, weight and rest repeat the
+// user's last performance, or are a beginner's 3x10 without one.
+type SuggestedExercise struct {
+	WorkoutTypeID uint
+	WorkoutType   string
+	Sets          int
+	Reps          int
+	Weight        float64
+	Unit          string
+	RestSeconds   int
+}
+
+// Text renders the suggestion as a few sentences, like the language model's suggestions.
+func (s *RuleSuggestion) Text() string {
+	if s.Rest {
+		return s.Reason
+	}
+	parts := make([]string, len(s.Exercises))
+	for i, e := range s.Exercises {
+		parts[i] = fmt.Sprintf("%s %dx%d", e.WorkoutType, e.Sets, e.Reps)
+		if e.Weight > 0 {
+			parts[i] += " at " + strconv.FormatFloat(e.Weight, 'f', -1, 64) + " " + e.Unit
+		}
+	}
+	return fmt.Sprintf("Train %s today: %s. %s", s.MuscleGroup, strings.Join(parts, ", "), s.Reason)
+}
+
+// RuleSuggester suggests the next workout with fixed rules instead of a language model, so
+// suggestions keep working while the model is unavailable. It picks the muscle group that
+// has rested longest, preferring the one with less volume over the last week on ties, and
+// suggests a rest day after maxStreakDays training days in a row or when no muscle group
+// has recovered yet.
+type RuleSuggester struct {
+	sessionRepo repository.WorkoutSessionRepository
+	typeRepo    repository.WorkoutTypeRepository
+}
+
+// NewRuleSuggester wires the repositories the rules read training history and the workout
+// type catalog from.
+func NewRuleSuggester(sessionRepo repository.WorkoutSessionRepository, typeRepo repository.WorkoutTypeRepository) *RuleSuggester {
+	return &RuleSuggester{sessionRepo: sessionRepo, typeRepo: typeRepo}
+}
+
+// Suggest returns the suggestion for a user at time now. It fails only when the history or the
+// catalog cannot be read, or with ErrNoWorkoutTypes when the catalog is empty.
+func (s *RuleSuggester) Suggest(ctx context.Context, userID uint, now time.Time) (*RuleSuggestion, error) {
+	types, err := s.typeRepo.List(ctx, maxCatalogTypes, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(types) == 0 {
+		return nil, ErrNoWorkoutTypes
+	}
+	sessions, err := s.sessionRepo.ListByUserInRange(ctx, userID, now.Add(-ruleLookback), now.Add(time.Second))
+	if err != nil {
+		return nil, err
+	}
+	return suggestByRules(sessions, types, now), nil
+}
+
+// groupStats is what the rules know about the training of one muscle group.
+type groupStats struct {
+	name        string
+	groupID     uint
+	lastTrained time.Time
+	volumeKg    float64
+	types       []*models.WorkoutType
+}
+
+// lastPerformance is the most recent logged performance of a workout type.
+type lastPerformance struct {
+	at     time.Time
+	sets   int
+	reps   int
+	weight float64
+	unit   string
+	rest   int
+}
+
+// suggestByRules applies the rules to sessions, oldest first, and the workout type catalog.
+func suggestByRules(sessions []*models.WorkoutSession, types []*models.WorkoutType, now time.Time) *RuleSuggestion {
+	if streak := trainingStreak(sessions, now); streak >= maxStreakDays {
+		return &RuleSuggestion{Rest: true, Reason: fmt.Sprintf(
+			"You have trained %d days in a row, so take a rest day with light walking or mobility work.", streak)}
+	}
+
+	groups := make(map[uint]*groupStats)
+	typeGroup := make(map[uint]*groupStats, len(types))
+	for _, wt := range types {
+		g, ok := groups[wt.MuscleGroupID]
+		if !ok {
+			g = &groupStats{groupID: wt.MuscleGroupID, name: "muscle group " + strconv.Itoa(int(wt.MuscleGroupID))}
+			if wt.MuscleGroup != nil {
+				g.name = wt.MuscleGroup.Name
+			}
+			groups[wt.MuscleGroupID] = g
+		}
+		g.types = append(g.types, wt)
+		typeGroup[wt.ID] = g
+	}
+
+	last := make(map[uint]*lastPerformance)
+	for _, session := range sessions {
+		// only exercises with completed sets count as trained; planned and skipped ones do not
+		perf := make(map[uint]*lastPerformance)
+		trained := make(map[uint]bool)
+		for _, ts := range sessionSets(session) {
+			if !ts.set.Completed {
+				continue
+			}
+			trained[ts.typeID] = true
+			p, ok := perf[ts.typeID]
+			if !ok {
+				p = &lastPerformance{at: session.Datetime, unit: ts.set.Unit}
+				perf[ts.typeID] = p
+			}
+			p.sets++
+			// the heaviest set stands for the session's working weight
+			if ts.set.WeightKg() >= p.weightKg() {
+				p.reps, p.weight, p.unit, p.rest = ts.set.Reps, ts.set.Weight, ts.set.Unit, ts.set.RestSeconds
+			}
+			if g := typeGroup[ts.typeID]; g != nil && now.Sub(session.Datetime) < volumeWindow {
+				g.volumeKg += float64(ts.set.Reps) * ts.set.WeightKg()
+			}
+		}
+		for typeID := range trained {
+			if g := typeGroup[typeID]; g != nil && session.Datetime.After(g.lastTrained) {
+				g.lastTrained = session.Datetime
+			}
+		}
+		for typeID, p := range perf {
+			last[typeID] = p
+		}
+	}
+
+	var best *groupStats
+	for _, g := range groups {
+		if best == nil || restedLonger(g, best) {
+			best = g
+		}
+	}
+	if !best.lastTrained.IsZero() && now.Sub(best.lastTrained) < minRecovery {
+		return &RuleSuggestion{Rest: true, Reason: "Every muscle group was trained in the last two days, " +
+			"so take a rest day to recover."}
+	}
+
+	// exercises the user knows come first, most recently done first
+	candidates := append([]*models.WorkoutType(nil), best.types...)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		pi, pj := last[candidates[i].ID], last[candidates[j].ID]
+		if pi == nil || pj == nil {
+			return pi != nil
+		}
+		return pi.at.After(pj.at)
+	})
+	s := &RuleSuggestion{MuscleGroup: best.name, Reason: groupReason(best, now)}
+	for _, wt := range candidates[:min(ruleExercises, len(candidates))] {
+		e := SuggestedExercise{WorkoutTypeID: wt.ID, WorkoutType: wt.Name, Sets: 3, Reps: 10, Unit: models.UnitKilograms, RestSeconds: ruleRestSeconds}
+		if p := last[wt.ID]; p != nil {
+			e.Sets, e.Reps, e.Weight, e.Unit = min(max(p.sets, 3), 5), max(p.reps, 1), p.weight, p.unit
+			if p.rest > 0 {
+				e.RestSeconds = p.rest
+			}
+		}
+		s.Exercises = append(s.Exercises, e)
+	}
+	return s
+}
+
+// restedLonger orders muscle groups for the rules: never trained first, then by the longest
+// rest, then by the least recent volume and finally by ID so the choice is deterministic.
+func restedLonger(a, b *groupStats) bool {
+	switch {
+	case !a.lastTrained.Equal(b.lastTrained):
+		return a.lastTrained.Before(b.lastTrained)
+	case a.volumeKg != b.volumeKg:
+		return a.volumeKg < b.volumeKg
+	default:
+		return a.groupID < b.groupID
+	}
+}
+
+// groupReason explains why a muscle group was chosen.
+func groupReason(g *groupStats, now time.Time) string {
+	if g.lastTrained.IsZero() {
+		return fmt.Sprintf("You have not trained %s in the last four weeks.", g.name)
+	}
+	days := int(now.Sub(g.lastTrained).Hours() / 24)
+	return fmt.Sprintf("You last trained %s %d days ago, longer than any other muscle group, with %s kg of volume in the last week.",
+		g.name, days, strconv.FormatFloat(math.Round(g.volumeKg), 'f', -1, 64))
+}
+
+// trainingStreak counts the consecutive calendar days with sessions that end today or yesterday.
+// Sessions without a completed set, such as drafts started from a plan, do not count.
+func trainingStreak(sessions []*models.WorkoutSession, now time.Time) int {
+	days := make(map[string]bool, len(sessions))
+	for _, s := range sessions {
+		if hasCompletedSet(s) {
+			days[s.Datetime.In(now.Location()).Format(time.DateOnly)] = true
+		}
+	}
+	day := now
+	if !days[day.Format(time.DateOnly)] {
+		day = day.AddDate(0, 0, -1)
+	}
+	streak := 0
+	for days[day.Format(time.DateOnly)] {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak
+}
+
+// hasCompletedSet reports whether any set of the session was completed.
+func hasCompletedSet(session *models.WorkoutSession) bool {
+	for _, ts := range sessionSets(session) {
+		if ts.set.Completed {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *lastPerformance) weightKg() float64 {
+	return models.WorkoutSet{Weight: p.weight, Unit: p.unit}.WeightKg()
+}
@@ -0,0 +1,152 @@
+package use_case_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/VibeTeam/fitness-tracker-backend/shared/pagination"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/models"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/repository"
+	"github.com/VibeTeam/fitness-tracker-backend/workout/use_case"
+)
+
+/* -------------------------------------------------------------------------- */
+/* Read-only session and workout type stubs                                    */
+/* -------------------------------------------------------------------------- */
+
+type stubSessionRepo struct {
+	repository.WorkoutSessionRepository
+	sessions []*models.WorkoutSession
+}
+
+func (r *stubSessionRepo) ListByUserInRange(_ context.Context, _ uint, from, to time.Time) ([]*models.WorkoutSession, error) {
+	var out []*models.WorkoutSession
+	for _, s := range r.sessions {
+		if !s.Datetime.Before(from) && s.Datetime.Before(to) {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+type stubTypeRepo struct {
+	repository.WorkoutTypeRepository
+	types []*models.WorkoutType
+}
+
+func (r *stubTypeRepo) List(context.Context, int, *pagination.Cursor) ([]*models.WorkoutType, error) {
+	return r.types, nil
+}
+
+var (
+	chest = &models.MuscleGroup{ID: 1, Name: "chest"}
+	legs  = &models.MuscleGroup{ID: 2, Name: "legs"}
+	back  = &models.MuscleGroup{ID: 3, Name: "back"}
+
+	catalog = []*models.WorkoutType{
+		{ID: 1, Name: "Bench Press", MuscleGroupID: 1, MuscleGroup: chest},
+		{ID: 2, Name: "Squat", MuscleGroupID: 2, MuscleGroup: legs},
+		{ID: 3, Name: "Leg Press", MuscleGroupID: 2, MuscleGroup: legs},
+		{ID: 4, Name: "Lunge", MuscleGroupID: 2, MuscleGroup: legs},
+		{ID: 5, Name: "Leg Curl", MuscleGroupID: 2, MuscleGroup: legs},
+		{ID: 6, Name: "Row", MuscleGroupID: 3, MuscleGroup: back},
+	}
+)
+
+// trained is a session of one exercise with completed sets of reps x weight kg.
+func trained(at time.Time, typeID uint, reps int, weights ...float64) *models.WorkoutSession {
+	ex := models.WorkoutExercise{ID: typeID, WorkoutTypeID: typeID, Position: 1}
+	for i, w := range weights {
+		ex.Sets = append(ex.Sets, models.WorkoutSet{SetIndex: i + 1, Reps: reps, Weight: w, Unit: models.UnitKilograms, Completed: true})
+	}
+	return &models.WorkoutSession{WorkoutTypeID: typeID, Datetime: at, Exercises: []models.WorkoutExercise{ex}}
+}
+
+func TestRuleSuggesterPicksLongestRestedGroup(t *testing.T) {
+	now := time.Date(2024, 5, 20, 18, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return now.AddDate(0, 0, -n) }
+	sessions := &stubSessionRepo{sessions: []*models.WorkoutSession{
+		trained(day(9), 3, 12, 150, 150, 150, 150), // legs, longest ago
+		trained(day(6), 6, 10, 60, 60, 60),         // back
+		trained(day(2), 1, 8, 80, 80, 80),          // chest
+	}}
+	rules := use_case.NewRuleSuggester(sessions, &stubTypeRepo{types: catalog})
+
+	s, err := rules.Suggest(context.Background(), 1, now)
+	require.NoError(t, err)
+	require.False(t, s.Rest)
+	require.Equal(t, "legs", s.MuscleGroup)
+	require.Len(t, s.Exercises, 3)
+	// the leg press the user did comes first, with the last performance
+	require.Equal(t, use_case.SuggestedExercise{WorkoutTypeID: 3, WorkoutType: "Leg Press", Sets: 4, Reps: 12, Weight: 150, Unit: "kg", RestSeconds: 90}, s.Exercises[0])
+	require.Equal(t, use_case.SuggestedExercise{WorkoutTypeID: 2, WorkoutType: "Squat", Sets: 3, Reps: 10, Unit: "kg", RestSeconds: 90}, s.Exercises[1])
+	require.Contains(t, s.Text(), "Train legs today: Leg Press 4x12 at 150 kg, Squat 3x10, Lunge 3x10.")
+
+	// a group never trained beats every rested one
+	sessions.sessions = sessions.sessions[1:]
+	s, err = rules.Suggest(context.Background(), 1, now)
+	require.NoError(t, err)
+	require.Equal(t, "legs", s.MuscleGroup)
+	require.Contains(t, s.Reason, "not trained legs")
+}
+
+func TestRuleSuggesterRestDays(t *testing.T) {
+	now := time.Date(2024, 5, 20, 18, 0, 0, 0, time.UTC)
+	rules := func(sessions ...*models.WorkoutSession) *use_case.RuleSuggestion {
+		s, err := use_case.NewRuleSuggester(&stubSessionRepo{sessions: sessions}, &stubTypeRepo{types: catalog}).
+			Suggest(context.Background(), 1, now)
+		require.NoError(t, err)
+		return s
+	}
+
+	// three training days in a row, ending yesterday
+	s := rules(trained(now.AddDate(0, 0, -3), 1, 5, 100), trained(now.AddDate(0, 0, -2), 2, 5, 100), trained(now.AddDate(0, 0, -1), 6, 5, 100))
+	require.True(t, s.Rest)
+	require.Empty(t, s.Exercises)
+	require.Contains(t, s.Text(), "3 days in a row")
+
+	// every group trained within two days, but with a day off in between
+	recent := now.Add(-46 * time.Hour)
+	s = rules(trained(recent, 1, 5, 100), trained(recent, 2, 5, 100), trained(now.Add(-time.Hour), 6, 5, 100))
+	require.True(t, s.Rest)
+
+	// a gap breaks the streak
+	s = rules(trained(now.AddDate(0, 0, -4), 1, 5, 100), trained(now.AddDate(0, 0, -3), 2, 5, 100), trained(now.AddDate(0, 0, -1), 2, 5, 100))
+	require.False(t, s.Rest)
+	require.Equal(t, "back", s.MuscleGroup)
+}
+
+func TestRuleSuggesterCountsCompletedSetsOnly(t *testing.T) {
+	now := time.Date(2024, 5, 20, 18, 0, 0, 0, time.UTC)
+	planned := func(at time.Time, typeID uint) *models.WorkoutSession {
+		s := trained(at, typeID, 10, 100, 100, 100)
+		for i := range s.Exercises[0].Sets {
+			s.Exercises[0].Sets[i].Completed = false
+		}
+		return s
+	}
+	sessions := &stubSessionRepo{}
+	rules := use_case.NewRuleSuggester(sessions, &stubTypeRepo{types: catalog})
+
+	// drafts started from a plan neither build a streak nor mark a group as trained
+	sessions.sessions = []*models.WorkoutSession{
+		trained(now.AddDate(0, 0, -9), 3, 12, 150),
+		trained(now.AddDate(0, 0, -8), 6, 10, 60),
+		planned(now.AddDate(0, 0, -3), 1),
+		planned(now.AddDate(0, 0, -2), 2),
+		planned(now.AddDate(0, 0, -1), 6),
+	}
+	s, err := rules.Suggest(context.Background(), 1, now)
+	require.NoError(t, err)
+	require.False(t, s.Rest)
+	require.Equal(t, "chest", s.MuscleGroup)
+	require.Contains(t, s.Reason, "not trained chest")
+
+	// without workout types there is nothing to suggest
+	_, err = use_case.NewRuleSuggester(sessions, &stubTypeRepo{}).Suggest(context.Background(), 1, now)
+	require.True(t, errors.Is(err, use_case.ErrNoWorkoutTypes))
+}